@@ -0,0 +1,153 @@
+// Command apikeyctl manages API keys through the admin endpoints of a running server.
+//
+// Usage:
+//
+//	apikeyctl [-server URL] [-token JWT | -api-key KEY] create -name NAME -scopes s1,s2 [-expires-in 720h]
+//	apikeyctl [-server URL] [-token JWT | -api-key KEY] list
+//	apikeyctl [-server URL] [-token JWT | -api-key KEY] revoke ID
+//
+// The credentials default to the EMPLOYEE_ADMIN_TOKEN and EMPLOYEE_ADMIN_API_KEY environment variables.
+package main
+
+import (
+	"bytes"
+	"employee/models"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+type client struct {
+	server string
+	token  string
+	apiKey string
+}
+
+func main() {
+	var c client
+	flag.StringVar(&c.server, "server", "http://localhost:8080", "base URL of the employee service")
+	flag.StringVar(&c.token, "token", os.Getenv("EMPLOYEE_ADMIN_TOKEN"), "bearer token of an admin")
+	flag.StringVar(&c.apiKey, "api-key", os.Getenv("EMPLOYEE_ADMIN_API_KEY"), "API key with the apikeys:admin scope")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "create":
+		err = c.create(args)
+	case "list":
+		err = c.list()
+	case "revoke":
+		if len(args) != 1 {
+			usage()
+			os.Exit(2)
+		}
+		err = c.revoke(args[0])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "apikeyctl:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: apikeyctl [flags] create -name NAME -scopes s1,s2 [-expires-in DURATION] | list | revoke ID")
+	flag.PrintDefaults()
+}
+
+func (c client) create(args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	name := fs.String("name", "", "human readable name of the key")
+	scopes := fs.String("scopes", "", "comma separated scopes, e.g. employees:read,employees:write")
+	expiresIn := fs.Duration("expires-in", 0, "lifetime of the key, 0 for no expiry")
+	fs.Parse(args)
+
+	req := models.CreateAPIKeyRequest{Name: *name, Scopes: strings.Split(*scopes, ",")}
+	if *expiresIn > 0 {
+		exp := time.Now().Add(*expiresIn).UTC()
+		req.ExpiresAt = &exp
+	}
+
+	var res models.CreateAPIKeyResponse
+	if err := c.do(http.MethodPost, "/admin/apikeys", req, &res); err != nil {
+		return err
+	}
+	fmt.Printf("id:     %s\nscopes: %s\nkey:    %s\n", res.ID, strings.Join(res.Scopes, ","), res.Key)
+	fmt.Fprintln(os.Stderr, "Store the key now, it cannot be shown again.")
+	return nil
+}
+
+func (c client) list() error {
+	var res models.ListAPIKeysResponse
+	if err := c.do(http.MethodGet, "/admin/apikeys", nil, &res); err != nil {
+		return err
+	}
+	for _, k := range res.Keys {
+		fmt.Printf("%s\t%s\t%s\texpires=%s\tlast_used=%s\n",
+			k.ID, k.Name, strings.Join(k.Scopes, ","), formatTime(k.ExpiresAt), formatTime(k.LastUsedAt))
+	}
+	return nil
+}
+
+func (c client) revoke(id string) error {
+	return c.do(http.MethodDelete, "/admin/apikeys/"+url.PathEscape(id), nil, nil)
+}
+
+func (c client) do(method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, strings.TrimRight(c.server, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	} else if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out != nil {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Format(time.RFC3339)
+}
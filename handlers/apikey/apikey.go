@@ -0,0 +1,91 @@
+package apikey
+
+import (
+	"employee/logic/apikey"
+	"employee/logic/authz"
+	"employee/models"
+	"employee/pkg/apierror"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	keys  *apikey.Manager
+	authz *authz.Authorizer
+}
+
+// NewAPIKeyHandler creates the API key admin handler. A nil authorizer permits every request.
+func NewAPIKeyHandler(keys *apikey.Manager, az *authz.Authorizer) *APIKeyHandler {
+	return &APIKeyHandler{
+		keys:  keys,
+		authz: az,
+	}
+}
+
+func (kh *APIKeyHandler) authorize(c *gin.Context) bool {
+	principal, _ := auth.GetPrincipal(c)
+	if _, err := kh.authz.Authorize(c.Request.Context(), principal, authz.ActionManageAPIKeys, ""); err != nil {
+		apiError := err.(*apierror.APIError)
+		apierror.Abort(c, apiError)
+		return false
+	}
+	return true
+}
+
+func (kh *APIKeyHandler) CreateKey(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "CreateKey").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	if !kh.authorize(c) {
+		return
+	}
+
+	var req models.CreateAPIKeyRequest
+	if err := c.BindJSON(&req); err != nil {
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to parse the request body")
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := kh.keys.CreateKey(c.Request.Context(), req)
+	if err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to create API key")
+		apierror.Abort(c, apiError)
+		return
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "CreateKey").Str("keyId", res.ID).Msg("Request processed successfully")
+	c.JSON(http.StatusCreated, res)
+}
+
+func (kh *APIKeyHandler) ListKeys(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "ListKeys").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	if !kh.authorize(c) {
+		return
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "ListKeys").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, models.ListAPIKeysResponse{Keys: kh.keys.ListKeys(c.Request.Context())})
+}
+
+func (kh *APIKeyHandler) RevokeKey(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "RevokeKey").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	if !kh.authorize(c) {
+		return
+	}
+
+	if err := kh.keys.RevokeKey(c.Request.Context(), c.Param("id")); err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to revoke API key")
+		apierror.Abort(c, apiError)
+		return
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "RevokeKey").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, models.APIResponse{Message: "OK"})
+}
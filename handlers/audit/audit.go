@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"employee/logic/audit"
+	"employee/logic/authz"
+	"employee/models"
+	"employee/pkg/apierror"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	trail *audit.Trail
+	authz *authz.Authorizer
+}
+
+// NewAuditHandler creates the audit trail handler. A nil authorizer permits every request.
+func NewAuditHandler(trail *audit.Trail, az *authz.Authorizer) *AuditHandler {
+	return &AuditHandler{
+		trail: trail,
+		authz: az,
+	}
+}
+
+func (ah *AuditHandler) GetAudit(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetAudit").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	empID := c.Param("id")
+	principal, _ := auth.GetPrincipal(c)
+	decision, err := ah.authz.Authorize(c.Request.Context(), principal, authz.ActionReadAudit, empID)
+	if err != nil {
+		apierror.Abort(c, err.(*apierror.APIError))
+		return
+	}
+
+	entries, err := ah.trail.List(c.Request.Context(), empID)
+	if err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to get the audit trail")
+		apierror.Abort(c, apiError)
+		return
+	}
+
+	// Fields the caller may not see are left out of the diffs, the hashes still
+	// cover them
+	if len(decision.HiddenFields) > 0 {
+		for i := range entries {
+			entries[i].Changes = slices.DeleteFunc(entries[i].Changes, func(ch models.FieldChange) bool {
+				return slices.Contains(decision.HiddenFields, ch.Field)
+			})
+		}
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetAudit").Int("entries", len(entries)).Msg("Request processed successfully")
+	c.JSON(http.StatusOK, models.GetAuditResponse{Entries: entries})
+}
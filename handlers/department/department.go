@@ -0,0 +1,152 @@
+package department
+
+import (
+	"employee/logic/authz"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/pkg/apierror"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DepartmentHandler struct {
+	emp   *employee.Employee
+	authz *authz.Authorizer
+}
+
+// NewDepartmentHandler creates the department handler. A nil authorizer permits every request.
+func NewDepartmentHandler(emp *employee.Employee, az *authz.Authorizer) *DepartmentHandler {
+	return &DepartmentHandler{
+		emp:   emp,
+		authz: az,
+	}
+}
+
+// authorize evaluates the authorization policy for the caller and aborts the request on denial.
+func (dh *DepartmentHandler) authorize(c *gin.Context, action authz.Action) (authz.Decision, bool) {
+	principal, _ := auth.GetPrincipal(c)
+	decision, err := dh.authz.Authorize(c.Request.Context(), principal, action, "")
+	if err != nil {
+		apiError := err.(*apierror.APIError)
+		apierror.Abort(c, apiError)
+		return decision, false
+	}
+	return decision, true
+}
+
+func (dh *DepartmentHandler) CreateDepartment(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "CreateDepartment").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	var department models.Department
+	if err := c.BindJSON(&department); err != nil {
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to parse the request body")
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if _, ok := dh.authorize(c, authz.ActionManageDepartments); !ok {
+		return
+	}
+
+	if err := dh.emp.CreateDepartment(c.Request.Context(), department); err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to create department")
+		apierror.Abort(c, apiError)
+		return
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "CreateDepartment").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, models.APIResponse{Message: "OK"})
+}
+
+func (dh *DepartmentHandler) GetDepartments(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetDepartments").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	if _, ok := dh.authorize(c, authz.ActionRead); !ok {
+		return
+	}
+
+	res, err := dh.emp.GetDepartments(c.Request.Context(), c.Param("id"), c.Query("last_eval_id"), c.Query("num_records"))
+	if err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to get departments")
+		apierror.Abort(c, apiError)
+		return
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetDepartments").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, res)
+}
+
+func (dh *DepartmentHandler) UpdateDepartment(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "UpdateDepartment").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	var req models.DepartmentUpdateRequest
+	if err := c.BindJSON(&req); err != nil {
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to parse the request body")
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if _, ok := dh.authorize(c, authz.ActionManageDepartments); !ok {
+		return
+	}
+
+	if err := dh.emp.UpdateDepartment(c.Request.Context(), c.Param("id"), req); err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to update department")
+		apierror.Abort(c, apiError)
+		return
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "UpdateDepartment").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, models.APIResponse{Message: "OK"})
+}
+
+func (dh *DepartmentHandler) DeleteDepartment(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "DeleteDepartment").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	if _, ok := dh.authorize(c, authz.ActionManageDepartments); !ok {
+		return
+	}
+
+	if err := dh.emp.DeleteDepartment(c.Request.Context(), c.Param("id")); err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to delete department")
+		apierror.Abort(c, apiError)
+		return
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "DeleteDepartment").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, models.APIResponse{Message: "OK"})
+}
+
+func (dh *DepartmentHandler) GetRollups(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetRollups").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	decision, ok := dh.authorize(c, authz.ActionRead)
+	if !ok {
+		return
+	}
+
+	res, err := dh.emp.GetDepartmentRollups(c.Request.Context(), c.Param("id"), c.Query("currency"))
+	if err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to get department rollups")
+		apierror.Abort(c, apiError)
+		return
+	}
+	// Payroll figures follow the visibility of the salaries they add up
+	if slices.Contains(decision.HiddenFields, "salary") {
+		for i := range res.Rollups {
+			res.Rollups[i].TotalSalary, res.Rollups[i].AverageSalary = 0, 0
+		}
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetRollups").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, res)
+}
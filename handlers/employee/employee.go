@@ -1,69 +1,261 @@
 package employee
 
 import (
+	"employee/logic/authz"
 	"employee/logic/employee"
 	"employee/models"
 	"employee/pkg/apierror"
+	"employee/pkg/auth"
 	"employee/pkg/logger"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type EmployeeHandler struct {
-	emp *employee.Employee
+	emp   *employee.Employee
+	authz *authz.Authorizer
 }
 
-func NewEmployeeHandler() *EmployeeHandler {
+// NewEmployeeHandler creates the employee handler. A nil authorizer permits every request.
+func NewEmployeeHandler(emp *employee.Employee, az *authz.Authorizer) *EmployeeHandler {
 	return &EmployeeHandler{
-		emp: employee.NewEmployee(),
+		emp:   emp,
+		authz: az,
 	}
 }
 
+// authorize evaluates the authorization policy for the caller and aborts the request on denial.
+func (eh *EmployeeHandler) authorize(c *gin.Context, action authz.Action, target string) (authz.Decision, bool) {
+	principal, _ := auth.GetPrincipal(c)
+	decision, err := eh.authz.Authorize(c.Request.Context(), principal, action, target)
+	if err != nil {
+		apiError := err.(*apierror.APIError)
+		apierror.Abort(c, apiError)
+		return decision, false
+	}
+	return decision, true
+}
+
 func (eh *EmployeeHandler) CreateEmployee(c *gin.Context) {
 
-	logger.Log.Info().Str("method", "CreateEmployee").Msg("Request received")
+	logger.Ctx(c.Request.Context()).Info().Str("method", "CreateEmployee").Str("subject", auth.GetSubject(c)).Msg("Request received")
 
-	var employee models.Employee
+	var req models.CreateEmployeeRequest
 
-	if err := c.BindJSON(&employee); err != nil {
-		logger.Log.Error().Err(err).Msg("Failed to parse the request body")
+	if err := c.BindJSON(&req); err != nil {
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to parse the request body")
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
 	}
 
-	if err := eh.emp.CreateEmployee(employee); err != nil {
+	if _, ok := eh.authorize(c, authz.ActionCreate, strconv.Itoa(req.ID)); !ok {
+		return
+	}
+
+	if err := eh.emp.CreateEmployeeWithJustification(c.Request.Context(), req.Employee, req.SalaryJustification); err != nil {
 		apiError := err.(*apierror.APIError)
-		logger.Log.Error().Err(err).Msg("Failed to create employee")
-		c.AbortWithStatusJSON(apiError.HttpStatusCode, apiError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to create employee")
+		apierror.Abort(c, apiError)
 		return
 	}
 
-	logger.Log.Info().Str("method", "CreateEmployee").Msg("Request processed successfully")
+	logger.Ctx(c.Request.Context()).Info().Str("method", "CreateEmployee").Msg("Request processed successfully")
 	c.JSON(http.StatusOK, models.APIResponse{Message: "OK"})
 }
 
 func (eh *EmployeeHandler) GetEmployee(c *gin.Context) {
-	logger.Log.Info().Str("method", "GetEmployee").Msg("Request received")
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetEmployee").Str("subject", auth.GetSubject(c)).Msg("Request received")
 
 	empID := c.Query("id")
 	LastEvalKeyID := c.Query("last_eval_id")
 	numRecords := c.Query("num_records")
+	asOf := c.Query("as_of")
+	knownAt := c.Query("known_at")
+
+	decision, ok := eh.authorize(c, authz.ActionRead, empID)
+	if !ok {
+		return
+	}
+
 	var res models.GetEmployeeResponse
 	var err error
-	if res, err = eh.emp.GetEmployee(empID, LastEvalKeyID, numRecords); err != nil {
+	if res, err = eh.emp.GetEmployeeAsOf(c.Request.Context(), empID, LastEvalKeyID, numRecords, asOf, knownAt); err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to get employee")
+		apierror.Abort(c, apiError)
+		return
+	}
+	decision.Redact(res.Employees)
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetEmployee").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, res)
+}
+
+func (eh *EmployeeHandler) GetSalaryHistory(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetSalaryHistory").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	empID := c.Param("id")
+
+	decision, ok := eh.authorize(c, authz.ActionRead, empID)
+	if !ok {
+		return
+	}
+	if slices.Contains(decision.HiddenFields, "salary") {
+		logger.Ctx(c.Request.Context()).Error().Str("empId", empID).Msg("Salary is hidden from the caller")
+		apierror.Abort(c, authz.GetAuthzError(authz.FieldHidden))
+		return
+	}
+
+	res, err := eh.emp.GetSalaryHistory(c.Request.Context(), empID)
+	if err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to get salary history")
+		apierror.Abort(c, apiError)
+		return
+	}
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetSalaryHistory").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, res)
+}
+
+func (eh *EmployeeHandler) RestoreEmployee(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "RestoreEmployee").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	empID := c.Param("id")
+
+	// Restoring undoes a delete and needs the same permission
+	if _, ok := eh.authorize(c, authz.ActionDelete, empID); !ok {
+		return
+	}
+
+	if err := eh.emp.RestoreEmployee(c.Request.Context(), empID); err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to restore employee")
+		apierror.Abort(c, apiError)
+		return
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "RestoreEmployee").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, models.APIResponse{Message: "OK"})
+}
+
+func (eh *EmployeeHandler) GetReports(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetReports").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	empID := c.Param("id")
+	recursive := c.Query("recursive") == "true"
+
+	decision, ok := eh.authorize(c, authz.ActionRead, empID)
+	if !ok {
+		return
+	}
+
+	res, err := eh.emp.GetReports(c.Request.Context(), empID, recursive)
+	if err != nil {
 		apiError := err.(*apierror.APIError)
-		logger.Log.Error().Err(err).Msg("Failed to get employee")
-		c.AbortWithStatusJSON(apiError.HttpStatusCode, apiError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to get reports")
+		apierror.Abort(c, apiError)
 		return
 	}
-	logger.Log.Info().Str("method", "GetEmployee").Msg("Request processed successfully")
+	decision.Redact(res.Employees)
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetReports").Msg("Request processed successfully")
 	c.JSON(http.StatusOK, res)
 }
 
+func (eh *EmployeeHandler) GetChain(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetChain").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	empID := c.Param("id")
+
+	decision, ok := eh.authorize(c, authz.ActionRead, empID)
+	if !ok {
+		return
+	}
+
+	res, err := eh.emp.GetChain(c.Request.Context(), empID)
+	if err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to get management chain")
+		apierror.Abort(c, apiError)
+		return
+	}
+	decision.Redact(res.Employees)
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetChain").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, res)
+}
+
+func (eh *EmployeeHandler) GetEmployeeStats(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetEmployeeStats").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	decision, ok := eh.authorize(c, authz.ActionRead, "")
+	if !ok {
+		return
+	}
+
+	req := models.EmployeeStatsRequest{
+		GroupBy:    c.Query("group_by"),
+		Position:   c.Query("position"),
+		Department: c.Query("department"),
+		Status:     c.Query("status"),
+		Currency:   c.Query("currency"),
+	}
+	if percentiles := c.Query("percentiles"); percentiles != "" {
+		req.Percentiles = strings.Split(percentiles, ",")
+	}
+	res, err := eh.emp.GetEmployeeStats(c.Request.Context(), req)
+	if err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to get employee stats")
+		apierror.Abort(c, apiError)
+		return
+	}
+	// Salary statistics follow the visibility of the salaries they summarize
+	if slices.Contains(decision.HiddenFields, "salary") {
+		for i := range res.Stats {
+			res.Stats[i].Sum, res.Stats[i].Mean, res.Stats[i].Median, res.Stats[i].Percentiles = 0, 0, 0, nil
+		}
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetEmployeeStats").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, res)
+}
+
+func (eh *EmployeeHandler) MoveReports(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "MoveReports").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	empID := c.Param("id")
+
+	var req models.MoveReportsRequest
+	if err := c.BindJSON(&req); err != nil {
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to parse the request body")
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	// The reports are moved to the new manager, who must be in the caller's scope too
+	if _, ok := eh.authorize(c, authz.ActionUpdate, empID); !ok {
+		return
+	}
+	if _, ok := eh.authorize(c, authz.ActionUpdate, strconv.Itoa(req.ManagerID)); !ok {
+		return
+	}
+
+	moved, err := eh.emp.MoveReports(c.Request.Context(), empID, req.ManagerID)
+	if err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to move reports")
+		apierror.Abort(c, apiError)
+		return
+	}
+	logger.Ctx(c.Request.Context()).Info().Str("method", "MoveReports").Int("moved", len(moved)).Msg("Request processed successfully")
+	c.JSON(http.StatusOK, models.MoveReportsResponse{Moved: moved})
+}
+
 func (eh *EmployeeHandler) UpdateEmployee(c *gin.Context) {
 
-	logger.Log.Info().Str("method", "UpdateEmployee").Msg("Request received")
+	logger.Ctx(c.Request.Context()).Info().Str("method", "UpdateEmployee").Str("subject", auth.GetSubject(c)).Msg("Request received")
 
 	empID := c.Query("id")
 
@@ -74,31 +266,39 @@ func (eh *EmployeeHandler) UpdateEmployee(c *gin.Context) {
 		return
 	}
 
-	if err := eh.emp.UpdateEmployee(empID, employee); err != nil {
+	if _, ok := eh.authorize(c, authz.ActionUpdate, empID); !ok {
+		return
+	}
+
+	if err := eh.emp.UpdateEmployee(c.Request.Context(), empID, employee); err != nil {
 		apiError := err.(*apierror.APIError)
-		logger.Log.Error().Err(err).Msg("Failed to update employee")
-		c.AbortWithStatusJSON(apiError.HttpStatusCode, apiError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to update employee")
+		apierror.Abort(c, apiError)
 		return
 	}
 
-	logger.Log.Info().Str("method", "UpdateEmployee").Msg("Request processed successfully")
+	logger.Ctx(c.Request.Context()).Info().Str("method", "UpdateEmployee").Msg("Request processed successfully")
 
 	c.JSON(http.StatusOK, models.APIResponse{Message: "OK"})
 }
 
 func (eh *EmployeeHandler) DeleteEmployee(c *gin.Context) {
 
-	logger.Log.Info().Str("method", "DeleteEmployee").Msg("Request received")
+	logger.Ctx(c.Request.Context()).Info().Str("method", "DeleteEmployee").Str("subject", auth.GetSubject(c)).Msg("Request received")
 
 	empID := c.Query("id")
 
-	if err := eh.emp.DeleteEmployee(empID); err != nil {
-		logger.Log.Error().Err(err).Msg("Failed to delete employee")
+	if _, ok := eh.authorize(c, authz.ActionDelete, empID); !ok {
+		return
+	}
+
+	if err := eh.emp.DeleteEmployee(c.Request.Context(), empID); err != nil {
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to delete employee")
 		apiError := err.(*apierror.APIError)
-		c.AbortWithStatusJSON(apiError.HttpStatusCode, apiError)
+		apierror.Abort(c, apiError)
 		return
 	}
 
-	logger.Log.Info().Str("method", "DeleteEmployee").Msg("Request processed successfully")
+	logger.Ctx(c.Request.Context()).Info().Str("method", "DeleteEmployee").Msg("Request processed successfully")
 	c.JSON(http.StatusOK, models.APIResponse{Message: "OK"})
 }
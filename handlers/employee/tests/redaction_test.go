@@ -0,0 +1,125 @@
+package employee_test
+
+import (
+	"bytes"
+	"employee/logic/authz"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"employee/pkg/testhelpers"
+	"employee/service/router"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Salary redaction", func() {
+	var (
+		secret = []byte("0123456789abcdef0123456789abcdef")
+		r      *gin.Engine
+	)
+
+	tokenFor := func(role string) string {
+		return "Bearer " + testhelpers.SignHS256(secret, "", map[string]any{
+			"sub": role + "-user", "roles": []string{role}, "exp": time.Now().Add(time.Hour).Unix(),
+		})
+	}
+
+	getEmployees := func(role string) []map[string]any {
+		req, _ := http.NewRequest("GET", "/employee", nil)
+		req.Header.Set("Authorization", tokenFor(role))
+		var body struct {
+			Employees []map[string]any `json:"employees"`
+		}
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusOK && json.Unmarshal(w.Body.Bytes(), &body) == nil
+		})
+		Expect(res).To(Equal(true))
+		return body.Employees
+	}
+
+	BeforeEach(func() {
+		v, err := auth.NewVerifier(auth.Config{HMACSecret: secret})
+		Expect(err).To(BeNil())
+		r = router.NewRouter(router.WithAuthentication(v), router.WithAuthorization(authz.NewAuthorizer(authz.DefaultPolicy())))
+
+		req, _ := http.NewRequest("POST", "/employee", bytes.NewBufferString(`{"id":1,"name":"John Doe","position":"Developer","salary":50000}`))
+		req.Header.Set("Authorization", tokenFor("hr-admin"))
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusOK
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	When("a viewer reads employees", func() {
+		It("returns the records without the salary field", func() {
+			emps := getEmployees("viewer")
+			Expect(emps).To(HaveLen(1))
+			Expect(emps[0]).To(HaveKeyWithValue("name", "John Doe"))
+			Expect(emps[0]).NotTo(HaveKey("salary"))
+		})
+
+		It("does not leak the salary in the raw response body", func() {
+			req, _ := http.NewRequest("GET", "/employee?id=1", nil)
+			req.Header.Set("Authorization", tokenFor("viewer"))
+			res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+				return w.Code == http.StatusOK && !bytes.Contains(w.Body.Bytes(), []byte("50000"))
+			})
+			Expect(res).To(Equal(true))
+		})
+	})
+
+	When("an hr-admin reads employees", func() {
+		It("returns the salary field", func() {
+			emps := getEmployees("hr-admin")
+			Expect(emps).To(HaveLen(1))
+			Expect(emps[0]).To(HaveKeyWithValue("salary", "50000"))
+		})
+	})
+
+	When("a salary is rejected", func() {
+		It("keeps the amount out of the logs", func() {
+			sink := filepath.Join(GinkgoT().TempDir(), "json.log")
+			previous, level := logger.Log, zerolog.GlobalLevel()
+			DeferCleanup(func() {
+				logger.Log = previous
+				zerolog.SetGlobalLevel(level)
+			})
+			closer, err := logger.Init(logger.Options{Level: "Debug", Format: logger.FormatJSON, Outputs: []string{sink}})
+			Expect(err).To(BeNil())
+
+			for _, salary := range []string{`"98765.123456"`, `98765.123456`, `"98765x"`, `98765e30`} {
+				req, _ := http.NewRequest("POST", "/employee", bytes.NewBufferString(`{"id":2,"name":"Jane Doe","position":"Manager","salary":`+salary+`}`))
+				req.Header.Set("Authorization", tokenFor("hr-admin"))
+				res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+					return w.Code == http.StatusBadRequest
+				})
+				Expect(res).To(Equal(true), salary)
+			}
+			Expect(closer.Close()).To(Succeed())
+
+			data, err := os.ReadFile(sink)
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(ContainSubstring("cannot have more than 4 decimals"))
+			Expect(string(data)).NotTo(ContainSubstring("98765"))
+		})
+	})
+
+	When("a viewer tries to create an employee", func() {
+		It("returns 403", func() {
+			req, _ := http.NewRequest("POST", "/employee", bytes.NewBufferString(`{"id":2,"name":"Jane Doe","position":"Manager","salary":80000}`))
+			req.Header.Set("Authorization", tokenFor("viewer"))
+			res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+				return w.Code == http.StatusForbidden
+			})
+			Expect(res).To(Equal(true))
+		})
+	})
+})
@@ -0,0 +1,39 @@
+package health
+
+import (
+	"employee/service/health"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+	}
+}
+
+func (hh *HealthHandler) Livez(c *gin.Context) {
+	respond(c, hh.checker.Liveness(c.Request.Context()))
+}
+
+func (hh *HealthHandler) Readyz(c *gin.Context) {
+	respond(c, hh.checker.Readiness(c.Request.Context()))
+}
+
+func (hh *HealthHandler) Healthz(c *gin.Context) {
+	respond(c, hh.checker.Health(c.Request.Context()))
+}
+
+func respond(c *gin.Context, report health.Report) {
+	c.Header("Cache-Control", "no-store")
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
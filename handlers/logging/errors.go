@@ -0,0 +1,20 @@
+package logging
+
+import (
+	"employee/pkg/apierror"
+	"net/http"
+)
+
+func GetLoggingError(c LoggingError) *apierror.APIError {
+	return LoggingErrors[c]
+}
+
+type LoggingError int
+
+const (
+	InvalidLogLevel LoggingError = iota + 100500
+)
+
+var LoggingErrors = map[LoggingError]*apierror.APIError{
+	InvalidLogLevel: {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidLogLevel), ErrorMessage: "Log level must be one of Debug, Info, Warn or Error"},
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"employee/logic/authz"
+	"employee/models"
+	"employee/pkg/apierror"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoggingHandler struct {
+	authz *authz.Authorizer
+}
+
+// NewLoggingHandler creates the log level admin handler. A nil authorizer permits every request.
+func NewLoggingHandler(az *authz.Authorizer) *LoggingHandler {
+	return &LoggingHandler{
+		authz: az,
+	}
+}
+
+func (lh *LoggingHandler) authorize(c *gin.Context) bool {
+	principal, _ := auth.GetPrincipal(c)
+	if _, err := lh.authz.Authorize(c.Request.Context(), principal, authz.ActionManageLogging, ""); err != nil {
+		apiError := err.(*apierror.APIError)
+		apierror.Abort(c, apiError)
+		return false
+	}
+	return true
+}
+
+func (lh *LoggingHandler) GetLevel(c *gin.Context) {
+	if !lh.authorize(c) {
+		return
+	}
+	c.JSON(http.StatusOK, models.LogLevel{Level: logger.GetLevel()})
+}
+
+func (lh *LoggingHandler) SetLevel(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "SetLevel").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	if !lh.authorize(c) {
+		return
+	}
+
+	var req models.LogLevel
+	if err := c.BindJSON(&req); err != nil {
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to parse the request body")
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	previous := logger.GetLevel()
+	if err := logger.SetLevel(req.Level); err != nil {
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Invalid log level")
+		apierror.Abort(c, GetLoggingError(InvalidLogLevel))
+		return
+	}
+
+	// Logged at Warn so the change is visible whatever the new level is
+	logger.Ctx(c.Request.Context()).Warn().Str("from", previous).Str("to", logger.GetLevel()).Msg("Log level changed")
+	c.JSON(http.StatusOK, models.LogLevel{Level: logger.GetLevel()})
+}
@@ -0,0 +1,133 @@
+package position
+
+import (
+	"employee/logic/authz"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/pkg/apierror"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PositionHandler struct {
+	emp   *employee.Employee
+	authz *authz.Authorizer
+}
+
+// NewPositionHandler creates the position handler. A nil authorizer permits every request.
+func NewPositionHandler(emp *employee.Employee, az *authz.Authorizer) *PositionHandler {
+	return &PositionHandler{
+		emp:   emp,
+		authz: az,
+	}
+}
+
+// authorize evaluates the authorization policy for the caller and aborts the request on denial.
+func (ph *PositionHandler) authorize(c *gin.Context, action authz.Action) (authz.Decision, bool) {
+	principal, _ := auth.GetPrincipal(c)
+	decision, err := ph.authz.Authorize(c.Request.Context(), principal, action, "")
+	if err != nil {
+		apiError := err.(*apierror.APIError)
+		apierror.Abort(c, apiError)
+		return decision, false
+	}
+	return decision, true
+}
+
+func (ph *PositionHandler) CreatePosition(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "CreatePosition").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	var position models.Position
+	if err := c.BindJSON(&position); err != nil {
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to parse the request body")
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if _, ok := ph.authorize(c, authz.ActionManagePositions); !ok {
+		return
+	}
+
+	if err := ph.emp.CreatePosition(c.Request.Context(), position); err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to create position")
+		apierror.Abort(c, apiError)
+		return
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "CreatePosition").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, models.APIResponse{Message: "OK"})
+}
+
+func (ph *PositionHandler) GetPositions(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetPositions").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	decision, ok := ph.authorize(c, authz.ActionRead)
+	if !ok {
+		return
+	}
+
+	res, err := ph.emp.GetPositions(c.Request.Context(), c.Param("title"), c.Query("last_eval_id"), c.Query("num_records"))
+	if err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to get positions")
+		apierror.Abort(c, apiError)
+		return
+	}
+	// Salary bands follow the visibility of the salaries they bound
+	if slices.Contains(decision.HiddenFields, "salary") {
+		for i := range res.Positions {
+			res.Positions[i].MinSalary, res.Positions[i].MidSalary, res.Positions[i].MaxSalary = 0, 0, 0
+		}
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "GetPositions").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, res)
+}
+
+func (ph *PositionHandler) UpdatePosition(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "UpdatePosition").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	var req models.PositionUpdateRequest
+	if err := c.BindJSON(&req); err != nil {
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to parse the request body")
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if _, ok := ph.authorize(c, authz.ActionManagePositions); !ok {
+		return
+	}
+
+	if err := ph.emp.UpdatePosition(c.Request.Context(), c.Param("title"), req); err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to update position")
+		apierror.Abort(c, apiError)
+		return
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "UpdatePosition").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, models.APIResponse{Message: "OK"})
+}
+
+func (ph *PositionHandler) DeletePosition(c *gin.Context) {
+	logger.Ctx(c.Request.Context()).Info().Str("method", "DeletePosition").Str("subject", auth.GetSubject(c)).Msg("Request received")
+
+	if _, ok := ph.authorize(c, authz.ActionManagePositions); !ok {
+		return
+	}
+
+	if err := ph.emp.DeletePosition(c.Request.Context(), c.Param("title")); err != nil {
+		apiError := err.(*apierror.APIError)
+		logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Failed to delete position")
+		apierror.Abort(c, apiError)
+		return
+	}
+
+	logger.Ctx(c.Request.Context()).Info().Str("method", "DeletePosition").Msg("Request processed successfully")
+	c.JSON(http.StatusOK, models.APIResponse{Message: "OK"})
+}
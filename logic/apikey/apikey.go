@@ -0,0 +1,203 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"employee/models"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"employee/service/simpledb"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+// keyPrefix marks raw keys so they are easy to recognise in configuration and secret scanners.
+const keyPrefix = "emk"
+
+// LastUsedInterval is how stale the recorded last use of a key may get. Uses closer
+// together are not written to the store, so that reads do not each cost a write.
+const LastUsedInterval = time.Minute
+
+type Manager struct {
+	db  *simpledb.Database[string, models.StoredAPIKey]
+	now func() time.Time
+}
+
+// NewManager creates a new API key manager with an empty key store.
+//
+// It returns a pointer to the newly created Manager struct.
+func NewManager() *Manager {
+	var d simpledb.Database[string, models.StoredAPIKey]
+	return NewManagerWithDB(d.Init())
+}
+
+// NewManagerWithDB creates a new API key manager backed by the given database.
+func NewManagerWithDB(db *simpledb.Database[string, models.StoredAPIKey]) *Manager {
+	return &Manager{
+		db:  db,
+		now: time.Now,
+	}
+}
+
+// SetClock overrides the time source used for expiry and last-used tracking.
+func (m *Manager) SetClock(now func() time.Time) {
+	m.now = now
+}
+
+// CreateKey generates a new API key. Only a hash of its secret is stored.
+//
+// It returns the key metadata together with the raw key, which cannot be recovered later.
+func (m *Manager) CreateKey(ctx context.Context, req models.CreateAPIKeyRequest) (models.CreateAPIKeyResponse, error) {
+	logger.Ctx(ctx).Debug().Str("name", req.Name).Strs("scopes", req.Scopes).Msg("Create key request received")
+
+	var res models.CreateAPIKeyResponse
+	if req.Name == "" || len(req.Name) > 100 {
+		logger.Ctx(ctx).Error().Str("name", req.Name).Msg("Invalid key name")
+		return res, GetKeyError(InvalidKeyName)
+	}
+	if !validScopes(req.Scopes) {
+		logger.Ctx(ctx).Error().Strs("scopes", req.Scopes).Msg("Invalid key scopes")
+		return res, GetKeyError(InvalidScope)
+	}
+	now := m.now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		logger.Ctx(ctx).Error().Time("expiresAt", *req.ExpiresAt).Msg("Invalid key expiry")
+		return res, GetKeyError(InvalidExpiry)
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error generating key ID")
+		return res, GetKeyError(ErrorCreatingKey)
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error generating key secret")
+		return res, GetKeyError(ErrorCreatingKey)
+	}
+
+	key := models.StoredAPIKey{
+		APIKey: models.APIKey{
+			ID:        id,
+			Name:      req.Name,
+			Scopes:    req.Scopes,
+			CreatedAt: now,
+			ExpiresAt: req.ExpiresAt,
+		},
+		HashedSecret: hashSecret(secret),
+	}
+	if err := m.db.SetItem(ctx, id, key); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error storing key")
+		return res, GetKeyError(ErrorCreatingKey)
+	}
+
+	logger.Ctx(ctx).Debug().Str("keyId", id).Msg("Request processed successfully")
+	res.APIKey = key.APIKey
+	res.Key = keyPrefix + "_" + id + "_" + secret
+	return res, nil
+}
+
+// ListKeys returns the metadata of every key.
+func (m *Manager) ListKeys(ctx context.Context) []models.APIKey {
+	keys := []models.APIKey{}
+	var last string
+	for {
+		batch, next, err := m.db.GetItems(ctx, last, 100)
+		if err != nil {
+			logger.Ctx(ctx).Error().Err(err).Msg("Error listing keys")
+			return keys
+		}
+		for _, k := range batch {
+			keys = append(keys, k.APIKey)
+		}
+		if next == "" {
+			return keys
+		}
+		last = next
+	}
+}
+
+// RevokeKey deletes the key with the given ID, invalidating it immediately.
+func (m *Manager) RevokeKey(ctx context.Context, id string) error {
+	logger.Ctx(ctx).Debug().Str("keyId", id).Msg("Revoke key request received")
+
+	if err := m.db.DeleteItem(ctx, id); err != nil {
+		if errors.Is(err, simpledb.KeyAbsent) {
+			logger.Ctx(ctx).Error().Str("keyId", id).Msg("Key not found")
+			return GetKeyError(KeyNotFound)
+		}
+		logger.Ctx(ctx).Error().Err(err).Msg("Error revoking key")
+		return GetKeyError(ErrorRevokingKey)
+	}
+	return nil
+}
+
+// Authenticate verifies a raw API key and records its use, at most once every
+// LastUsedInterval.
+//
+// It returns the principal of the key, or an *apierror.APIError if the key is unknown or expired.
+func (m *Manager) Authenticate(ctx context.Context, raw string) (*auth.Principal, error) {
+	parts := strings.Split(raw, "_")
+	if len(parts) != 3 || parts[0] != keyPrefix {
+		return nil, GetKeyError(InvalidAPIKey)
+	}
+	id, secret := parts[1], parts[2]
+
+	key, ok := m.db.GetItem(ctx, id)
+	if !ok || subtle.ConstantTimeCompare([]byte(key.HashedSecret), []byte(hashSecret(secret))) != 1 {
+		logger.Ctx(ctx).Error().Str("keyId", id).Msg("Invalid API key")
+		return nil, GetKeyError(InvalidAPIKey)
+	}
+	now := m.now()
+	if key.ExpiresAt != nil && !now.Before(*key.ExpiresAt) {
+		logger.Ctx(ctx).Error().Str("keyId", id).Msg("API key expired")
+		return nil, GetKeyError(APIKeyExpired)
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= LastUsedInterval {
+		key.LastUsedAt = &now
+		if err := m.db.UpdateItem(ctx, id, key); err != nil {
+			// The key was revoked concurrently
+			logger.Ctx(ctx).Error().Err(err).Str("keyId", id).Msg("Error recording key use")
+			return nil, GetKeyError(InvalidAPIKey)
+		}
+	}
+
+	return &auth.Principal{Subject: "apikey:" + id, Method: auth.MethodAPIKey, Scopes: key.Scopes}, nil
+}
+
+func validScopes(scopes []string) bool {
+	if len(scopes) == 0 {
+		return false
+	}
+	for _, s := range scopes {
+		known := false
+		for _, k := range auth.KnownScopes {
+			if s == k {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return false
+		}
+	}
+	return true
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
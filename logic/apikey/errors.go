@@ -0,0 +1,34 @@
+package apikey
+
+import (
+	"employee/pkg/apierror"
+	"net/http"
+)
+
+func GetKeyError(c KeyError) *apierror.APIError {
+	return KeyErrors[c]
+}
+
+type KeyError int
+
+const (
+	InvalidKeyName KeyError = iota + 100300
+	InvalidScope
+	InvalidExpiry
+	ErrorCreatingKey
+	KeyNotFound
+	ErrorRevokingKey
+	InvalidAPIKey
+	APIKeyExpired
+)
+
+var KeyErrors = map[KeyError]*apierror.APIError{
+	InvalidKeyName:   {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidKeyName), ErrorMessage: "Key name cannot be empty or longer than 100 characters"},
+	InvalidScope:     {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidScope), ErrorMessage: "Provide at least one valid scope"},
+	InvalidExpiry:    {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidExpiry), ErrorMessage: "Expiry must be in the future"},
+	ErrorCreatingKey: {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorCreatingKey), ErrorMessage: "Error creating API key"},
+	KeyNotFound:      {HttpStatusCode: http.StatusNotFound, ErrCode: int(KeyNotFound), ErrorMessage: "API key not found"},
+	ErrorRevokingKey: {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorRevokingKey), ErrorMessage: "Error revoking API key"},
+	InvalidAPIKey:    {HttpStatusCode: http.StatusUnauthorized, ErrCode: int(InvalidAPIKey), ErrorMessage: "Invalid API key"},
+	APIKeyExpired:    {HttpStatusCode: http.StatusUnauthorized, ErrCode: int(APIKeyExpired), ErrorMessage: "API key has expired"},
+}
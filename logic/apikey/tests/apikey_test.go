@@ -0,0 +1,120 @@
+package apikey_test
+
+import (
+	"context"
+	"employee/logic/apikey"
+	"employee/models"
+	"employee/pkg/auth"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manager", func() {
+	var (
+		m   *apikey.Manager
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		m = apikey.NewManager()
+		now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		m.SetClock(func() time.Time { return now })
+	})
+
+	Context("CreateKey function", func() {
+		It("should return the raw key once and store only its hash", func() {
+			res, err := m.CreateKey(context.Background(), models.CreateAPIKeyRequest{Name: "payroll-sync", Scopes: []string{auth.ScopeEmployeesRead}})
+			Expect(err).To(BeNil())
+			Expect(res.Key).NotTo(BeEmpty())
+			Expect(strings.Split(res.Key, "_")).To(HaveLen(3))
+
+			keys := m.ListKeys(context.Background())
+			Expect(keys).To(HaveLen(1))
+			Expect(keys[0].ID).To(Equal(res.ID))
+		})
+
+		It("should reject an unknown scope", func() {
+			_, err := m.CreateKey(context.Background(), models.CreateAPIKeyRequest{Name: "job", Scopes: []string{"employees:everything"}})
+			Expect(err).To(Equal(apikey.GetKeyError(apikey.InvalidScope)))
+		})
+
+		It("should reject an empty name", func() {
+			_, err := m.CreateKey(context.Background(), models.CreateAPIKeyRequest{Scopes: []string{auth.ScopeEmployeesRead}})
+			Expect(err).To(Equal(apikey.GetKeyError(apikey.InvalidKeyName)))
+		})
+
+		It("should reject an expiry in the past", func() {
+			past := now.Add(-time.Hour)
+			_, err := m.CreateKey(context.Background(), models.CreateAPIKeyRequest{Name: "job", Scopes: []string{auth.ScopeEmployeesRead}, ExpiresAt: &past})
+			Expect(err).To(Equal(apikey.GetKeyError(apikey.InvalidExpiry)))
+		})
+	})
+
+	Context("Authenticate function", func() {
+		It("should return a principal with the key scopes and record the use", func() {
+			res, err := m.CreateKey(context.Background(), models.CreateAPIKeyRequest{Name: "job", Scopes: []string{auth.ScopeEmployeesWrite}})
+			Expect(err).To(BeNil())
+
+			p, err := m.Authenticate(context.Background(), res.Key)
+			Expect(err).To(BeNil())
+			Expect(p.Method).To(Equal(auth.MethodAPIKey))
+			Expect(p.Scopes).To(ConsistOf(auth.ScopeEmployeesWrite))
+
+			keys := m.ListKeys(context.Background())
+			Expect(keys[0].LastUsedAt).NotTo(BeNil())
+			Expect(*keys[0].LastUsedAt).To(Equal(now))
+		})
+
+		It("should record uses at most once every interval", func() {
+			res, err := m.CreateKey(context.Background(), models.CreateAPIKeyRequest{Name: "job", Scopes: []string{auth.ScopeEmployeesRead}})
+			Expect(err).To(BeNil())
+			first := now
+			_, err = m.Authenticate(context.Background(), res.Key)
+			Expect(err).To(BeNil())
+
+			now = first.Add(apikey.LastUsedInterval - time.Second)
+			_, err = m.Authenticate(context.Background(), res.Key)
+			Expect(err).To(BeNil())
+			Expect(*m.ListKeys(context.Background())[0].LastUsedAt).To(Equal(first))
+
+			now = first.Add(apikey.LastUsedInterval)
+			_, err = m.Authenticate(context.Background(), res.Key)
+			Expect(err).To(BeNil())
+			Expect(*m.ListKeys(context.Background())[0].LastUsedAt).To(Equal(now))
+		})
+
+		It("should reject a key with a wrong secret", func() {
+			res, err := m.CreateKey(context.Background(), models.CreateAPIKeyRequest{Name: "job", Scopes: []string{auth.ScopeEmployeesRead}})
+			Expect(err).To(BeNil())
+
+			_, err = m.Authenticate(context.Background(), "emk_"+res.ID+"_deadbeef")
+			Expect(err).To(Equal(apikey.GetKeyError(apikey.InvalidAPIKey)))
+		})
+
+		It("should reject an expired key", func() {
+			exp := now.Add(time.Hour)
+			res, err := m.CreateKey(context.Background(), models.CreateAPIKeyRequest{Name: "job", Scopes: []string{auth.ScopeEmployeesRead}, ExpiresAt: &exp})
+			Expect(err).To(BeNil())
+
+			now = now.Add(2 * time.Hour)
+			_, err = m.Authenticate(context.Background(), res.Key)
+			Expect(err).To(Equal(apikey.GetKeyError(apikey.APIKeyExpired)))
+		})
+
+		It("should reject a revoked key", func() {
+			res, err := m.CreateKey(context.Background(), models.CreateAPIKeyRequest{Name: "job", Scopes: []string{auth.ScopeEmployeesRead}})
+			Expect(err).To(BeNil())
+			Expect(m.RevokeKey(context.Background(), res.ID)).To(BeNil())
+
+			_, err = m.Authenticate(context.Background(), res.Key)
+			Expect(err).To(Equal(apikey.GetKeyError(apikey.InvalidAPIKey)))
+		})
+	})
+
+	It("should return KeyNotFound when revoking an unknown key", func() {
+		Expect(m.RevokeKey(context.Background(), "missing")).To(Equal(apikey.GetKeyError(apikey.KeyNotFound)))
+	})
+})
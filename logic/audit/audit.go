@@ -0,0 +1,303 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"employee/models"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"employee/pkg/tracing"
+	"employee/service/simpledb"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AnonymousActor is recorded for mutations made without authentication.
+const AnonymousActor = "anonymous"
+
+// pageSize is the number of entries read at a time when scanning the trail.
+const pageSize = 256
+
+// Trail is the append-only, hash-chained audit trail of employee mutations. Entries
+// are only ever added, never updated or deleted.
+type Trail struct {
+	db         *simpledb.Database[uint64, models.AuditEntry]
+	mutex      sync.Mutex
+	loaded     bool
+	lastSeq    uint64
+	lastHash   string
+	byEmployee map[int][]uint64
+	// broken is the first broken link found, after which no entry is appended
+	broken error
+	now    func() time.Time
+}
+
+// NewTrail creates an in-memory audit trail.
+//
+// It returns a pointer to the newly created Trail struct.
+func NewTrail() *Trail {
+	var d simpledb.Database[uint64, models.AuditEntry]
+	return NewTrailWithDB(d.Init())
+}
+
+// NewTrailWithDB creates an audit trail backed by the given database. The existing
+// entries are read on first use, so the database may still be loading.
+func NewTrailWithDB(db *simpledb.Database[uint64, models.AuditEntry]) *Trail {
+	return &Trail{db: db, byEmployee: map[int][]uint64{}, now: time.Now}
+}
+
+// SetClock replaces the clock used to timestamp entries, for tests.
+func (t *Trail) SetClock(now func() time.Time) {
+	t.now = now
+}
+
+// Record appends an entry for a mutation of employee id from before to after. The
+// actor and request ID are taken from ctx. before is nil on create and after on delete.
+//
+// It returns an AuditChainBroken error, recording nothing, once the trail has failed
+// verification.
+func (t *Trail) Record(ctx context.Context, id int, action string, before, after any) error {
+	ctx, span := tracing.Start(ctx, "audit.Record")
+	defer span.End()
+
+	changes, err := Diff(before, after)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to compute the audit diff")
+		span.SetError(err)
+		return GetAuditError(ErrorRecordingAudit)
+	}
+	actor := AnonymousActor
+	if p, ok := auth.PrincipalFromContext(ctx); ok {
+		actor = p.Subject
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if err := t.load(ctx); err != nil {
+		span.SetError(err)
+		return GetAuditError(ErrorRecordingAudit)
+	}
+	if t.broken != nil {
+		logger.Ctx(ctx).Error().Err(t.broken).Int("empId", id).Str("action", action).Msg("Refusing to append to a broken audit trail")
+		span.SetError(t.broken)
+		return GetAuditError(AuditChainBroken)
+	}
+
+	entry := models.AuditEntry{
+		Seq:        t.lastSeq + 1,
+		EmployeeID: id,
+		Action:     action,
+		Actor:      actor,
+		RequestID:  logger.RequestID(ctx),
+		Timestamp:  t.now().UTC().Truncate(time.Microsecond),
+		Changes:    changes,
+		PrevHash:   t.lastHash,
+	}
+	if entry.Hash, err = Hash(entry); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to hash the audit entry")
+		span.SetError(err)
+		return GetAuditError(ErrorRecordingAudit)
+	}
+	if err := t.db.SetItem(ctx, entry.Seq, entry); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to store the audit entry")
+		span.SetError(err)
+		return GetAuditError(ErrorRecordingAudit)
+	}
+	t.append(entry)
+	logger.Ctx(ctx).Debug().Uint64("seq", entry.Seq).Int("empId", id).Str("action", action).Msg("Audit entry recorded")
+	return nil
+}
+
+// List returns the entries of employee empID, oldest first.
+func (t *Trail) List(ctx context.Context, empID string) ([]models.AuditEntry, error) {
+	ctx, span := tracing.Start(ctx, "audit.List")
+	defer span.End()
+
+	id, err := strconv.Atoi(empID)
+	if err != nil || id == 0 {
+		logger.Ctx(ctx).Error().Str("empId", empID).Msg("Invalid employee ID")
+		return nil, GetAuditError(InvalidEmployeeID)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if err := t.load(ctx); err != nil {
+		span.SetError(err)
+		return nil, GetAuditError(ErrorReadingAudit)
+	}
+	entries := []models.AuditEntry{}
+	for _, seq := range t.byEmployee[id] {
+		entry, ok := t.db.GetItem(ctx, seq)
+		if !ok {
+			logger.Ctx(ctx).Error().Uint64("seq", seq).Msg("Audit entry missing")
+			return nil, GetAuditError(ErrorReadingAudit)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Verify walks the whole trail and checks that every entry follows the previous
+// one and still matches its hash.
+//
+// It returns an AuditChainBroken error describing the first broken entry in the log,
+// and the trail refuses new entries from then on.
+func (t *Trail) Verify(ctx context.Context) error {
+	var prev models.AuditEntry
+	err := t.scan(ctx, func(entry models.AuditEntry) error {
+		if err := verifyLink(prev, entry); err != nil {
+			return err
+		}
+		prev = entry
+		return nil
+	})
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Audit trail verification failed")
+		t.mutex.Lock()
+		if t.broken == nil {
+			t.broken = err
+		}
+		t.mutex.Unlock()
+		return GetAuditError(AuditChainBroken)
+	}
+	return nil
+}
+
+// Check returns an AuditChainBroken error if the trail is known to be broken, from
+// loading it or from Verify. It is meant for readiness checks and neither loads nor
+// rescans the trail.
+func (t *Trail) Check(_ context.Context) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.broken != nil {
+		return GetAuditError(AuditChainBroken)
+	}
+	return nil
+}
+
+// load reads the existing entries once. The mutex must be held.
+func (t *Trail) load(ctx context.Context) error {
+	if t.loaded {
+		return nil
+	}
+	var prev models.AuditEntry
+	err := t.scan(ctx, func(entry models.AuditEntry) error {
+		if err := verifyLink(prev, entry); err != nil && t.broken == nil {
+			logger.Ctx(ctx).Error().Err(err).Msg("Audit trail failed verification")
+			t.broken = err
+		}
+		prev = entry
+		t.append(entry)
+		return nil
+	})
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to read the audit trail")
+		return err
+	}
+	t.loaded = true
+	return nil
+}
+
+func (t *Trail) append(entry models.AuditEntry) {
+	t.lastSeq, t.lastHash = entry.Seq, entry.Hash
+	t.byEmployee[entry.EmployeeID] = append(t.byEmployee[entry.EmployeeID], entry.Seq)
+}
+
+// scan calls f for every entry in sequence order.
+func (t *Trail) scan(ctx context.Context, f func(models.AuditEntry) error) error {
+	var last uint64
+	for {
+		entries, lastKey, err := t.db.GetItems(ctx, last, pageSize)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := f(entry); err != nil {
+				return err
+			}
+		}
+		if len(entries) < pageSize {
+			return nil
+		}
+		last = lastKey
+	}
+}
+
+func verifyLink(prev, entry models.AuditEntry) error {
+	if entry.Seq != prev.Seq+1 {
+		return fmt.Errorf("entry %d follows entry %d", entry.Seq, prev.Seq)
+	}
+	if entry.PrevHash != prev.Hash {
+		return fmt.Errorf("entry %d does not chain to entry %d", entry.Seq, prev.Seq)
+	}
+	hash, err := Hash(entry)
+	if err != nil {
+		return err
+	}
+	if hash != entry.Hash {
+		return fmt.Errorf("entry %d does not match its hash", entry.Seq)
+	}
+	return nil
+}
+
+// Hash returns the SHA-256 of the JSON encoding of entry without its Hash field.
+func Hash(entry models.AuditEntry) (string, error) {
+	entry.Hash = ""
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Diff returns the JSON fields that differ between before and after, sorted by name.
+// Either may be nil.
+func Diff(before, after any) ([]models.FieldChange, error) {
+	old, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for k := range old {
+		names[k] = true
+	}
+	for k := range cur {
+		names[k] = true
+	}
+	var sorted []string
+	for k := range names {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	changes := []models.FieldChange{}
+	for _, k := range sorted {
+		if !bytes.Equal(old[k], cur[k]) {
+			changes = append(changes, models.FieldChange{Field: k, Old: old[k], New: cur[k]})
+		}
+	}
+	return changes, nil
+}
+
+func fields(v any) (map[string]json.RawMessage, error) {
+	m := map[string]json.RawMessage{}
+	if v == nil {
+		return m, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return m, json.Unmarshal(data, &m)
+}
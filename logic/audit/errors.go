@@ -0,0 +1,26 @@
+package audit
+
+import (
+	"employee/pkg/apierror"
+	"net/http"
+)
+
+func GetAuditError(c AuditError) *apierror.APIError {
+	return AuditErrors[c]
+}
+
+type AuditError int
+
+const (
+	InvalidEmployeeID AuditError = iota + 100600
+	ErrorRecordingAudit
+	ErrorReadingAudit
+	AuditChainBroken
+)
+
+var AuditErrors = map[AuditError]*apierror.APIError{
+	InvalidEmployeeID:   {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidEmployeeID), ErrorMessage: "Provide a valid employee ID"},
+	ErrorRecordingAudit: {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorRecordingAudit), ErrorMessage: "Error recording the audit trail"},
+	ErrorReadingAudit:   {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorReadingAudit), ErrorMessage: "Error reading the audit trail"},
+	AuditChainBroken:    {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(AuditChainBroken), ErrorMessage: "Audit trail failed verification"},
+}
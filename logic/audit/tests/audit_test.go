@@ -0,0 +1,167 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"employee/logic/audit"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"employee/service/simpledb"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trail", func() {
+	var (
+		trail *audit.Trail
+		eh    *employee.Employee
+		ctx   context.Context
+		now   time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+		trail = audit.NewTrail()
+		trail.SetClock(func() time.Time { return now })
+		eh = employee.NewEmployee()
+		eh.SetAuditTrail(trail)
+		ctx = auth.WithPrincipal(logger.WithRequestID(context.Background(), "req-1"), &auth.Principal{Subject: "alice"})
+	})
+
+	It("should record create, update and delete with the actor and a field diff", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100)})).To(Succeed())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(120)})).To(Succeed())
+		Expect(eh.DeleteEmployee(ctx, "1")).To(Succeed())
+
+		entries, err := trail.List(ctx, "1")
+		Expect(err).To(BeNil())
+		Expect(entries).To(HaveLen(3))
+
+		Expect(entries[0].Action).To(Equal(models.AuditCreate))
+		Expect(entries[0].Actor).To(Equal("alice"))
+		Expect(entries[0].RequestID).To(Equal("req-1"))
+		Expect(entries[0].Timestamp).To(Equal(now))
+		// id, name, position, salary and the default currency and status
+		Expect(entries[0].Changes).To(HaveLen(6))
+
+		Expect(entries[1].Action).To(Equal(models.AuditUpdate))
+		Expect(entries[1].Changes).To(Equal([]models.FieldChange{{Field: "salary", Old: []byte(`"100"`), New: []byte(`"120"`)}}))
+
+		Expect(entries[2].Action).To(Equal(models.AuditDelete))
+		Expect(entries[2].Changes[0].New).To(BeNil())
+
+		Expect(entries[1].PrevHash).To(Equal(entries[0].Hash))
+		Expect(entries[2].PrevHash).To(Equal(entries[1].Hash))
+		Expect(trail.Verify(ctx)).To(Succeed())
+	})
+
+	It("should not record failed mutations", func() {
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(120)})).NotTo(Succeed())
+		entries, err := trail.List(ctx, "1")
+		Expect(err).To(BeNil())
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("should record anonymous callers", func() {
+		Expect(eh.CreateEmployee(context.Background(), models.Employee{ID: 2, Name: "Bob", Position: "Engineer", Salary: models.MoneyOf(100)})).To(Succeed())
+		entries, err := trail.List(ctx, "2")
+		Expect(err).To(BeNil())
+		Expect(entries[0].Actor).To(Equal(audit.AnonymousActor))
+	})
+
+	It("should reject invalid employee IDs", func() {
+		_, err := trail.List(ctx, "abc")
+		Expect(err).To(Equal(audit.GetAuditError(audit.InvalidEmployeeID)))
+	})
+
+	Context("persisted in a journal", func() {
+		var path string
+
+		BeforeEach(func() {
+			path = filepath.Join(GinkgoT().TempDir(), "audit.journal")
+			db, err := simpledb.Open[uint64, models.AuditEntry](path)
+			Expect(err).To(BeNil())
+			trail = audit.NewTrailWithDB(db)
+			eh.SetAuditTrail(trail)
+			Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100)})).To(Succeed())
+			Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(120)})).To(Succeed())
+			Expect(db.Close()).To(Succeed())
+		})
+
+		reopen := func() *audit.Trail {
+			db, err := simpledb.Open[uint64, models.AuditEntry](path)
+			Expect(err).To(BeNil())
+			return audit.NewTrailWithDB(db)
+		}
+
+		It("should continue the chain after a restart", func() {
+			trail = reopen()
+			Expect(trail.Record(ctx, 1, models.AuditDelete, models.Employee{ID: 1}, nil)).To(Succeed())
+			entries, err := trail.List(ctx, "1")
+			Expect(err).To(BeNil())
+			Expect(entries).To(HaveLen(3))
+			Expect(entries[2].Seq).To(Equal(uint64(3)))
+			Expect(trail.Verify(ctx)).To(Succeed())
+		})
+
+		It("should detect an edited entry", func() {
+			data, err := os.ReadFile(path)
+			Expect(err).To(BeNil())
+			Expect(os.WriteFile(path, bytes.Replace(data, []byte(`"new":"120"`), []byte(`"new":"999"`), 1), 0o600)).To(Succeed())
+
+			Expect(reopen().Verify(ctx)).To(Equal(audit.GetAuditError(audit.AuditChainBroken)))
+		})
+
+		It("should detect a removed entry", func() {
+			data, err := os.ReadFile(path)
+			Expect(err).To(BeNil())
+			lines := bytes.SplitAfter(data, []byte("\n"))
+			Expect(os.WriteFile(path, bytes.Join(lines[1:], nil), 0o600)).To(Succeed())
+
+			Expect(reopen().Verify(ctx)).To(Equal(audit.GetAuditError(audit.AuditChainBroken)))
+		})
+
+		Context("once verification failed", func() {
+			BeforeEach(func() {
+				data, err := os.ReadFile(path)
+				Expect(err).To(BeNil())
+				Expect(os.WriteFile(path, bytes.Replace(data, []byte(`"new":"120"`), []byte(`"new":"999"`), 1), 0o600)).To(Succeed())
+				trail = reopen()
+				eh.SetAuditTrail(trail)
+				Expect(trail.Check(ctx)).To(Succeed())
+				Expect(trail.Verify(ctx)).NotTo(Succeed())
+			})
+
+			It("should fail its check and refuse new entries", func() {
+				Expect(trail.Check(ctx)).To(Equal(audit.GetAuditError(audit.AuditChainBroken)))
+				Expect(trail.Record(ctx, 1, models.AuditDelete, models.Employee{ID: 1}, nil)).To(Equal(audit.GetAuditError(audit.AuditChainBroken)))
+				entries, err := trail.List(ctx, "1")
+				Expect(err).To(BeNil())
+				Expect(entries).To(HaveLen(2))
+			})
+
+			It("should undo the mutations it cannot record", func() {
+				Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(150)})).To(Equal(audit.GetAuditError(audit.AuditChainBroken)))
+				res, err := eh.GetEmployee(ctx, "1", "", "")
+				Expect(err).To(BeNil())
+				Expect(res.Employees[0].Salary).To(Equal(models.MoneyOf(120)))
+				history, err := eh.GetSalaryHistory(ctx, "1")
+				Expect(err).To(BeNil())
+				Expect(history.Changes).To(HaveLen(2))
+
+				Expect(eh.DeleteEmployee(ctx, "1")).To(Equal(audit.GetAuditError(audit.AuditChainBroken)))
+				Expect(eh.CreateEmployee(ctx, models.Employee{ID: 2, Name: "Bob", Position: "Engineer", Salary: models.MoneyOf(100)})).To(Equal(audit.GetAuditError(audit.AuditChainBroken)))
+				res, err = eh.GetEmployee(ctx, "", "", "")
+				Expect(err).To(BeNil())
+				Expect(res.Employees).To(HaveLen(1))
+				Expect(res.Employees[0].DeletedAt).To(BeNil())
+			})
+		})
+	})
+})
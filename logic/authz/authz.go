@@ -0,0 +1,166 @@
+package authz
+
+import (
+	"context"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"strconv"
+)
+
+// Decision is the outcome of a permitted authorization check.
+type Decision struct {
+	// HiddenFields lists the employee fields, by JSON name, the caller may not see.
+	HiddenFields []string
+}
+
+// allowAll is the decision used when no authorizer is configured.
+var allowAll = Decision{}
+
+// ManagerLookup returns the manager ID of employee id, or false if it has none.
+type ManagerLookup func(ctx context.Context, id int) (int, bool)
+
+type Authorizer struct {
+	policy    *Policy
+	managerOf ManagerLookup
+}
+
+// NewAuthorizer creates an Authorizer evaluating the given policy.
+//
+// A nil *Authorizer is valid and permits every action.
+func NewAuthorizer(policy *Policy) *Authorizer {
+	return &Authorizer{policy: policy}
+}
+
+// SetHierarchy checks the own_reports scope against the reporting hierarchy looked
+// up by managerOf instead of the Reports of the policy. Callers are matched to their
+// employee record by the Employees of the policy.
+func (a *Authorizer) SetHierarchy(managerOf ManagerLookup) {
+	a.managerOf = managerOf
+}
+
+// Authorize checks whether principal may perform action on the employee identified by target.
+// target is the raw employee ID of the request and may be empty for collection reads and creates.
+//
+// It returns the decision for the caller, or an *apierror.APIError describing the denial.
+func (a *Authorizer) Authorize(ctx context.Context, principal *auth.Principal, action Action, target string) (Decision, error) {
+	if a == nil {
+		return allowAll, nil
+	}
+	if principal == nil {
+		logger.Ctx(ctx).Error().Str("action", string(action)).Msg("Unauthenticated request denied")
+		return Decision{}, GetAuthzError(NoRoleAssigned)
+	}
+
+	if principal.Method == auth.MethodAPIKey {
+		return a.authorizeScopes(ctx, principal, action)
+	}
+
+	names, roles := a.rolesOf(principal)
+	if len(roles) == 0 {
+		logger.Ctx(ctx).Error().Str("subject", principal.Subject).Msg("No role assigned")
+		return Decision{}, GetAuthzError(NoRoleAssigned)
+	}
+
+	permitted, outOfScope := false, false
+	for _, role := range roles {
+		for _, g := range role.Grants {
+			if g.Action != action {
+				continue
+			}
+			if g.Scope == ScopeOwnReports && !a.isReport(ctx, principal.Subject, target) {
+				outOfScope = true
+				continue
+			}
+			permitted = true
+		}
+	}
+
+	if !permitted {
+		logger.Ctx(ctx).Error().Str("subject", principal.Subject).Str("action", string(action)).Str("target", target).
+			Msg("Action denied")
+		if outOfScope {
+			return Decision{}, GetAuthzError(NotOwnReport)
+		}
+		return Decision{}, GetAuthzError(ActionNotPermitted)
+	}
+	return Decision{HiddenFields: a.hiddenFields(names, nil)}, nil
+}
+
+// scopeActions maps API key scopes to the actions they permit.
+var scopeActions = map[string][]Action{
+	auth.ScopeEmployeesRead:  {ActionRead},
+	auth.ScopeEmployeesWrite: {ActionCreate, ActionUpdate, ActionDelete},
+	auth.ScopeAPIKeysAdmin:   {ActionManageAPIKeys},
+	auth.ScopeLoggingAdmin:   {ActionManageLogging},
+	auth.ScopeAuditRead:      {ActionReadAudit},
+}
+
+// authorizeScopes authorizes API key callers, which carry scopes instead of roles.
+func (a *Authorizer) authorizeScopes(ctx context.Context, principal *auth.Principal, action Action) (Decision, error) {
+	for _, scope := range principal.Scopes {
+		for _, allowed := range scopeActions[scope] {
+			if allowed == action {
+				return Decision{HiddenFields: a.hiddenFields(nil, principal.Scopes)}, nil
+			}
+		}
+	}
+	logger.Ctx(ctx).Error().Str("subject", principal.Subject).Str("action", string(action)).Msg("Action denied")
+	return Decision{}, GetAuthzError(ActionNotPermitted)
+}
+
+// rolesOf returns the names and definitions of the known roles held by principal.
+func (a *Authorizer) rolesOf(principal *auth.Principal) ([]string, []Role) {
+	var names []string
+	var roles []Role
+	for _, name := range append(append([]string{}, principal.Roles...), a.policy.Bindings[principal.Subject]...) {
+		if role, ok := a.policy.Roles[name]; ok {
+			names = append(names, name)
+			roles = append(roles, role)
+		}
+	}
+	return names, roles
+}
+
+// hiddenFields returns the restricted fields that none of the given roles or scopes may see.
+func (a *Authorizer) hiddenFields(roles, scopes []string) []string {
+	var hidden []string
+	for field, rule := range a.policy.Fields {
+		if !containsAny(rule.VisibleTo, roles) && !containsAny(rule.VisibleToScopes, scopes) {
+			hidden = append(hidden, field)
+		}
+	}
+	return hidden
+}
+
+func containsAny(list, values []string) bool {
+	for _, l := range list {
+		for _, v := range values {
+			if l == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isReport reports whether the employee target reports directly to the subject manager.
+func (a *Authorizer) isReport(ctx context.Context, manager, target string) bool {
+	id, err := strconv.Atoi(target)
+	if err != nil {
+		return false
+	}
+	if a.managerOf != nil {
+		self, ok := a.policy.Employees[manager]
+		if !ok {
+			return false
+		}
+		managerID, ok := a.managerOf(ctx, id)
+		return ok && managerID == self
+	}
+	for _, report := range a.policy.Reports[manager] {
+		if report == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package authz
+
+import (
+	"employee/pkg/apierror"
+	"net/http"
+)
+
+func GetAuthzError(c AuthzError) *apierror.APIError {
+	return AuthzErrors[c]
+}
+
+type AuthzError int
+
+const (
+	NoRoleAssigned AuthzError = iota + 100200
+	ActionNotPermitted
+	NotOwnReport
+	FieldHidden
+)
+
+var AuthzErrors = map[AuthzError]*apierror.APIError{
+	NoRoleAssigned:     {HttpStatusCode: http.StatusForbidden, ErrCode: int(NoRoleAssigned), ErrorMessage: "Caller has no role assigned"},
+	ActionNotPermitted: {HttpStatusCode: http.StatusForbidden, ErrCode: int(ActionNotPermitted), ErrorMessage: "Caller is not permitted to perform this action"},
+	NotOwnReport:       {HttpStatusCode: http.StatusForbidden, ErrCode: int(NotOwnReport), ErrorMessage: "Caller may only modify their own reports"},
+	FieldHidden:        {HttpStatusCode: http.StatusForbidden, ErrCode: int(FieldHidden), ErrorMessage: "Caller is not permitted to see this field"},
+}
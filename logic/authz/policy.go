@@ -0,0 +1,155 @@
+package authz
+
+import (
+	"employee/pkg/auth"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionRead   Action = "read"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	// ActionManageAPIKeys covers the API key admin endpoints.
+	ActionManageAPIKeys Action = "manage_api_keys"
+	// ActionManageLogging covers the log level admin endpoint.
+	ActionManageLogging Action = "manage_logging"
+	// ActionReadAudit covers the employee audit trail.
+	ActionReadAudit Action = "read_audit"
+	// ActionManageDepartments covers creating, renaming and deleting departments.
+	ActionManageDepartments Action = "manage_departments"
+	// ActionManagePositions covers the position catalogue and its salary bands.
+	ActionManagePositions Action = "manage_positions"
+)
+
+type Scope string
+
+const (
+	// ScopeAll allows the action on any employee.
+	ScopeAll Scope = "all"
+	// ScopeOwnReports allows the action only on employees reporting to the caller.
+	ScopeOwnReports Scope = "own_reports"
+)
+
+// Grant allows a single action, optionally restricted to a scope.
+type Grant struct {
+	Action Action `json:"action"`
+	Scope  Scope  `json:"scope,omitempty"`
+}
+
+// Role is a named set of grants.
+type Role struct {
+	Grants []Grant `json:"grants"`
+}
+
+// FieldRule restricts the visibility of a response field to a set of roles and API key scopes.
+type FieldRule struct {
+	VisibleTo       []string `json:"visible_to"`
+	VisibleToScopes []string `json:"visible_to_scopes,omitempty"`
+}
+
+// Policy maps roles to grants. Roles are taken from the caller's token and from Bindings.
+type Policy struct {
+	Roles map[string]Role `json:"roles"`
+	// Fields lists the employee fields, by JSON name, that only some roles may see.
+	// Fields that are not listed are visible to every caller permitted to read.
+	Fields map[string]FieldRule `json:"fields,omitempty"`
+	// Bindings assigns roles to subjects in addition to the roles carried by their token.
+	Bindings map[string][]string `json:"bindings,omitempty"`
+	// Reports lists the employee IDs reporting to each manager subject. It is ignored
+	// once the authorizer checks the reporting hierarchy, see Authorizer.SetHierarchy.
+	Reports map[string][]int `json:"reports,omitempty"`
+	// Employees maps subjects to their employee ID in the reporting hierarchy.
+	Employees map[string]int `json:"employees,omitempty"`
+}
+
+// LoadPolicyFile reads a JSON policy from path and validates it.
+func LoadPolicyFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse policy file: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Validate checks that every grant names a known action and scope, that every
+// binding refers to a defined role and that every employee ID is valid.
+func (p *Policy) Validate() error {
+	if len(p.Roles) == 0 {
+		return fmt.Errorf("policy defines no roles")
+	}
+	for name, role := range p.Roles {
+		for _, g := range role.Grants {
+			switch g.Action {
+			case ActionCreate, ActionRead, ActionUpdate, ActionDelete, ActionManageAPIKeys, ActionManageLogging, ActionReadAudit, ActionManageDepartments, ActionManagePositions:
+			default:
+				return fmt.Errorf("role %q: unknown action %q", name, g.Action)
+			}
+			switch g.Scope {
+			case "", ScopeAll, ScopeOwnReports:
+			default:
+				return fmt.Errorf("role %q: unknown scope %q", name, g.Scope)
+			}
+		}
+	}
+	for field, rule := range p.Fields {
+		if !isRedactableField(field) {
+			return fmt.Errorf("field rule: unknown employee field %q", field)
+		}
+		for _, r := range rule.VisibleTo {
+			if _, ok := p.Roles[r]; !ok {
+				return fmt.Errorf("field rule for %q: unknown role %q", field, r)
+			}
+		}
+	}
+	for subject, roles := range p.Bindings {
+		for _, r := range roles {
+			if _, ok := p.Roles[r]; !ok {
+				return fmt.Errorf("binding for %q: unknown role %q", subject, r)
+			}
+		}
+	}
+	for subject, id := range p.Employees {
+		if id <= 0 {
+			return fmt.Errorf("employee of %q: invalid employee ID %d", subject, id)
+		}
+	}
+	return nil
+}
+
+// DefaultPolicy returns the built-in hr-admin, manager and viewer roles.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Roles: map[string]Role{
+			"hr-admin": {
+				Grants: []Grant{
+					{Action: ActionCreate}, {Action: ActionRead}, {Action: ActionUpdate}, {Action: ActionDelete},
+					{Action: ActionManageAPIKeys}, {Action: ActionManageLogging}, {Action: ActionReadAudit},
+					{Action: ActionManageDepartments}, {Action: ActionManagePositions},
+				},
+			},
+			"manager": {
+				Grants: []Grant{
+					{Action: ActionRead}, {Action: ActionUpdate, Scope: ScopeOwnReports},
+				},
+			},
+			"viewer": {
+				Grants: []Grant{{Action: ActionRead}},
+			},
+		},
+		Fields: map[string]FieldRule{
+			"salary": {VisibleTo: []string{"hr-admin", "manager"}, VisibleToScopes: []string{auth.ScopeSalaryRead}},
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package authz
+
+import (
+	"employee/models"
+	"reflect"
+	"strings"
+)
+
+// employeeFields maps the JSON name of every optional employee field to its struct field index.
+// Only omitempty fields can be redacted, as clearing them removes them from the encoded response.
+var employeeFields = func() map[string]int {
+	fields := map[string]int{}
+	t := reflect.TypeOf(models.Employee{})
+	for i := 0; i < t.NumField(); i++ {
+		name, opts, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" && strings.Contains(opts, "omitempty") {
+			fields[name] = i
+		}
+	}
+	return fields
+}()
+
+func isRedactableField(name string) bool {
+	_, ok := employeeFields[name]
+	return ok
+}
+
+// Redact removes the hidden fields from each employee in place.
+//
+// Every response carrying employee records to a caller must pass through Redact.
+func (d Decision) Redact(emps []models.Employee) {
+	if len(d.HiddenFields) == 0 {
+		return
+	}
+	for i := range emps {
+		v := reflect.ValueOf(&emps[i]).Elem()
+		for _, field := range d.HiddenFields {
+			if idx, ok := employeeFields[field]; ok {
+				f := v.Field(idx)
+				f.Set(reflect.Zero(f.Type()))
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package authz_test
+
+import (
+	"context"
+	"employee/logic/authz"
+	"employee/models"
+	"employee/pkg/auth"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authorizer", func() {
+	var az *authz.Authorizer
+
+	BeforeEach(func() {
+		policy := authz.DefaultPolicy()
+		policy.Reports = map[string][]int{"boss": {2, 3}}
+		policy.Bindings = map[string][]string{"bound-admin": {"hr-admin"}}
+		az = authz.NewAuthorizer(policy)
+	})
+
+	principal := func(subject string, roles ...string) *auth.Principal {
+		return &auth.Principal{Subject: subject, Roles: roles}
+	}
+
+	Context("hr-admin", func() {
+		It("should be allowed to create and delete", func() {
+			_, err := az.Authorize(context.Background(), principal("admin", "hr-admin"), authz.ActionCreate, "1")
+			Expect(err).To(BeNil())
+			_, err = az.Authorize(context.Background(), principal("admin", "hr-admin"), authz.ActionDelete, "1")
+			Expect(err).To(BeNil())
+		})
+
+		It("should get the role from a policy binding", func() {
+			_, err := az.Authorize(context.Background(), principal("bound-admin"), authz.ActionDelete, "1")
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("manager", func() {
+		It("should be allowed to update their own reports", func() {
+			_, err := az.Authorize(context.Background(), principal("boss", "manager"), authz.ActionUpdate, "2")
+			Expect(err).To(BeNil())
+		})
+
+		It("should not be allowed to update other employees", func() {
+			_, err := az.Authorize(context.Background(), principal("boss", "manager"), authz.ActionUpdate, "4")
+			Expect(err).To(Equal(authz.GetAuthzError(authz.NotOwnReport)))
+		})
+
+		It("should not be allowed to delete", func() {
+			_, err := az.Authorize(context.Background(), principal("boss", "manager"), authz.ActionDelete, "2")
+			Expect(err).To(Equal(authz.GetAuthzError(authz.ActionNotPermitted)))
+		})
+
+		Context("with the reporting hierarchy", func() {
+			BeforeEach(func() {
+				policy := authz.DefaultPolicy()
+				policy.Reports = map[string][]int{"boss": {2, 3}}
+				policy.Employees = map[string]int{"boss": 1}
+				az = authz.NewAuthorizer(policy)
+				managers := map[int]int{2: 1, 4: 1, 5: 4}
+				az.SetHierarchy(func(_ context.Context, id int) (int, bool) {
+					m, ok := managers[id]
+					return m, ok
+				})
+			})
+
+			It("should check the manager of the target instead of the policy reports", func() {
+				_, err := az.Authorize(context.Background(), principal("boss", "manager"), authz.ActionUpdate, "4")
+				Expect(err).To(BeNil())
+				_, err = az.Authorize(context.Background(), principal("boss", "manager"), authz.ActionUpdate, "3")
+				Expect(err).To(Equal(authz.GetAuthzError(authz.NotOwnReport)))
+				_, err = az.Authorize(context.Background(), principal("boss", "manager"), authz.ActionUpdate, "5")
+				Expect(err).To(Equal(authz.GetAuthzError(authz.NotOwnReport)))
+			})
+
+			It("should deny managers without an employee record", func() {
+				_, err := az.Authorize(context.Background(), principal("other", "manager"), authz.ActionUpdate, "2")
+				Expect(err).To(Equal(authz.GetAuthzError(authz.NotOwnReport)))
+			})
+		})
+	})
+
+	Context("viewer", func() {
+		It("should read without salary", func() {
+			decision, err := az.Authorize(context.Background(), principal("someone", "viewer"), authz.ActionRead, "")
+			Expect(err).To(BeNil())
+			Expect(decision.HiddenFields).To(ConsistOf("salary"))
+		})
+
+		It("should not be allowed to create", func() {
+			_, err := az.Authorize(context.Background(), principal("someone", "viewer"), authz.ActionCreate, "1")
+			Expect(err).To(Equal(authz.GetAuthzError(authz.ActionNotPermitted)))
+		})
+	})
+
+	It("should deny callers without a known role", func() {
+		_, err := az.Authorize(context.Background(), principal("nobody", "intern"), authz.ActionRead, "")
+		Expect(err).To(Equal(authz.GetAuthzError(authz.NoRoleAssigned)))
+	})
+
+	It("should deny anonymous callers", func() {
+		_, err := az.Authorize(context.Background(), nil, authz.ActionRead, "")
+		Expect(err).To(Equal(authz.GetAuthzError(authz.NoRoleAssigned)))
+	})
+
+	It("should allow everything when no authorizer is configured", func() {
+		var none *authz.Authorizer
+		decision, err := none.Authorize(context.Background(), nil, authz.ActionDelete, "1")
+		Expect(err).To(BeNil())
+		Expect(decision.HiddenFields).To(BeEmpty())
+	})
+
+	Context("Redact", func() {
+		It("should clear only the hidden fields", func() {
+			emps := []models.Employee{{ID: 1, Name: "John Doe", Position: "Developer", Salary: models.MoneyOf(50000)}}
+			authz.Decision{HiddenFields: []string{"salary"}}.Redact(emps)
+			Expect(emps[0]).To(Equal(models.Employee{ID: 1, Name: "John Doe", Position: "Developer"}))
+		})
+	})
+
+	Context("LoadPolicyFile", func() {
+		It("should load a valid policy", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "policy.json")
+			Expect(os.WriteFile(path, []byte(`{"roles":{"viewer":{"grants":[{"action":"read"}]}}}`), 0o600)).To(Succeed())
+			p, err := authz.LoadPolicyFile(path)
+			Expect(err).To(BeNil())
+			Expect(p.Roles).To(HaveKey("viewer"))
+		})
+
+		It("should reject an unknown action", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "policy.json")
+			Expect(os.WriteFile(path, []byte(`{"roles":{"viewer":{"grants":[{"action":"fire"}]}}}`), 0o600)).To(Succeed())
+			_, err := authz.LoadPolicyFile(path)
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should reject a field rule for an unknown field", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "policy.json")
+			Expect(os.WriteFile(path, []byte(`{"roles":{"viewer":{"grants":[{"action":"read"}]}},"fields":{"ssn":{"visible_to":["viewer"]}}}`), 0o600)).To(Succeed())
+			_, err := authz.LoadPolicyFile(path)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
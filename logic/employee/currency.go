@@ -0,0 +1,60 @@
+package employee
+
+import (
+	"context"
+	"employee/models"
+	"employee/pkg/currency"
+	"employee/pkg/logger"
+	"math/big"
+)
+
+// DefaultCurrency is the currency of salaries given without one, unless SetCurrencies
+// is called.
+const DefaultCurrency = "USD"
+
+// amount is a salary and its currency.
+type amount struct {
+	value    models.Money
+	currency string
+}
+
+// SetCurrencies sets the currency of salaries given without one, which is also the
+// currency reports are in by default, and the rates converting between currencies.
+// A nil table only allows reports in the currency every salary is paid in.
+func (eh *Employee) SetCurrencies(defaultCurrency string, rates *currency.Table) {
+	eh.defaultCurrency = defaultCurrency
+	eh.rates = rates
+}
+
+// currencyOf returns the currency emp is paid in.
+func (eh *Employee) currencyOf(emp models.Employee) string {
+	if emp.Currency == "" {
+		return eh.defaultCurrency
+	}
+	return emp.Currency
+}
+
+// checkCurrency checks that code is an ISO 4217 currency code.
+func checkCurrency(ctx context.Context, code string) error {
+	if !currency.Valid(code) {
+		logger.Ctx(ctx).Error().Str("currency", code).Msg("Invalid currency")
+		return GetEmpError(InvalidCurrency)
+	}
+	return nil
+}
+
+// fitsMinorUnit reports whether value has no more decimals than the minor unit of
+// code, so that it can be paid.
+func fitsMinorUnit(value models.Money, code string) bool {
+	return value.Decimals() <= currency.MinorUnits(code)
+}
+
+// convert returns value in from converted into to at the rate in effect on date.
+func (eh *Employee) convert(ctx context.Context, value *big.Rat, from, to, date string) (*big.Rat, error) {
+	converted, err := eh.rates.Convert(value, from, to, date)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Exchange rate not found")
+		return nil, GetEmpError(ExchangeRateNotFound)
+	}
+	return converted, nil
+}
@@ -0,0 +1,245 @@
+package employee
+
+import (
+	"context"
+	"employee/models"
+	"employee/pkg/currency"
+	"employee/pkg/logger"
+	"employee/pkg/tracing"
+	"employee/service/simpledb"
+	"errors"
+	"math/big"
+	"sort"
+)
+
+// SetDepartmentDB keeps the departments in db instead of a new in-memory database.
+func (eh *Employee) SetDepartmentDB(db *simpledb.Database[string, models.Department]) {
+	eh.departments = db
+}
+
+// checkDepartment checks that the department an employee is assigned to exists.
+// The index must be locked, so that the department cannot be deleted meanwhile.
+func (eh *Employee) checkDepartment(ctx context.Context, department string) error {
+	if department == "" {
+		return nil
+	}
+	if _, ok := eh.departments.GetItem(ctx, department); !ok {
+		logger.Ctx(ctx).Error().Str("department", department).Msg("Department not found")
+		return GetEmpError(DepartmentNotFound)
+	}
+	return nil
+}
+
+// reservedDepartmentIDs are the paths under /departments that are not department IDs.
+var reservedDepartmentIDs = map[string]bool{"rollups": true}
+
+// CreateDepartment adds a department employees can then be assigned to.
+//
+// It returns an error if the ID or name is invalid or the department already exists.
+func (eh *Employee) CreateDepartment(ctx context.Context, department models.Department) (err error) {
+	ctx, span := tracing.Start(ctx, "employee.CreateDepartment")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("department.id", department.ID)
+
+	logger.Ctx(ctx).Debug().Str("id", department.ID).Str("name", department.Name).Msg("Create department request received")
+
+	if department.ID == "" || len(department.ID) > maxDepartmentLength || reservedDepartmentIDs[department.ID] {
+		logger.Ctx(ctx).Error().Str("id", department.ID).Msg("Invalid department ID")
+		return GetEmpError(InvalidID)
+	}
+	if department.Name == "" || len(department.Name) > maxDepartmentLength {
+		logger.Ctx(ctx).Error().Str("name", department.Name).Msg("Invalid department name")
+		return GetEmpError(InvalidDepartmentName)
+	}
+
+	if err := eh.departments.SetItem(ctx, department.ID, department); err != nil {
+		if errors.Is(err, simpledb.KeyAlreadyPresent) {
+			logger.Ctx(ctx).Error().Str("id", department.ID).Msg("Department already exists")
+			return GetEmpError(DepartmentAlreadyExists)
+		}
+		logger.Ctx(ctx).Error().Err(err).Msg("Error adding department")
+		return GetEmpError(ErrorAddingDepartment)
+	}
+	logger.Ctx(ctx).Debug().Str("id", department.ID).Msg("Request processed successfully")
+	return nil
+}
+
+// GetDepartments returns the department with the given ID, or the next page of
+// departments in creation order.
+func (eh *Employee) GetDepartments(ctx context.Context, depID, LastEvalKeyID, numRecords string) (res models.GetDepartmentsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "employee.GetDepartments")
+	defer func() { span.SetError(err); span.End() }()
+
+	logger.Ctx(ctx).Debug().Str("id", depID).Str("lastEvalKeyId", LastEvalKeyID).Str("numRecords", numRecords).
+		Msg("Get departments request received")
+
+	if depID != "" {
+		department, ok := eh.departments.GetItem(ctx, depID)
+		if !ok {
+			logger.Ctx(ctx).Error().Str("id", depID).Msg("Department not found")
+			return res, GetEmpError(DepartmentNotFound)
+		}
+		res.Departments = []models.Department{department}
+		return res, nil
+	}
+
+	departments, last, err := eh.departments.GetItems(ctx, LastEvalKeyID, eh.pageSize(ctx, numRecords))
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("lastEvalKeyId", LastEvalKeyID).Msg("Error getting departments")
+		return res, GetEmpError(InvalidLastEvalKeyID)
+	}
+	res.Departments = departments
+	if res.Departments == nil {
+		res.Departments = []models.Department{}
+	}
+	res.LastEvalKeyID = last
+	logger.Ctx(ctx).Debug().Int("departments", len(res.Departments)).Msg("Request processed successfully")
+	return res, nil
+}
+
+// UpdateDepartment renames a department. Its ID, which employees refer to, cannot change.
+func (eh *Employee) UpdateDepartment(ctx context.Context, depID string, req models.DepartmentUpdateRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "employee.UpdateDepartment")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("department.id", depID)
+
+	logger.Ctx(ctx).Debug().Str("id", depID).Str("name", req.Name).Msg("Update department request received")
+
+	if req.Name == "" || len(req.Name) > maxDepartmentLength {
+		logger.Ctx(ctx).Error().Str("name", req.Name).Msg("Invalid department name")
+		return GetEmpError(InvalidDepartmentName)
+	}
+	if err := eh.departments.UpdateItem(ctx, depID, models.Department{ID: depID, Name: req.Name}); err != nil {
+		if errors.Is(err, simpledb.KeyAbsent) {
+			logger.Ctx(ctx).Error().Str("id", depID).Msg("Department not found")
+			return GetEmpError(DepartmentNotFound)
+		}
+		logger.Ctx(ctx).Error().Err(err).Msg("Error updating department")
+		return GetEmpError(ErrorUpdatingDepartment)
+	}
+	logger.Ctx(ctx).Debug().Str("id", depID).Msg("Request processed successfully")
+	return nil
+}
+
+// DeleteDepartment removes a department.
+//
+// It returns an error if the department does not exist or still has employees.
+func (eh *Employee) DeleteDepartment(ctx context.Context, depID string) (err error) {
+	ctx, span := tracing.Start(ctx, "employee.DeleteDepartment")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("department.id", depID)
+
+	logger.Ctx(ctx).Debug().Str("id", depID).Msg("Delete department request received")
+
+	unlock, err := eh.lockIndex(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error indexing employees")
+		return GetEmpError(ErrorDeletingDepartment)
+	}
+	defer unlock()
+
+	if r := eh.index.departments[depID]; r.headcount > 0 {
+		logger.Ctx(ctx).Error().Str("id", depID).Int("headcount", r.headcount).Msg("Department has employees")
+		return GetEmpError(DepartmentHasEmployees)
+	}
+	if err := eh.departments.DeleteItem(ctx, depID); err != nil {
+		if errors.Is(err, simpledb.KeyAbsent) {
+			logger.Ctx(ctx).Error().Str("id", depID).Msg("Department not found")
+			return GetEmpError(DepartmentNotFound)
+		}
+		logger.Ctx(ctx).Error().Err(err).Msg("Error deleting department")
+		return GetEmpError(ErrorDeletingDepartment)
+	}
+	logger.Ctx(ctx).Debug().Str("id", depID).Msg("Request processed successfully")
+	return nil
+}
+
+// GetDepartmentRollups returns the headcount, total and average salary of the
+// department with the given ID, or of every department ordered by ID. Departments
+// assigned before departments were managed are included while they have employees.
+//
+// Salaries are converted into reportingCurrency, the default currency when empty, at
+// today's rates. The figures are exact until rounded to its minor unit.
+//
+// The rollups are kept up to date by every employee write and count the salaries in
+// effect today, scheduled salary changes included once they take effect.
+func (eh *Employee) GetDepartmentRollups(ctx context.Context, depID, reportingCurrency string) (res models.GetDepartmentRollupsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "employee.GetDepartmentRollups")
+	defer func() { span.SetError(err); span.End() }()
+
+	logger.Ctx(ctx).Debug().Str("id", depID).Str("currency", reportingCurrency).Msg("Department rollups request received")
+
+	if reportingCurrency == "" {
+		reportingCurrency = eh.defaultCurrency
+	}
+	if err := checkCurrency(ctx, reportingCurrency); err != nil {
+		return res, err
+	}
+
+	var ids []string
+	seen := map[string]bool{}
+	if depID != "" {
+		if _, ok := eh.departments.GetItem(ctx, depID); !ok {
+			logger.Ctx(ctx).Error().Str("id", depID).Msg("Department not found")
+			return res, GetEmpError(DepartmentNotFound)
+		}
+		ids = []string{depID}
+	} else {
+		for last := ""; ; {
+			departments, next, err := eh.departments.GetItems(ctx, last, scanPageSize)
+			if err != nil {
+				logger.Ctx(ctx).Error().Err(err).Msg("Error getting departments")
+				return res, GetEmpError(ErrorGettingDepartment)
+			}
+			for _, d := range departments {
+				ids, seen[d.ID] = append(ids, d.ID), true
+			}
+			if next == "" {
+				break
+			}
+			last = next
+		}
+	}
+
+	unlock, err := eh.lockIndex(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error indexing employees")
+		return res, GetEmpError(ErrorGettingDepartment)
+	}
+	defer unlock()
+	if depID == "" {
+		for id := range eh.index.departments {
+			if !seen[id] {
+				ids = append(ids, id)
+			}
+		}
+	}
+	today := eh.today()
+	res.Rollups = make([]models.DepartmentRollup, 0, len(ids))
+	for _, id := range ids {
+		r := eh.index.departments[id]
+		rollup := models.DepartmentRollup{Department: id, Headcount: r.headcount, Currency: reportingCurrency}
+		total := new(big.Rat)
+		for code, t := range r.totals {
+			// A currency nobody in the department is paid in anymore needs no rate
+			if t == 0 {
+				continue
+			}
+			converted, err := eh.convert(ctx, t.Rat(), code, reportingCurrency, today)
+			if err != nil {
+				return res, err
+			}
+			total.Add(total, converted)
+		}
+		rollup.TotalSalary, _ = models.MoneyFromRat(currency.Round(total, reportingCurrency))
+		if r.headcount > 0 {
+			average := total.Quo(total, big.NewRat(int64(r.headcount), 1))
+			rollup.AverageSalary, _ = models.MoneyFromRat(currency.Round(average, reportingCurrency))
+		}
+		res.Rollups = append(res.Rollups, rollup)
+	}
+
+	sort.Slice(res.Rollups, func(i, j int) bool { return res.Rollups[i].Department < res.Rollups[j].Department })
+	logger.Ctx(ctx).Debug().Int("departments", len(res.Rollups)).Msg("Request processed successfully")
+	return res, nil
+}
@@ -0,0 +1,63 @@
+package employee
+
+import (
+	"context"
+	"employee/models"
+	"employee/pkg/logger"
+	"net/mail"
+)
+
+const (
+	// maxEmailLength is the longest address accepted, per RFC 5321.
+	maxEmailLength = 254
+	// maxDepartmentLength is the longest department accepted.
+	maxDepartmentLength = 100
+)
+
+// validateDetails checks the email, department, manager, dates and status of emp.
+func validateDetails(ctx context.Context, emp models.Employee) error {
+	if emp.Email != "" {
+		addr, err := mail.ParseAddress(emp.Email)
+		if err != nil || addr.Address != emp.Email || len(emp.Email) > maxEmailLength {
+			logger.Ctx(ctx).Error().Str("email", emp.Email).Msg("Invalid employee email")
+			return GetEmpError(InvalidEmail)
+		}
+	}
+
+	if len(emp.Department) > maxDepartmentLength {
+		logger.Ctx(ctx).Error().Str("department", emp.Department).Msg("Invalid employee department")
+		return GetEmpError(InvalidDepartment)
+	}
+
+	if emp.ManagerID < 0 || emp.ManagerID == emp.ID {
+		logger.Ctx(ctx).Error().Int("managerId", emp.ManagerID).Msg("Invalid manager ID")
+		return GetEmpError(InvalidManagerID)
+	}
+
+	if emp.HireDate != "" {
+		if _, ok := parseDate(emp.HireDate); !ok {
+			logger.Ctx(ctx).Error().Str("hireDate", emp.HireDate).Msg("Invalid hire date")
+			return GetEmpError(InvalidHireDate)
+		}
+	}
+	if emp.TerminationDate != "" {
+		if _, ok := parseDate(emp.TerminationDate); !ok || emp.TerminationDate < emp.HireDate {
+			logger.Ctx(ctx).Error().Str("terminationDate", emp.TerminationDate).Str("hireDate", emp.HireDate).
+				Msg("Invalid termination date")
+			return GetEmpError(InvalidTerminationDate)
+		}
+	}
+
+	switch emp.Status {
+	case models.StatusActive, models.StatusOnLeave:
+	case models.StatusTerminated:
+		if emp.TerminationDate == "" {
+			logger.Ctx(ctx).Error().Msg("Terminated employee without a termination date")
+			return GetEmpError(InvalidStatus)
+		}
+	default:
+		logger.Ctx(ctx).Error().Str("status", emp.Status).Msg("Invalid employee status")
+		return GetEmpError(InvalidStatus)
+	}
+	return nil
+}
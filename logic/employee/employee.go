@@ -1,15 +1,45 @@
 package employee
 
 import (
+	"context"
+	"employee/logic/audit"
 	"employee/models"
+	"employee/pkg/currency"
 	"employee/pkg/logger"
+	"employee/pkg/tracing"
 	"employee/service/simpledb"
 	"errors"
 	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultPageSize is the number of records returned when num_records is not provided.
+	DefaultPageSize = 10
+	// DefaultMaxPageSize is the largest num_records accepted unless configured otherwise.
+	DefaultMaxPageSize = 100
 )
 
 type Employee struct {
-	db *simpledb.Database[int, models.Employee]
+	db       *simpledb.Database[int, models.Employee]
+	salaries *simpledb.Database[int, models.SalaryHistory]
+	versions *simpledb.Database[int, models.EmployeeVersions]
+	// positions is the catalogue of positions and their salary bands
+	positions *simpledb.Database[string, models.Position]
+	// departments holds the departments employees may belong to
+	departments *simpledb.Database[string, models.Department]
+	// versionsMutex guards the one-off migration of employees stored before
+	// versions were kept
+	versionsMutex    sync.Mutex
+	versionsMigrated bool
+	index            index
+	audit            *audit.Trail
+	defaultCurrency  string
+	rates            *currency.Table
+	now              func() time.Time
+	defaultPageSize  int
+	maxPageSize      int
 }
 
 // NewEmployee creates a new instance of the Employee struct and initializes its db field with a new instance of the simpledb.Database[int, models.Employee] struct.
@@ -17,212 +47,614 @@ type Employee struct {
 // It returns a pointer to the newly created Employee struct.
 func NewEmployee() *Employee {
 	var d simpledb.Database[int, models.Employee]
+	return NewEmployeeWithDB(d.Init())
+}
+
+// NewEmployeeWithDB creates a new instance of the Employee struct backed by the given database.
+// Salary histories, versions, departments and positions are kept in memory unless
+// SetSalaryDB, SetVersionDB, SetDepartmentDB and SetPositionDB are called.
+func NewEmployeeWithDB(db *simpledb.Database[int, models.Employee]) *Employee {
+	var salaries simpledb.Database[int, models.SalaryHistory]
+	var versions simpledb.Database[int, models.EmployeeVersions]
+	var departments simpledb.Database[string, models.Department]
+	var positions simpledb.Database[string, models.Position]
 	return &Employee{
-		db: d.Init(),
+		db:              db,
+		salaries:        salaries.Init(),
+		versions:        versions.Init(),
+		departments:     departments.Init(),
+		positions:       positions.Init(),
+		now:             time.Now,
+		defaultCurrency: DefaultCurrency,
+		defaultPageSize: DefaultPageSize,
+		maxPageSize:     DefaultMaxPageSize,
+	}
+}
+
+// SetPageSizes sets the number of records returned by GetEmployee when numRecords is
+// not provided, and the largest number returned by a single call.
+func (eh *Employee) SetPageSizes(defaultSize, maxSize int) {
+	eh.defaultPageSize = defaultSize
+	eh.maxPageSize = maxSize
+}
+
+// SetAuditTrail records every create, update and delete on trail.
+func (eh *Employee) SetAuditTrail(trail *audit.Trail) {
+	eh.audit = trail
+}
+
+// record appends an audit entry when an audit trail is set.
+func (eh *Employee) record(ctx context.Context, id int, action string, before, after any) error {
+	if eh.audit == nil {
+		return nil
 	}
+	return eh.audit.Record(ctx, id, action, before, after)
 }
 
 // CreateEmployee creates a new employee in the system.
 //
 // It takes in a models.Employee object as a parameter and returns an error.
-func (eh *Employee) CreateEmployee(employee models.Employee) error {
-	logger.Log.Debug().Str("id", strconv.Itoa(employee.ID)).
-		Str("name", employee.Name).Str("position", employee.Position).Float64("salary", employee.Salary).
+func (eh *Employee) CreateEmployee(ctx context.Context, employee models.Employee) error {
+	return eh.CreateEmployeeWithJustification(ctx, employee, "")
+}
+
+// CreateEmployeeWithJustification is CreateEmployee accepting a salary outside the band
+// of the position for the given justification, recorded in the salary history.
+func (eh *Employee) CreateEmployeeWithJustification(ctx context.Context, employee models.Employee, justification string) (err error) {
+	ctx, span := tracing.Start(ctx, "employee.CreateEmployee")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("employee.id", employee.ID)
+
+	logger.Ctx(ctx).Debug().Str("id", strconv.Itoa(employee.ID)).
+		Str("name", employee.Name).Str("position", employee.Position).Stringer("salary", employee.Salary).
+		Str("email", employee.Email).Str("department", employee.Department).Int("managerId", employee.ManagerID).
 		Msg("Create Request received")
 
 	if employee.ID == 0 {
-		logger.Log.Error().Str("id", strconv.Itoa(employee.ID)).
+		logger.Ctx(ctx).Error().Str("id", strconv.Itoa(employee.ID)).
 			Msg("Invalid employee ID")
 		return GetEmpError(InvalidID)
 	}
 
 	if employee.Name == "" || len(employee.Name) > 100 {
-		logger.Log.Error().Str("name", employee.Name).
+		logger.Ctx(ctx).Error().Str("name", employee.Name).
 			Msg("Invalid employee name")
 		return GetEmpError(NameInvalid)
 	}
 
 	if employee.Position == "" || len(employee.Position) > 100 {
-		logger.Log.Error().Str("position", employee.Position).
+		logger.Ctx(ctx).Error().Str("position", employee.Position).
 			Msg("Invalid employee position")
 		return GetEmpError(InvalidPosition)
 	}
 
 	if employee.Salary <= 0 {
-		logger.Log.Error().Float64("salary", employee.Salary).
+		logger.Ctx(ctx).Error().Stringer("salary", employee.Salary).
 			Msg("Invalid employee salary")
 		return GetEmpError(InvalidSalary)
 	}
+	if employee.Currency == "" {
+		employee.Currency = eh.defaultCurrency
+	}
+	if err := checkCurrency(ctx, employee.Currency); err != nil {
+		return err
+	}
+	if !fitsMinorUnit(employee.Salary, employee.Currency) {
+		logger.Ctx(ctx).Error().Stringer("salary", employee.Salary).Str("currency", employee.Currency).
+			Msg("Salary finer than the minor unit of its currency")
+		return GetEmpError(InvalidSalary)
+	}
+
+	// Only DeleteEmployee marks employees deleted
+	employee.DeletedAt = nil
+	// Clients that predate the status field create active employees
+	if employee.Status == "" {
+		employee.Status = models.StatusActive
+	}
+	if err := validateDetails(ctx, employee); err != nil {
+		return err
+	}
+	if len(justification) > maxJustificationLength {
+		logger.Ctx(ctx).Error().Int("length", len(justification)).Msg("Invalid salary justification")
+		return GetEmpError(InvalidSalaryJustification)
+	}
+	override, err := eh.checkBand(ctx, employee.Position, justification, amount{employee.Salary, employee.Currency})
+	if err != nil {
+		return err
+	}
 
-	if err := eh.db.SetItem(employee.ID, employee); err != nil {
+	if err := eh.migrateVersions(ctx); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error recording employee versions")
+		return GetEmpError(ErrorRecordingVersion)
+	}
+	unlock, err := eh.lockIndex(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error indexing employees")
+		return GetEmpError(ErrorAddingEmp)
+	}
+	defer unlock()
+	if employee.Email != "" && eh.emailTaken(employee.Email, employee.ID) {
+		logger.Ctx(ctx).Error().Str("email", employee.Email).Msg("Email already exists")
+		return GetEmpError(EmailAlreadyExists)
+	}
+	if err := eh.checkDepartment(ctx, employee.Department); err != nil {
+		return err
+	}
+	if err := eh.checkManager(ctx, employee.ID, employee.ManagerID); err != nil {
+		return err
+	}
+	if err := eh.db.SetItem(ctx, employee.ID, employee); err != nil {
 		if errors.Is(err, simpledb.KeyAlreadyPresent) {
-			logger.Log.Error().Int("id", employee.ID).
+			logger.Ctx(ctx).Error().Int("id", employee.ID).
 				Msg("Employee already exists")
 			return GetEmpError(EmpAlreadyExists)
 		}
-		logger.Log.Error().Err(err).
+		logger.Ctx(ctx).Error().Err(err).
 			Msg("Error adding employee")
 		return GetEmpError(ErrorAddingEmp)
 	}
+	// The starting salary takes effect on the day the employee is created
+	change := eh.newSalaryChange(ctx, employee.Salary, employee.Currency, eh.today(), "")
+	change.OverrideJustification = override
+	history := models.SalaryHistory{Changes: []models.SalaryChange{change}}
+	if err := eh.salaries.SetItem(ctx, employee.ID, history); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Int("id", employee.ID).
+			Msg("Error recording salary, removing employee")
+		if err := eh.db.DeleteItem(ctx, employee.ID); err != nil {
+			logger.Ctx(ctx).Error().Err(err).Int("id", employee.ID).Msg("Error removing employee")
+		}
+		return GetEmpError(ErrorAddingEmp)
+	}
+	// A create that cannot be versioned or audited is undone rather than kept half recorded
+	undo := func() {
+		if err := eh.salaries.DeleteItem(ctx, employee.ID); err != nil {
+			logger.Ctx(ctx).Error().Err(err).Int("id", employee.ID).Msg("Error removing salary history")
+		}
+		if err := eh.db.DeleteItem(ctx, employee.ID); err != nil {
+			logger.Ctx(ctx).Error().Err(err).Int("id", employee.ID).Msg("Error removing employee")
+		}
+	}
+	prev, err := eh.addVersions(ctx, employee.ID, eh.newVersion(models.AuditCreate, employee, eh.today()))
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Int("id", employee.ID).Msg("Error recording employee version")
+		undo()
+		return GetEmpError(ErrorRecordingVersion)
+	}
+	if err := eh.record(ctx, employee.ID, models.AuditCreate, nil, employee); err != nil {
+		eh.restoreVersions(ctx, employee.ID, prev)
+		undo()
+		return err
+	}
+	eh.reindex(models.Employee{}, employee)
 
-	logger.Log.Debug().Str("id", strconv.Itoa(employee.ID)).
+	logger.Ctx(ctx).Debug().Str("id", strconv.Itoa(employee.ID)).
 		Msg("Request processed successfully")
 	return nil
 }
 
-func (eh *Employee) GetEmployee(empID, LastEvalKeyID, numRecords string) (models.GetEmployeeResponse, error) {
-	logger.Log.Debug().Str("empId", empID).Str("lastEvalKeyId", LastEvalKeyID).Str("numRecords", numRecords).
-		Msg("Get Request received")
+// GetEmployee returns the employee with the given ID, or the next page of employees,
+// with the salary in effect today.
+func (eh *Employee) GetEmployee(ctx context.Context, empID, LastEvalKeyID, numRecords string) (models.GetEmployeeResponse, error) {
+	return eh.GetEmployeeAsOf(ctx, empID, LastEvalKeyID, numRecords, "", "")
+}
+
+// GetEmployeeAsOf is GetEmployee with the employees as they were on asOf, a YYYY-MM-DD
+// date, according to what had been recorded at knownAt, an RFC 3339 timestamp or a
+// date standing for the end of that day. Changes scheduled after asOf and changes
+// recorded after knownAt are ignored, and employees deleted since are included.
+//
+// An empty asOf means the date of knownAt, and an empty knownAt means now.
+func (eh *Employee) GetEmployeeAsOf(ctx context.Context, empID, LastEvalKeyID, numRecords, asOf, knownAt string) (res models.GetEmployeeResponse, err error) {
+	ctx, span := tracing.Start(ctx, "employee.GetEmployee")
+	defer func() { span.SetError(err); span.End() }()
 
-	var res models.GetEmployeeResponse
+	logger.Ctx(ctx).Debug().Str("empId", empID).Str("lastEvalKeyId", LastEvalKeyID).Str("numRecords", numRecords).
+		Str("asOf", asOf).Str("knownAt", knownAt).Msg("Get Request received")
+
+	cutoff := eh.now()
+	if knownAt != "" {
+		var ok bool
+		if cutoff, ok = parseKnownAt(knownAt); !ok {
+			logger.Ctx(ctx).Error().Str("knownAt", knownAt).Msg("Invalid known_at time")
+			return res, GetEmpError(InvalidKnownAt)
+		}
+	}
+	date := cutoff.UTC().Format(time.DateOnly)
+	if asOf != "" {
+		var ok bool
+		if date, ok = parseDate(asOf); !ok {
+			logger.Ctx(ctx).Error().Str("asOf", asOf).Msg("Invalid as_of date")
+			return res, GetEmpError(InvalidAsOf)
+		}
+	}
+	if asOf != "" || knownAt != "" {
+		return eh.getVersions(ctx, empID, LastEvalKeyID, eh.pageSize(ctx, numRecords), date, cutoff)
+	}
 
 	// If empID is not empty, return the employee with the given ID
 	if empID != "" {
 		empIDInt, err := strconv.Atoi(empID)
 		if err != nil {
-			logger.Log.Error().Err(err).
+			logger.Ctx(ctx).Error().Err(err).
 				Str("empId", empID).
 				Msg("Invalid employee ID")
 			return res, GetEmpError(InvalidID)
 		}
-		emp, present := eh.db.GetItem(empIDInt)
+		emp, present := eh.getItem(ctx, empIDInt)
 		if !present {
-			logger.Log.Error().Str("empId", empID).
+			logger.Ctx(ctx).Error().Str("empId", empID).
 				Msg("Employee not found")
 			return res, GetEmpError(InvalidID)
 		}
 		res.Employees = append(res.Employees, emp)
-		logger.Log.Debug().
+		eh.applySalaries(ctx, res.Employees, date)
+		logger.Ctx(ctx).Debug().
 			Str("empId", empID).Msg("Request processed successfully")
 		return res, nil
 	}
 
 	// Else return the next batch of employees
 	var LastEvalKeyIDInt int
-	var err error
 	if LastEvalKeyID != "" {
 		LastEvalKeyIDInt, err = strconv.Atoi(LastEvalKeyID)
 		if err != nil {
-			logger.Log.Error().Err(err).
+			logger.Ctx(ctx).Error().Err(err).
 				Str("lastEvalKeyId", LastEvalKeyID).
 				Msg("Invalid lastEvalKeyId")
 			return res, GetEmpError(InvalidLastEvalKeyID)
 		}
 	}
-	numRecordsInt, err := strconv.Atoi(numRecords)
-	if err != nil {
-		numRecordsInt = 10 // Default value if numRecords is not provided
-	}
 
 	// Get the next batch of employees
-	res.Employees, res.LastEvalKeyID, err = eh.db.GetItems(LastEvalKeyIDInt, numRecordsInt)
+	res.Employees, res.LastEvalKeyID, err = eh.getItems(ctx, LastEvalKeyIDInt, eh.pageSize(ctx, numRecords))
 	if err != nil {
 		if errors.Is(err, simpledb.InvalidLastEvalKeyID) {
-			logger.Log.Error().Err(err).
+			logger.Ctx(ctx).Error().Err(err).
 				Str("lastEvalKeyId", LastEvalKeyID).
 				Msg("Invalid lastEvalKeyId")
 			return res, GetEmpError(InvalidLastEvalKeyID)
 		}
-		logger.Log.Error().Err(err).
+		logger.Ctx(ctx).Error().Err(err).
 			Msg("Error getting employees")
 		return res, GetEmpError(ErrorGettingEmp)
 	}
 	if res.Employees == nil {
 		res.Employees = []models.Employee{}
 	}
-	logger.Log.Debug().
+	eh.applySalaries(ctx, res.Employees, date)
+	logger.Ctx(ctx).Debug().
 		Msg("Request processed successfully")
 	return res, nil
 }
 
-func (eh *Employee) UpdateEmployee(empID string, empUpdateReq models.EmployeeUpdateRequest) error {
+// pageSize returns the number of records to return for numRecords, capped to the
+// largest page size.
+func (eh *Employee) pageSize(ctx context.Context, numRecords string) int {
+	numRecordsInt, err := strconv.Atoi(numRecords)
+	if err != nil || numRecordsInt <= 0 {
+		numRecordsInt = eh.defaultPageSize // Default value if numRecords is not provided
+	}
+	if numRecordsInt > eh.maxPageSize {
+		logger.Ctx(ctx).Debug().Int("numRecords", numRecordsInt).Int("max", eh.maxPageSize).
+			Msg("Capping numRecords")
+		numRecordsInt = eh.maxPageSize
+	}
+	return numRecordsInt
+}
+
+func (eh *Employee) UpdateEmployee(ctx context.Context, empID string, empUpdateReq models.EmployeeUpdateRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "employee.UpdateEmployee")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("employee.id", empID)
 
-	logger.Log.Debug().
+	logger.Ctx(ctx).Debug().
 		Str("empId", empID).
 		Str("position", empUpdateReq.Position).
-		Float64("salary", empUpdateReq.Salary).
+		Stringer("salary", empUpdateReq.Salary).
 		Msg("Update Request received")
 
 	// Validate the employee ID
 	if empID == "" {
-		logger.Log.Error().Str("empId", empID).Msg("Invalid employee ID")
+		logger.Ctx(ctx).Error().Str("empId", empID).Msg("Invalid employee ID")
 		return GetEmpError(InvalidID)
 	}
 	empInt, err := strconv.Atoi(empID)
 	if err != nil {
-		logger.Log.Error().Err(err).Str("empId", empID).Msg("Invalid employee ID")
+		logger.Ctx(ctx).Error().Err(err).Str("empId", empID).Msg("Invalid employee ID")
 		return GetEmpError(InvalidID)
 	}
 
 	// Validate the employee update request
-	if empUpdateReq.Position == "" && empUpdateReq.Salary == 0 {
-		logger.Log.Error().
+	if empUpdateReq.Position == "" && empUpdateReq.Salary == 0 && empUpdateReq.Email == "" && empUpdateReq.Department == "" &&
+		empUpdateReq.ManagerID == nil && empUpdateReq.HireDate == "" && empUpdateReq.TerminationDate == "" && empUpdateReq.Status == "" &&
+		empUpdateReq.Currency == "" {
+		logger.Ctx(ctx).Error().
 			Str("position", empUpdateReq.Position).
-			Float64("salary", empUpdateReq.Salary).
+			Stringer("salary", empUpdateReq.Salary).
 			Msg("Invalid employee update request")
 		return GetEmpError(InvalidEmpUpdate)
 	}
 
 	if empUpdateReq.Salary < 0 {
-		logger.Log.Error().Float64("salary", empUpdateReq.Salary).Msg("Invalid salary")
+		logger.Ctx(ctx).Error().Stringer("salary", empUpdateReq.Salary).Msg("Invalid salary")
 		return GetEmpError(InvalidSalary)
 	}
 
+	effectiveDate := eh.today()
+	if empUpdateReq.EffectiveDate != "" {
+		var ok bool
+		if effectiveDate, ok = parseDate(empUpdateReq.EffectiveDate); !ok || empUpdateReq.Salary == 0 {
+			logger.Ctx(ctx).Error().Str("effectiveDate", empUpdateReq.EffectiveDate).Msg("Invalid effective date")
+			return GetEmpError(InvalidEffectiveDate)
+		}
+	}
+	if len(empUpdateReq.SalaryJustification) > maxJustificationLength ||
+		(empUpdateReq.SalaryJustification != "" && empUpdateReq.Salary == 0 && empUpdateReq.Position == "") {
+		logger.Ctx(ctx).Error().Int("length", len(empUpdateReq.SalaryJustification)).Msg("Invalid salary justification")
+		return GetEmpError(InvalidSalaryJustification)
+	}
+	if empUpdateReq.Currency != "" {
+		if err := checkCurrency(ctx, empUpdateReq.Currency); err != nil {
+			return err
+		}
+		if empUpdateReq.Salary == 0 {
+			logger.Ctx(ctx).Error().Str("currency", empUpdateReq.Currency).Msg("Currency change without a salary")
+			return GetEmpError(InvalidCurrency)
+		}
+	}
+	if len(empUpdateReq.Reason) > maxReasonLength || (empUpdateReq.Reason != "" && empUpdateReq.Salary == 0) {
+		logger.Ctx(ctx).Error().Int("length", len(empUpdateReq.Reason)).Msg("Invalid salary change reason")
+		return GetEmpError(InvalidSalaryReason)
+	}
+
+	if err := eh.migrateVersions(ctx); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error recording employee versions")
+		return GetEmpError(ErrorRecordingVersion)
+	}
+	unlock, err := eh.lockIndex(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error indexing employees")
+		return GetEmpError(ErrorUpdateEmp)
+	}
+	defer unlock()
+
 	// Get the employee from the database
-	currentEmp, ok := eh.db.GetItem(empInt)
+	currentEmp, ok := eh.getItem(ctx, empInt)
 	if !ok {
-		logger.Log.Error().Int("empId", empInt).Msg("Employee not found")
+		logger.Ctx(ctx).Error().Int("empId", empInt).Msg("Employee not found")
 		return GetEmpError(InvalidID)
 	}
 
-	// Update the employee with the new values
+	before := currentEmp
+
+	// Update the employee with the new values, the fields set take effect today
+	var fields []string
 	if empUpdateReq.Position != "" {
-		logger.Log.Debug().Str("position", empUpdateReq.Position).Msg("Updating position")
+		logger.Ctx(ctx).Debug().Str("position", empUpdateReq.Position).Msg("Updating position")
 		currentEmp.Position = empUpdateReq.Position
+		fields = append(fields, "position")
+	}
+	if empUpdateReq.Email != "" {
+		currentEmp.Email = empUpdateReq.Email
+		fields = append(fields, "email")
+	}
+	if empUpdateReq.Department != "" {
+		currentEmp.Department = empUpdateReq.Department
+		fields = append(fields, "department")
+	}
+	if empUpdateReq.ManagerID != nil {
+		currentEmp.ManagerID = *empUpdateReq.ManagerID
+		fields = append(fields, "manager_id")
+	}
+	if empUpdateReq.HireDate != "" {
+		currentEmp.HireDate = empUpdateReq.HireDate
+		fields = append(fields, "hire_date")
+	}
+	if empUpdateReq.TerminationDate != "" {
+		currentEmp.TerminationDate = empUpdateReq.TerminationDate
+		fields = append(fields, "termination_date")
+	}
+	if empUpdateReq.Status != "" {
+		currentEmp.Status = empUpdateReq.Status
+		fields = append(fields, "status")
+	}
+	// Employees stored before the status field are active
+	if currentEmp.Status == "" {
+		currentEmp.Status = models.StatusActive
+	}
+	if err := validateDetails(ctx, currentEmp); err != nil {
+		return err
+	}
+	if empUpdateReq.Email != "" && eh.emailTaken(empUpdateReq.Email, empInt) {
+		logger.Ctx(ctx).Error().Str("email", empUpdateReq.Email).Msg("Email already exists")
+		return GetEmpError(EmailAlreadyExists)
+	}
+	// Employees stored before departments were managed keep their department until it changes
+	if empUpdateReq.Department != "" {
+		if err := eh.checkDepartment(ctx, currentEmp.Department); err != nil {
+			return err
+		}
+	}
+	if empUpdateReq.ManagerID != nil {
+		if err := eh.checkManager(ctx, empInt, currentEmp.ManagerID); err != nil {
+			return err
+		}
 	}
 
+	// A new salary must fit the band of the position, and so must the salary in effect
+	// today when the position changes
+	salaryCurrency := empUpdateReq.Currency
+	if salaryCurrency == "" {
+		salaryCurrency = eh.currencyOf(currentEmp)
+	}
+	if !fitsMinorUnit(empUpdateReq.Salary, salaryCurrency) {
+		logger.Ctx(ctx).Error().Stringer("salary", empUpdateReq.Salary).Str("currency", salaryCurrency).
+			Msg("Salary finer than the minor unit of its currency")
+		return GetEmpError(InvalidSalary)
+	}
+	var bandSalaries []amount
 	if empUpdateReq.Salary != 0 {
-		logger.Log.Debug().Float64("salary", empUpdateReq.Salary).Msg("Updating salary")
-		currentEmp.Salary = empUpdateReq.Salary
+		bandSalaries = append(bandSalaries, amount{empUpdateReq.Salary, salaryCurrency})
+	}
+	if empUpdateReq.Position != "" && (empUpdateReq.Salary == 0 || effectiveDate > eh.today()) {
+		bandSalaries = append(bandSalaries, amount{currentEmp.Salary, eh.currencyOf(currentEmp)})
+	}
+	override, err := eh.checkBand(ctx, currentEmp.Position, empUpdateReq.SalaryJustification, bandSalaries...)
+	if err != nil {
+		return err
+	}
+	// An override on a position change alone restates the salary in effect, so that the
+	// justification is recorded in the salary history
+	salary, reason := empUpdateReq.Salary, empUpdateReq.Reason
+	if override != "" && salary == 0 {
+		salary, salaryCurrency, reason = currentEmp.Salary, eh.currencyOf(currentEmp), positionChangeReason
 	}
 
-	if err := eh.db.UpdateItem(empInt, currentEmp); err != nil {
+	// A salary change is added to the history, the stored salary is the one in effect today
+	var salaries *models.SalaryHistory
+	if salary != 0 {
+		logger.Ctx(ctx).Debug().Stringer("salary", salary).Str("effectiveDate", effectiveDate).
+			Msg("Updating salary")
+		change := eh.newSalaryChange(ctx, salary, salaryCurrency, effectiveDate, reason)
+		change.OverrideJustification = override
+		prev, history, err := eh.addSalaryChange(ctx, currentEmp, change)
+		if err != nil {
+			logger.Ctx(ctx).Error().Err(err).Msg("Error recording salary change")
+			return GetEmpError(ErrorRecordingSalary)
+		}
+		salaries = &prev
+		if change, ok := salaryAsOf(history.Changes, eh.today()); ok {
+			currentEmp.Salary = change.Salary
+			if change.Currency != "" {
+				currentEmp.Currency = change.Currency
+			}
+		}
+	}
+
+	// undoSalaries undoes the salary change when the update as a whole fails
+	undoSalaries := func() {
+		if salaries == nil {
+			return
+		}
+		undo := func() error { return eh.salaries.UpdateItem(ctx, empInt, *salaries) }
+		if len(salaries.Changes) == 0 {
+			undo = func() error { return eh.salaries.DeleteItem(ctx, empInt) }
+		}
+		if err := undo(); err != nil {
+			logger.Ctx(ctx).Error().Err(err).Int("empId", empInt).Msg("Error reverting salary change")
+		}
+	}
+	if err := eh.db.UpdateItem(ctx, empInt, currentEmp); err != nil {
+		undoSalaries()
 		if errors.Is(err, simpledb.KeyAbsent) {
-			logger.Log.Error().Int("empId", empInt).Msg("Employee not found")
+			logger.Ctx(ctx).Error().Int("empId", empInt).Msg("Employee not found")
 			return GetEmpError(InvalidID)
 		}
-		logger.Log.Error().Err(err).Msg("Error updating employee")
+		logger.Ctx(ctx).Error().Err(err).Msg("Error updating employee")
 		return GetEmpError(ErrorUpdateEmp)
 	}
+	undo := func() {
+		if err := eh.db.UpdateItem(ctx, empInt, before); err != nil {
+			logger.Ctx(ctx).Error().Err(err).Int("empId", empInt).Msg("Error reverting employee update")
+		}
+		undoSalaries()
+	}
+	var versions []models.EmployeeVersion
+	if len(fields) > 0 {
+		versions = append(versions, eh.newVersion(models.AuditUpdate, currentEmp, eh.today(), fields...))
+	}
+	if salary != 0 {
+		versions = append(versions, eh.newVersion(models.AuditUpdate, models.Employee{ID: empInt, Salary: salary, Currency: salaryCurrency},
+			effectiveDate, "salary", "currency"))
+	}
+	prev, err := eh.addVersions(ctx, empInt, versions...)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Int("empId", empInt).Msg("Error recording employee version")
+		undo()
+		return GetEmpError(ErrorRecordingVersion)
+	}
+	if err := eh.record(ctx, empInt, models.AuditUpdate, before, currentEmp); err != nil {
+		eh.restoreVersions(ctx, empInt, prev)
+		undo()
+		return err
+	}
+	eh.reindex(before, currentEmp)
 
-	logger.Log.Debug().
+	logger.Ctx(ctx).Debug().
 		Str("empId", empID).
 		Msg("Request processed successfully")
 	return nil
 }
 
-func (eh *Employee) DeleteEmployee(empID string) error {
+func (eh *Employee) DeleteEmployee(ctx context.Context, empID string) (err error) {
+	ctx, span := tracing.Start(ctx, "employee.DeleteEmployee")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("employee.id", empID)
 
-	logger.Log.Debug().Str("empId", empID).Msg("Delete Request received")
+	logger.Ctx(ctx).Debug().Str("empId", empID).Msg("Delete Request received")
 
 	if empID == "" {
-		logger.Log.Error().Str("empId", empID).Msg("Invalid employee ID")
+		logger.Ctx(ctx).Error().Str("empId", empID).Msg("Invalid employee ID")
 		return GetEmpError(InvalidID)
 	}
 	empInt, err := strconv.Atoi(empID)
 	if err != nil {
-		logger.Log.Error().Err(err).Str("empId", empID).Msg("Invalid employee ID")
+		logger.Ctx(ctx).Error().Err(err).Str("empId", empID).Msg("Invalid employee ID")
 		return GetEmpError(InvalidID)
 	}
 
-	if err := eh.db.DeleteItem(empInt); err != nil {
+	if err := eh.migrateVersions(ctx); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error recording employee versions")
+		return GetEmpError(ErrorRecordingVersion)
+	}
+
+	unlock, err := eh.lockIndex(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error indexing employees")
+		return GetEmpError(ErrorDeleteEmp)
+	}
+	defer unlock()
+
+	// Deleting a manager would leave its reports with a dangling manager ID
+	if reports := eh.index.reports[empInt]; len(reports) > 0 {
+		logger.Ctx(ctx).Error().Int("empId", empInt).Int("reports", len(reports)).Msg("Employee has reports")
+		return GetEmpError(HasReports)
+	}
+
+	before, ok := eh.getItem(ctx, empInt)
+	if !ok {
+		logger.Ctx(ctx).Error().Int("empId", empInt).Msg("Employee not found")
+		return GetEmpError(InvalidID)
+	}
+	// The record and its salary history are kept until purged, so that the delete can be undone
+	deleted := before
+	deletedAt := eh.now().UTC().Truncate(time.Microsecond)
+	deleted.DeletedAt = &deletedAt
+	if err := eh.db.UpdateItem(ctx, empInt, deleted); err != nil {
 		if errors.Is(err, simpledb.KeyAbsent) {
-			logger.Log.Error().Int("empId", empInt).Msg("Employee not found")
+			logger.Ctx(ctx).Error().Int("empId", empInt).Msg("Employee not found")
 			return GetEmpError(InvalidID)
 		}
-		logger.Log.Error().Err(err).Msg("Error deleting employee")
+		logger.Ctx(ctx).Error().Err(err).Msg("Error deleting employee")
 		return GetEmpError(ErrorDeleteEmp)
 	}
-	logger.Log.Debug().Str("empId", empID).Msg("Request processed successfully")
+	undo := func() {
+		if err := eh.db.UpdateItem(ctx, empInt, before); err != nil {
+			logger.Ctx(ctx).Error().Err(err).Int("empId", empInt).Msg("Error reverting employee delete")
+		}
+	}
+	prev, err := eh.addVersions(ctx, empInt, eh.newVersion(models.AuditDelete, models.Employee{ID: empInt}, eh.today()))
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Int("empId", empInt).Msg("Error recording employee version")
+		undo()
+		return GetEmpError(ErrorRecordingVersion)
+	}
+	if err := eh.record(ctx, empInt, models.AuditDelete, before, nil); err != nil {
+		eh.restoreVersions(ctx, empInt, prev)
+		undo()
+		return err
+	}
+	eh.reindex(before, models.Employee{})
+	logger.Ctx(ctx).Debug().Str("empId", empID).Msg("Request processed successfully")
 	return nil
 }
@@ -23,18 +23,100 @@ const (
 	ErrorDeleteEmp
 	InvalidEmpUpdate
 	ErrorUpdateEmp
+	InvalidEffectiveDate
+	InvalidSalaryReason
+	InvalidAsOf
+	ErrorRecordingSalary
+	InvalidKnownAt
+	ErrorRecordingVersion
+	InvalidEmail
+	EmailAlreadyExists
+	InvalidDepartment
+	InvalidManagerID
+	InvalidHireDate
+	InvalidTerminationDate
+	InvalidStatus
+	ManagerNotFound
+	ManagerCycle
+	HasReports
+	ErrorMovingReports
+	DepartmentNotFound
+	InvalidDepartmentName
+	DepartmentAlreadyExists
+	DepartmentHasEmployees
+	ErrorAddingDepartment
+	ErrorGettingDepartment
+	ErrorUpdatingDepartment
+	ErrorDeletingDepartment
+	EmpNotDeleted
+	ErrorRestoringEmp
+	ErrorPurgingEmp
+	PositionNotFound
+	InvalidPositionBand
+	PositionAlreadyExists
+	SalaryOutsideBand
+	InvalidSalaryJustification
+	ErrorAddingPosition
+	ErrorGettingPosition
+	ErrorUpdatingPosition
+	ErrorDeletingPosition
+	InvalidCurrency
+	ExchangeRateNotFound
+	InvalidStatsQuery
+	ErrorGettingStats
 )
 
 var EmpErrors = map[EmpError]*apierror.APIError{
-	InvalidID:            {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidID), ErrorMessage: "Provide a valid ID"},
-	NameInvalid:          {HttpStatusCode: http.StatusBadRequest, ErrCode: int(NameInvalid), ErrorMessage: "Name cannot be empty or longer than 100 characters"},
-	InvalidPosition:      {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidPosition), ErrorMessage: "Position cannot be empty or longer than 100 characters"},
-	InvalidSalary:        {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidSalary), ErrorMessage: "Salary cannot be less than equal to 0"},
-	EmpAlreadyExists:     {HttpStatusCode: http.StatusBadRequest, ErrCode: int(EmpAlreadyExists), ErrorMessage: "Employee already exists"},
-	ErrorAddingEmp:       {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorAddingEmp), ErrorMessage: "Error adding employee"},
-	InvalidLastEvalKeyID: {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidLastEvalKeyID), ErrorMessage: "Invalid last evaluated ID"},
-	ErrorGettingEmp:      {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorGettingEmp), ErrorMessage: "Error getting employees"},
-	ErrorDeleteEmp:       {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorDeleteEmp), ErrorMessage: "Error deleting employee"},
-	InvalidEmpUpdate:     {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidEmpUpdate), ErrorMessage: "Invalid employee update request"},
-	ErrorUpdateEmp:       {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorUpdateEmp), ErrorMessage: "Error updating employee"},
+	InvalidID:                  {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidID), ErrorMessage: "Provide a valid ID"},
+	NameInvalid:                {HttpStatusCode: http.StatusBadRequest, ErrCode: int(NameInvalid), ErrorMessage: "Name cannot be empty or longer than 100 characters"},
+	InvalidPosition:            {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidPosition), ErrorMessage: "Position cannot be empty or longer than 100 characters"},
+	InvalidSalary:              {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidSalary), ErrorMessage: "Salary must be greater than 0 with no more decimals than its currency has"},
+	EmpAlreadyExists:           {HttpStatusCode: http.StatusBadRequest, ErrCode: int(EmpAlreadyExists), ErrorMessage: "Employee already exists"},
+	ErrorAddingEmp:             {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorAddingEmp), ErrorMessage: "Error adding employee"},
+	InvalidLastEvalKeyID:       {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidLastEvalKeyID), ErrorMessage: "Invalid last evaluated ID"},
+	ErrorGettingEmp:            {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorGettingEmp), ErrorMessage: "Error getting employees"},
+	ErrorDeleteEmp:             {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorDeleteEmp), ErrorMessage: "Error deleting employee"},
+	InvalidEmpUpdate:           {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidEmpUpdate), ErrorMessage: "Invalid employee update request"},
+	ErrorUpdateEmp:             {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorUpdateEmp), ErrorMessage: "Error updating employee"},
+	InvalidEffectiveDate:       {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidEffectiveDate), ErrorMessage: "Effective date must be a YYYY-MM-DD date and requires a salary"},
+	InvalidSalaryReason:        {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidSalaryReason), ErrorMessage: "Reason cannot be longer than 200 characters and requires a salary"},
+	InvalidAsOf:                {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidAsOf), ErrorMessage: "as_of must be a YYYY-MM-DD date"},
+	ErrorRecordingSalary:       {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorRecordingSalary), ErrorMessage: "Error recording salary change"},
+	InvalidKnownAt:             {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidKnownAt), ErrorMessage: "known_at must be an RFC 3339 time or a YYYY-MM-DD date"},
+	ErrorRecordingVersion:      {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorRecordingVersion), ErrorMessage: "Error recording employee version"},
+	InvalidEmail:               {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidEmail), ErrorMessage: "Email must be a valid address of at most 254 characters"},
+	EmailAlreadyExists:         {HttpStatusCode: http.StatusBadRequest, ErrCode: int(EmailAlreadyExists), ErrorMessage: "Email is already used by another employee"},
+	InvalidDepartment:          {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidDepartment), ErrorMessage: "Department cannot be longer than 100 characters"},
+	InvalidManagerID:           {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidManagerID), ErrorMessage: "Manager ID must be another employee's ID"},
+	InvalidHireDate:            {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidHireDate), ErrorMessage: "Hire date must be a YYYY-MM-DD date"},
+	InvalidTerminationDate:     {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidTerminationDate), ErrorMessage: "Termination date must be a YYYY-MM-DD date not before the hire date"},
+	InvalidStatus:              {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidStatus), ErrorMessage: "Status must be active, on_leave or terminated, and terminated requires a termination date"},
+	ManagerNotFound:            {HttpStatusCode: http.StatusBadRequest, ErrCode: int(ManagerNotFound), ErrorMessage: "Manager does not exist"},
+	ManagerCycle:               {HttpStatusCode: http.StatusBadRequest, ErrCode: int(ManagerCycle), ErrorMessage: "Manager reports to the employee, directly or indirectly"},
+	HasReports:                 {HttpStatusCode: http.StatusBadRequest, ErrCode: int(HasReports), ErrorMessage: "Employee has reports, move them to another manager first"},
+	ErrorMovingReports:         {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorMovingReports), ErrorMessage: "Error moving reports"},
+	DepartmentNotFound:         {HttpStatusCode: http.StatusBadRequest, ErrCode: int(DepartmentNotFound), ErrorMessage: "Department does not exist"},
+	InvalidDepartmentName:      {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidDepartmentName), ErrorMessage: "Department name cannot be empty or longer than 100 characters"},
+	DepartmentAlreadyExists:    {HttpStatusCode: http.StatusBadRequest, ErrCode: int(DepartmentAlreadyExists), ErrorMessage: "Department already exists"},
+	DepartmentHasEmployees:     {HttpStatusCode: http.StatusBadRequest, ErrCode: int(DepartmentHasEmployees), ErrorMessage: "Department has employees, move them to another department first"},
+	ErrorAddingDepartment:      {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorAddingDepartment), ErrorMessage: "Error adding department"},
+	ErrorGettingDepartment:     {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorGettingDepartment), ErrorMessage: "Error getting departments"},
+	ErrorUpdatingDepartment:    {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorUpdatingDepartment), ErrorMessage: "Error updating department"},
+	ErrorDeletingDepartment:    {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorDeletingDepartment), ErrorMessage: "Error deleting department"},
+	EmpNotDeleted:              {HttpStatusCode: http.StatusBadRequest, ErrCode: int(EmpNotDeleted), ErrorMessage: "Employee is not deleted"},
+	ErrorRestoringEmp:          {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorRestoringEmp), ErrorMessage: "Error restoring employee"},
+	ErrorPurgingEmp:            {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorPurgingEmp), ErrorMessage: "Error purging deleted employees"},
+	PositionNotFound:           {HttpStatusCode: http.StatusBadRequest, ErrCode: int(PositionNotFound), ErrorMessage: "Position does not exist"},
+	InvalidPositionBand:        {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidPositionBand), ErrorMessage: "Salary band must satisfy 0 < min <= mid <= max with no more decimals than its currency has"},
+	PositionAlreadyExists:      {HttpStatusCode: http.StatusBadRequest, ErrCode: int(PositionAlreadyExists), ErrorMessage: "Position already exists"},
+	SalaryOutsideBand:          {HttpStatusCode: http.StatusBadRequest, ErrCode: int(SalaryOutsideBand), ErrorMessage: "Salary is outside the band of the position, provide a salary_justification to override"},
+	InvalidSalaryJustification: {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidSalaryJustification), ErrorMessage: "Salary justification cannot be longer than 500 characters and requires a salary or position"},
+	ErrorAddingPosition:        {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorAddingPosition), ErrorMessage: "Error adding position"},
+	ErrorGettingPosition:       {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorGettingPosition), ErrorMessage: "Error getting positions"},
+	ErrorUpdatingPosition:      {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorUpdatingPosition), ErrorMessage: "Error updating position"},
+	ErrorDeletingPosition:      {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorDeletingPosition), ErrorMessage: "Error deleting position"},
+	InvalidCurrency:            {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidCurrency), ErrorMessage: "Currency must be an ISO 4217 code and can only be changed together with the salary"},
+	ExchangeRateNotFound:       {HttpStatusCode: http.StatusBadRequest, ErrCode: int(ExchangeRateNotFound), ErrorMessage: "No exchange rate converts between the currencies on this date"},
+	InvalidStatsQuery:          {HttpStatusCode: http.StatusBadRequest, ErrCode: int(InvalidStatsQuery), ErrorMessage: "group_by must be position or department and percentiles decimals between 0 and 100"},
+	ErrorGettingStats:          {HttpStatusCode: http.StatusInternalServerError, ErrCode: int(ErrorGettingStats), ErrorMessage: "Error getting employee statistics"},
 }
@@ -0,0 +1,246 @@
+package employee
+
+import (
+	"context"
+	"employee/models"
+	"employee/pkg/logger"
+	"employee/pkg/tracing"
+	"strconv"
+)
+
+// checkManager checks that managerID can be the manager of id: it must be an existing
+// employee other than id that does not report to id, directly or not. The index must
+// be locked.
+func (eh *Employee) checkManager(ctx context.Context, id, managerID int) error {
+	if managerID == 0 {
+		return nil
+	}
+	if _, ok := eh.getItem(ctx, managerID); !ok {
+		logger.Ctx(ctx).Error().Int("managerId", managerID).Msg("Manager not found")
+		return GetEmpError(ManagerNotFound)
+	}
+	// Walk up from the new manager, meeting id means the move would close a cycle
+	for m, steps := managerID, 0; m != 0 && steps <= len(eh.index.managers); m, steps = eh.index.managers[m], steps+1 {
+		if m == id {
+			logger.Ctx(ctx).Error().Int("empId", id).Int("managerId", managerID).Msg("Manager reports to the employee")
+			return GetEmpError(ManagerCycle)
+		}
+	}
+	return nil
+}
+
+// subordinates returns the IDs of the direct reports of id, or of every employee below
+// id when recursive, level by level. The index must be locked.
+func (eh *Employee) subordinates(id int, recursive bool) []int {
+	if !recursive {
+		return append([]int{}, eh.index.reports[id]...)
+	}
+	var ids []int
+	for queue := []int{id}; len(queue) > 0; queue = queue[1:] {
+		reports := eh.index.reports[queue[0]]
+		ids = append(ids, reports...)
+		queue = append(queue, reports...)
+	}
+	return ids
+}
+
+// ManagerOf returns the manager ID of employee id, or false if it has none or does
+// not exist.
+func (eh *Employee) ManagerOf(ctx context.Context, id int) (int, bool) {
+	unlock, err := eh.lockIndex(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error indexing employees")
+		return 0, false
+	}
+	defer unlock()
+	managerID, ok := eh.index.managers[id]
+	return managerID, ok
+}
+
+// employees returns the stored records of ids with the salary in effect today,
+// skipping any that no longer exist.
+func (eh *Employee) employees(ctx context.Context, ids []int) []models.Employee {
+	emps := []models.Employee{}
+	for _, id := range ids {
+		if emp, ok := eh.getItem(ctx, id); ok {
+			emps = append(emps, emp)
+		}
+	}
+	eh.applySalaries(ctx, emps, eh.today())
+	return emps
+}
+
+// GetReports returns the employees reporting directly to an employee or, when recursive
+// is set, every employee below it, level by level and by ID within a team.
+//
+// It returns an error if the ID is invalid or the employee does not exist.
+func (eh *Employee) GetReports(ctx context.Context, empID string, recursive bool) (res models.GetEmployeeResponse, err error) {
+	ctx, span := tracing.Start(ctx, "employee.GetReports")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("employee.id", empID)
+
+	logger.Ctx(ctx).Debug().Str("empId", empID).Bool("recursive", recursive).Msg("Reports request received")
+
+	empInt, err := strconv.Atoi(empID)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("empId", empID).Msg("Invalid employee ID")
+		return res, GetEmpError(InvalidID)
+	}
+	unlock, err := eh.lockIndex(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error indexing employees")
+		return res, GetEmpError(ErrorGettingEmp)
+	}
+	if _, ok := eh.getItem(ctx, empInt); !ok {
+		unlock()
+		logger.Ctx(ctx).Error().Int("empId", empInt).Msg("Employee not found")
+		return res, GetEmpError(InvalidID)
+	}
+	ids := eh.subordinates(empInt, recursive)
+	unlock()
+
+	res.Employees = eh.employees(ctx, ids)
+	logger.Ctx(ctx).Debug().Str("empId", empID).Int("reports", len(res.Employees)).Msg("Request processed successfully")
+	return res, nil
+}
+
+// GetChain returns the management chain of an employee, from its manager up to the
+// top of the hierarchy.
+//
+// It returns an error if the ID is invalid or the employee does not exist.
+func (eh *Employee) GetChain(ctx context.Context, empID string) (res models.GetEmployeeResponse, err error) {
+	ctx, span := tracing.Start(ctx, "employee.GetChain")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("employee.id", empID)
+
+	logger.Ctx(ctx).Debug().Str("empId", empID).Msg("Chain request received")
+
+	empInt, err := strconv.Atoi(empID)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("empId", empID).Msg("Invalid employee ID")
+		return res, GetEmpError(InvalidID)
+	}
+	unlock, err := eh.lockIndex(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error indexing employees")
+		return res, GetEmpError(ErrorGettingEmp)
+	}
+	if _, ok := eh.getItem(ctx, empInt); !ok {
+		unlock()
+		logger.Ctx(ctx).Error().Int("empId", empInt).Msg("Employee not found")
+		return res, GetEmpError(InvalidID)
+	}
+	var ids []int
+	for m := eh.index.managers[empInt]; m != 0; m = eh.index.managers[m] {
+		ids = append(ids, m)
+	}
+	unlock()
+
+	res.Employees = eh.employees(ctx, ids)
+	logger.Ctx(ctx).Debug().Str("empId", empID).Int("chain", len(res.Employees)).Msg("Request processed successfully")
+	return res, nil
+}
+
+// MoveReports makes every direct report of an employee report to managerID instead,
+// taking their own reports along. Either every report is moved or none is.
+//
+// It returns the IDs of the moved employees, or an error if either ID is invalid, the
+// new manager does not exist or reports to one of the moved employees.
+func (eh *Employee) MoveReports(ctx context.Context, empID string, managerID int) (moved []int, err error) {
+	ctx, span := tracing.Start(ctx, "employee.MoveReports")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("employee.id", empID)
+
+	logger.Ctx(ctx).Debug().Str("empId", empID).Int("managerId", managerID).Msg("Move reports request received")
+
+	empInt, err := strconv.Atoi(empID)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("empId", empID).Msg("Invalid employee ID")
+		return nil, GetEmpError(InvalidID)
+	}
+	if managerID < 0 || managerID == empInt {
+		logger.Ctx(ctx).Error().Int("managerId", managerID).Msg("Invalid manager ID")
+		return nil, GetEmpError(InvalidManagerID)
+	}
+	if err := eh.migrateVersions(ctx); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error recording employee versions")
+		return nil, GetEmpError(ErrorRecordingVersion)
+	}
+
+	unlock, err := eh.lockIndex(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error indexing employees")
+		return nil, GetEmpError(ErrorMovingReports)
+	}
+	defer unlock()
+
+	if _, ok := eh.getItem(ctx, empInt); !ok {
+		logger.Ctx(ctx).Error().Int("empId", empInt).Msg("Employee not found")
+		return nil, GetEmpError(InvalidID)
+	}
+	ids := eh.subordinates(empInt, false)
+	for _, id := range ids {
+		if err := eh.checkManager(ctx, id, managerID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Write every report first, undoing the writes made so far if one fails
+	var before, after []models.Employee
+	revert := func() {
+		for i := range before {
+			if err := eh.db.UpdateItem(ctx, before[i].ID, before[i]); err != nil {
+				logger.Ctx(ctx).Error().Err(err).Int("empId", before[i].ID).Msg("Error reverting moved report")
+			}
+		}
+	}
+	for _, id := range ids {
+		emp, _ := eh.db.GetItem(ctx, id)
+		next := emp
+		next.ManagerID = managerID
+		if err := eh.db.UpdateItem(ctx, id, next); err != nil {
+			logger.Ctx(ctx).Error().Err(err).Int("empId", id).Msg("Error moving report, reverting the move")
+			revert()
+			return nil, GetEmpError(ErrorMovingReports)
+		}
+		before, after = append(before, emp), append(after, next)
+	}
+	// A move that cannot be versioned is undone as a whole, like the writes
+	var prev []models.EmployeeVersions
+	revertVersions := func() {
+		for i := range prev {
+			eh.restoreVersions(ctx, after[i].ID, prev[i])
+		}
+	}
+	for i := range before {
+		history, err := eh.addVersions(ctx, after[i].ID, eh.newVersion(models.AuditUpdate, after[i], eh.today(), "manager_id"))
+		if err != nil {
+			logger.Ctx(ctx).Error().Err(err).Int("empId", after[i].ID).Msg("Error recording employee version, reverting the move")
+			revertVersions()
+			revert()
+			return nil, GetEmpError(ErrorRecordingVersion)
+		}
+		prev = append(prev, history)
+	}
+	// A move that cannot be audited is undone, the moves already audited are recorded
+	// as moved back
+	for i := range before {
+		if err := eh.record(ctx, after[i].ID, models.AuditUpdate, before[i], after[i]); err != nil {
+			revertVersions()
+			revert()
+			for j := 0; j < i; j++ {
+				if err := eh.record(ctx, after[j].ID, models.AuditUpdate, after[j], before[j]); err != nil {
+					logger.Ctx(ctx).Error().Err(err).Int("empId", after[j].ID).Msg("Error recording reverted move")
+				}
+			}
+			return nil, err
+		}
+	}
+
+	for i := range before {
+		eh.reindex(before[i], after[i])
+	}
+
+	logger.Ctx(ctx).Debug().Str("empId", empID).Int("moved", len(ids)).Msg("Request processed successfully")
+	return ids, nil
+}
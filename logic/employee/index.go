@@ -0,0 +1,197 @@
+package employee
+
+import (
+	"context"
+	"employee/models"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// index holds the lookups spanning employees: the owner of every email, the
+// manager and direct reports of every employee, and the rollup of every department.
+// It is built from the stored employees on first use and kept up to date by every
+// write.
+type index struct {
+	mutex  sync.Mutex
+	loaded bool
+	emails map[string]int
+	// managers maps each employee with a manager to its manager ID.
+	managers map[int]int
+	// reports lists the direct reports of each manager, ordered by ID.
+	reports map[int][]int
+	// departments holds the rollup of each department with employees.
+	departments map[string]rollup
+	// pay holds the salary each employee with a department is counted with in the
+	// rollups.
+	pay map[int]pay
+	// day is the date the counted salaries are in effect on.
+	day string
+}
+
+// rollup is the headcount and salary totals of a department.
+type rollup struct {
+	headcount int
+	// totals is the sum of the salaries paid in each currency.
+	totals map[string]models.Money
+}
+
+// pay is the salary of an employee in the rollup of its department.
+type pay struct {
+	department string
+	currency   string
+	salary     models.Money
+}
+
+// lockIndex locks the index, loading it first if needed. The index stays locked
+// until the returned function is called, so that a check and the write it guards
+// are atomic.
+func (eh *Employee) lockIndex(ctx context.Context) (func(), error) {
+	eh.index.mutex.Lock()
+	if !eh.index.loaded {
+		if err := eh.loadIndex(ctx); err != nil {
+			eh.index.mutex.Unlock()
+			return nil, err
+		}
+	}
+	eh.countSalaries(ctx)
+	return eh.index.mutex.Unlock, nil
+}
+
+// loadIndex builds the index from the stored employees. The index must be locked.
+func (eh *Employee) loadIndex(ctx context.Context) error {
+	eh.index.emails = map[string]int{}
+	eh.index.managers = map[int]int{}
+	eh.index.reports = map[int][]int{}
+	eh.index.departments = map[string]rollup{}
+	eh.index.pay = map[int]pay{}
+	eh.index.day = ""
+	var last int
+	for {
+		emps, next, err := eh.db.GetItems(ctx, last, scanPageSize)
+		if err != nil {
+			return err
+		}
+		for _, emp := range emps {
+			if emp.DeletedAt == nil {
+				eh.reindex(models.Employee{}, emp)
+			}
+		}
+		if next == 0 {
+			break
+		}
+		last = next
+	}
+	eh.index.loaded = true
+	return nil
+}
+
+// countSalaries counts in the rollups the salaries in effect today. Employees are
+// written with the salary in effect on the day of the write, so once a day passes
+// the salary histories are read again for the scheduled changes that took effect
+// since. The index must be locked.
+func (eh *Employee) countSalaries(ctx context.Context) {
+	today := eh.today()
+	if eh.index.day == today {
+		return
+	}
+	for id, p := range eh.index.pay {
+		history, ok := eh.salaries.GetItem(ctx, id)
+		if !ok {
+			continue
+		}
+		change, ok := salaryAsOf(history.Changes, today)
+		if !ok {
+			continue
+		}
+		next := p
+		next.salary = change.Salary
+		// Changes recorded before currencies were are in the currency of the employee
+		if change.Currency != "" {
+			next.currency = change.Currency
+		}
+		if next != p {
+			eh.uncount(p)
+			eh.count(next)
+			eh.index.pay[id] = next
+		}
+	}
+	eh.index.day = today
+}
+
+// count adds p to the rollup of its department. The index must be locked.
+func (eh *Employee) count(p pay) {
+	r := eh.index.departments[p.department]
+	if r.totals == nil {
+		r.totals = map[string]models.Money{}
+	}
+	r.headcount++
+	r.totals[p.currency] += p.salary
+	eh.index.departments[p.department] = r
+}
+
+// uncount removes p from the rollup of its department. The index must be locked.
+func (eh *Employee) uncount(p pay) {
+	r := eh.index.departments[p.department]
+	r.headcount--
+	r.totals[p.currency] -= p.salary
+	if r.headcount == 0 {
+		delete(eh.index.departments, p.department)
+	} else {
+		eh.index.departments[p.department] = r
+	}
+}
+
+// emailTaken reports whether email belongs to an employee other than id. The index
+// must be locked.
+func (eh *Employee) emailTaken(email string, id int) bool {
+	owner, ok := eh.index.emails[strings.ToLower(email)]
+	return ok && owner != id
+}
+
+// reindex replaces before with after in the index. A zero ID stands for an absent
+// record, so that creates pass an empty before and deletes an empty after. The
+// index must be locked.
+func (eh *Employee) reindex(before, after models.Employee) {
+	var counted pay
+	var wasCounted bool
+	if before.ID != 0 {
+		if key := strings.ToLower(before.Email); before.Email != "" && eh.index.emails[key] == before.ID {
+			delete(eh.index.emails, key)
+		}
+		if before.ManagerID != 0 {
+			delete(eh.index.managers, before.ID)
+			reports := slices.DeleteFunc(eh.index.reports[before.ManagerID], func(id int) bool { return id == before.ID })
+			if len(reports) == 0 {
+				delete(eh.index.reports, before.ManagerID)
+			} else {
+				eh.index.reports[before.ManagerID] = reports
+			}
+		}
+		if counted, wasCounted = eh.index.pay[before.ID]; wasCounted {
+			eh.uncount(counted)
+			delete(eh.index.pay, before.ID)
+		}
+	}
+	if after.ID != 0 {
+		if after.Email != "" {
+			eh.index.emails[strings.ToLower(after.Email)] = after.ID
+		}
+		if after.ManagerID != 0 {
+			eh.index.managers[after.ID] = after.ManagerID
+			reports := eh.index.reports[after.ManagerID]
+			i, _ := slices.BinarySearch(reports, after.ID)
+			eh.index.reports[after.ManagerID] = slices.Insert(reports, i, after.ID)
+		}
+		if after.Department != "" {
+			p := pay{department: after.Department, currency: eh.currencyOf(after), salary: after.Salary}
+			// A write leaving the stored salary alone keeps the salary counted, which may
+			// be a scheduled change that took effect since the employee was stored
+			if wasCounted && after.ID == before.ID && after.Salary == before.Salary && eh.currencyOf(after) == eh.currencyOf(before) {
+				p.currency, p.salary = counted.currency, counted.salary
+			}
+			eh.count(p)
+			eh.index.pay[after.ID] = p
+		}
+	}
+}
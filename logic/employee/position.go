@@ -0,0 +1,193 @@
+package employee
+
+import (
+	"context"
+	"employee/models"
+	"employee/pkg/logger"
+	"employee/pkg/tracing"
+	"employee/service/simpledb"
+	"errors"
+)
+
+// maxJustificationLength is the longest salary band override justification accepted.
+const maxJustificationLength = 500
+
+// SetPositionDB keeps the position catalogue in db instead of a new in-memory database.
+func (eh *Employee) SetPositionDB(db *simpledb.Database[string, models.Position]) {
+	eh.positions = db
+}
+
+// validBand reports whether 0 < min <= mid <= max.
+func validBand(min, mid, max models.Money) bool {
+	return min > 0 && min <= mid && mid <= max
+}
+
+// checkBand checks salaries against the band of position, converted into the currency
+// of the band at today's rates. A salary outside the band is accepted with a
+// justification, which is returned to be recorded with the salary. Positions missing
+// from the catalogue have no band.
+func (eh *Employee) checkBand(ctx context.Context, position, justification string, salaries ...amount) (string, error) {
+	p, ok := eh.positions.GetItem(ctx, position)
+	if !ok {
+		return "", nil
+	}
+	// Positions catalogued before currencies were have a band in the default currency
+	bandCurrency := p.Currency
+	if bandCurrency == "" {
+		bandCurrency = eh.defaultCurrency
+	}
+	low, high := p.MinSalary.Rat(), p.MaxSalary.Rat()
+	for _, salary := range salaries {
+		value, err := eh.convert(ctx, salary.value.Rat(), salary.currency, bandCurrency, eh.today())
+		if err != nil {
+			return "", err
+		}
+		if value.Cmp(low) >= 0 && value.Cmp(high) <= 0 {
+			continue
+		}
+		if justification == "" {
+			logger.Ctx(ctx).Error().Str("position", position).Stringer("salary", salary.value).Str("currency", salary.currency).
+				Stringer("minSalary", p.MinSalary).Stringer("maxSalary", p.MaxSalary).Msg("Salary outside band")
+			return "", GetEmpError(SalaryOutsideBand)
+		}
+		logger.Ctx(ctx).Warn().Str("position", position).Stringer("salary", salary.value).Str("currency", salary.currency).
+			Stringer("minSalary", p.MinSalary).Stringer("maxSalary", p.MaxSalary).Msg("Salary outside band accepted with a justification")
+		return justification, nil
+	}
+	return "", nil
+}
+
+// CreatePosition adds a position and its salary band to the catalogue.
+//
+// It returns an error if the title or band is invalid or the position already exists.
+func (eh *Employee) CreatePosition(ctx context.Context, position models.Position) (err error) {
+	ctx, span := tracing.Start(ctx, "employee.CreatePosition")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("position.title", position.Title)
+
+	logger.Ctx(ctx).Debug().Str("title", position.Title).Msg("Create position request received")
+
+	if position.Title == "" || len(position.Title) > 100 {
+		logger.Ctx(ctx).Error().Str("title", position.Title).Msg("Invalid position title")
+		return GetEmpError(InvalidPosition)
+	}
+	if !validBand(position.MinSalary, position.MidSalary, position.MaxSalary) {
+		logger.Ctx(ctx).Error().Stringer("minSalary", position.MinSalary).Stringer("midSalary", position.MidSalary).
+			Stringer("maxSalary", position.MaxSalary).Msg("Invalid salary band")
+		return GetEmpError(InvalidPositionBand)
+	}
+	if position.Currency == "" {
+		position.Currency = eh.defaultCurrency
+	}
+	if err := checkCurrency(ctx, position.Currency); err != nil {
+		return err
+	}
+	if !fitsMinorUnit(position.MinSalary, position.Currency) || !fitsMinorUnit(position.MidSalary, position.Currency) ||
+		!fitsMinorUnit(position.MaxSalary, position.Currency) {
+		logger.Ctx(ctx).Error().Str("currency", position.Currency).Msg("Salary band finer than the minor unit of its currency")
+		return GetEmpError(InvalidPositionBand)
+	}
+
+	if err := eh.positions.SetItem(ctx, position.Title, position); err != nil {
+		if errors.Is(err, simpledb.KeyAlreadyPresent) {
+			logger.Ctx(ctx).Error().Str("title", position.Title).Msg("Position already exists")
+			return GetEmpError(PositionAlreadyExists)
+		}
+		logger.Ctx(ctx).Error().Err(err).Msg("Error adding position")
+		return GetEmpError(ErrorAddingPosition)
+	}
+	logger.Ctx(ctx).Debug().Str("title", position.Title).Msg("Request processed successfully")
+	return nil
+}
+
+// GetPositions returns the position with the given title, or the next page of
+// positions in creation order.
+func (eh *Employee) GetPositions(ctx context.Context, title, LastEvalKeyID, numRecords string) (res models.GetPositionsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "employee.GetPositions")
+	defer func() { span.SetError(err); span.End() }()
+
+	logger.Ctx(ctx).Debug().Str("title", title).Str("lastEvalKeyId", LastEvalKeyID).Str("numRecords", numRecords).
+		Msg("Get positions request received")
+
+	if title != "" {
+		position, ok := eh.positions.GetItem(ctx, title)
+		if !ok {
+			logger.Ctx(ctx).Error().Str("title", title).Msg("Position not found")
+			return res, GetEmpError(PositionNotFound)
+		}
+		res.Positions = []models.Position{position}
+		return res, nil
+	}
+
+	positions, last, err := eh.positions.GetItems(ctx, LastEvalKeyID, eh.pageSize(ctx, numRecords))
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("lastEvalKeyId", LastEvalKeyID).Msg("Error getting positions")
+		return res, GetEmpError(InvalidLastEvalKeyID)
+	}
+	res.Positions = positions
+	if res.Positions == nil {
+		res.Positions = []models.Position{}
+	}
+	res.LastEvalKeyID = last
+	logger.Ctx(ctx).Debug().Int("positions", len(res.Positions)).Msg("Request processed successfully")
+	return res, nil
+}
+
+// UpdatePosition replaces the salary band of a position. Salaries already recorded
+// are not checked against the new band.
+func (eh *Employee) UpdatePosition(ctx context.Context, title string, req models.PositionUpdateRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "employee.UpdatePosition")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("position.title", title)
+
+	logger.Ctx(ctx).Debug().Str("title", title).Msg("Update position request received")
+
+	if !validBand(req.MinSalary, req.MidSalary, req.MaxSalary) {
+		logger.Ctx(ctx).Error().Stringer("minSalary", req.MinSalary).Stringer("midSalary", req.MidSalary).
+			Stringer("maxSalary", req.MaxSalary).Msg("Invalid salary band")
+		return GetEmpError(InvalidPositionBand)
+	}
+	if req.Currency == "" {
+		req.Currency = eh.defaultCurrency
+	}
+	if err := checkCurrency(ctx, req.Currency); err != nil {
+		return err
+	}
+	if !fitsMinorUnit(req.MinSalary, req.Currency) || !fitsMinorUnit(req.MidSalary, req.Currency) ||
+		!fitsMinorUnit(req.MaxSalary, req.Currency) {
+		logger.Ctx(ctx).Error().Str("currency", req.Currency).Msg("Salary band finer than the minor unit of its currency")
+		return GetEmpError(InvalidPositionBand)
+	}
+	position := models.Position{Title: title, MinSalary: req.MinSalary, MidSalary: req.MidSalary, MaxSalary: req.MaxSalary, Currency: req.Currency}
+	if err := eh.positions.UpdateItem(ctx, title, position); err != nil {
+		if errors.Is(err, simpledb.KeyAbsent) {
+			logger.Ctx(ctx).Error().Str("title", title).Msg("Position not found")
+			return GetEmpError(PositionNotFound)
+		}
+		logger.Ctx(ctx).Error().Err(err).Msg("Error updating position")
+		return GetEmpError(ErrorUpdatingPosition)
+	}
+	logger.Ctx(ctx).Debug().Str("title", title).Msg("Request processed successfully")
+	return nil
+}
+
+// DeletePosition removes a position from the catalogue. Employees holding it keep
+// their position, which no longer has a band.
+func (eh *Employee) DeletePosition(ctx context.Context, title string) (err error) {
+	ctx, span := tracing.Start(ctx, "employee.DeletePosition")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("position.title", title)
+
+	logger.Ctx(ctx).Debug().Str("title", title).Msg("Delete position request received")
+
+	if err := eh.positions.DeleteItem(ctx, title); err != nil {
+		if errors.Is(err, simpledb.KeyAbsent) {
+			logger.Ctx(ctx).Error().Str("title", title).Msg("Position not found")
+			return GetEmpError(PositionNotFound)
+		}
+		logger.Ctx(ctx).Error().Err(err).Msg("Error deleting position")
+		return GetEmpError(ErrorDeletingPosition)
+	}
+	logger.Ctx(ctx).Debug().Str("title", title).Msg("Request processed successfully")
+	return nil
+}
@@ -0,0 +1,237 @@
+package employee
+
+import (
+	"context"
+	"employee/models"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"employee/pkg/tracing"
+	"employee/service/simpledb"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// PurgeActor is the audit actor of the employees removed by RunPurge.
+const PurgeActor = "retention-purge"
+
+// getItem returns the stored employee with the given ID, or false if it does not
+// exist or is deleted.
+func (eh *Employee) getItem(ctx context.Context, id int) (models.Employee, bool) {
+	emp, ok := eh.db.GetItem(ctx, id)
+	if !ok || emp.DeletedAt != nil {
+		return models.Employee{}, false
+	}
+	return emp, true
+}
+
+// getItems is GetItems skipping deleted employees: it reads on until n employees are
+// found, and the last ID is zero once every employee has been read.
+func (eh *Employee) getItems(ctx context.Context, last, n int) ([]models.Employee, int, error) {
+	emps := []models.Employee{}
+	for {
+		page, next, err := eh.db.GetItems(ctx, last, n-len(emps))
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, emp := range page {
+			if emp.DeletedAt == nil {
+				emps = append(emps, emp)
+			}
+		}
+		if next == 0 || len(emps) == n {
+			return emps, next, nil
+		}
+		last = next
+	}
+}
+
+// RestoreEmployee undoes the delete of an employee that has not been purged yet. Its
+// email, department and manager are checked again, as they may have changed since.
+//
+// It returns an error if the ID is invalid or the employee is not deleted.
+func (eh *Employee) RestoreEmployee(ctx context.Context, empID string) (err error) {
+	ctx, span := tracing.Start(ctx, "employee.RestoreEmployee")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("employee.id", empID)
+
+	logger.Ctx(ctx).Debug().Str("empId", empID).Msg("Restore Request received")
+
+	empInt, err := strconv.Atoi(empID)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("empId", empID).Msg("Invalid employee ID")
+		return GetEmpError(InvalidID)
+	}
+	if err := eh.migrateVersions(ctx); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error recording employee versions")
+		return GetEmpError(ErrorRecordingVersion)
+	}
+
+	unlock, err := eh.lockIndex(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error indexing employees")
+		return GetEmpError(ErrorRestoringEmp)
+	}
+	defer unlock()
+
+	emp, ok := eh.db.GetItem(ctx, empInt)
+	if !ok {
+		logger.Ctx(ctx).Error().Int("empId", empInt).Msg("Employee not found")
+		return GetEmpError(InvalidID)
+	}
+	if emp.DeletedAt == nil {
+		logger.Ctx(ctx).Error().Int("empId", empInt).Msg("Employee is not deleted")
+		return GetEmpError(EmpNotDeleted)
+	}
+
+	deleted := emp
+	emp.DeletedAt = nil
+	if emp.Email != "" && eh.emailTaken(emp.Email, empInt) {
+		logger.Ctx(ctx).Error().Str("email", emp.Email).Msg("Email already exists")
+		return GetEmpError(EmailAlreadyExists)
+	}
+	if err := eh.checkDepartment(ctx, emp.Department); err != nil {
+		return err
+	}
+	if err := eh.checkManager(ctx, empInt, emp.ManagerID); err != nil {
+		return err
+	}
+	// The salary history was kept, the salary may have changed while deleted
+	if history, ok := eh.salaries.GetItem(ctx, empInt); ok {
+		if change, ok := salaryAsOf(history.Changes, eh.today()); ok {
+			emp.Salary = change.Salary
+			if change.Currency != "" {
+				emp.Currency = change.Currency
+			}
+		}
+	}
+
+	if err := eh.db.UpdateItem(ctx, empInt, emp); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error restoring employee")
+		return GetEmpError(ErrorRestoringEmp)
+	}
+	undo := func() {
+		if err := eh.db.UpdateItem(ctx, empInt, deleted); err != nil {
+			logger.Ctx(ctx).Error().Err(err).Int("empId", empInt).Msg("Error reverting employee restore")
+		}
+	}
+	prev, err := eh.addVersions(ctx, empInt, eh.newVersion(models.AuditRestore, emp, eh.today()))
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Int("empId", empInt).Msg("Error recording employee version")
+		undo()
+		return GetEmpError(ErrorRecordingVersion)
+	}
+	if err := eh.record(ctx, empInt, models.AuditRestore, nil, emp); err != nil {
+		eh.restoreVersions(ctx, empInt, prev)
+		undo()
+		return err
+	}
+	eh.reindex(models.Employee{}, emp)
+	logger.Ctx(ctx).Debug().Str("empId", empID).Msg("Request processed successfully")
+	return nil
+}
+
+// PurgeDeleted removes for good the employees deleted more than retention ago,
+// together with their salary history, freeing their IDs. Their versions are kept for
+// reads as of earlier dates, and a reused ID starts a new incarnation of them.
+//
+// It returns the number of employees purged.
+func (eh *Employee) PurgeDeleted(ctx context.Context, retention time.Duration) (purged int, err error) {
+	ctx, span := tracing.Start(ctx, "employee.PurgeDeleted")
+	defer func() { span.SetError(err); span.End() }()
+
+	cutoff := eh.now().Add(-retention)
+	expired := func(emp models.Employee) bool {
+		return emp.DeletedAt != nil && !emp.DeletedAt.After(cutoff)
+	}
+
+	var ids []int
+	for last := 0; ; {
+		emps, next, err := eh.db.GetItems(ctx, last, scanPageSize)
+		if err != nil {
+			logger.Ctx(ctx).Error().Err(err).Msg("Error reading employees to purge")
+			return 0, GetEmpError(ErrorPurgingEmp)
+		}
+		for _, emp := range emps {
+			if expired(emp) {
+				ids = append(ids, emp.ID)
+			}
+		}
+		if next == 0 {
+			break
+		}
+		last = next
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	// Restores take the index lock, hold it so that none races with the purge
+	unlock, err := eh.lockIndex(ctx)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error indexing employees")
+		return 0, GetEmpError(ErrorPurgingEmp)
+	}
+	defer unlock()
+
+	for _, id := range ids {
+		emp, ok := eh.db.GetItem(ctx, id)
+		if !ok || !expired(emp) {
+			continue
+		}
+		history, hasHistory := eh.salaries.GetItem(ctx, id)
+		versions, hasVersions := eh.versions.GetItem(ctx, id)
+		if err := eh.db.DeleteItem(ctx, id); err != nil {
+			logger.Ctx(ctx).Error().Err(err).Int("empId", id).Msg("Error purging employee")
+			return purged, GetEmpError(ErrorPurgingEmp)
+		}
+		if err := eh.salaries.DeleteItem(ctx, id); err != nil && !errors.Is(err, simpledb.KeyAbsent) {
+			logger.Ctx(ctx).Error().Err(err).Int("empId", id).Msg("Error purging salary history")
+		}
+		if hasVersions {
+			next := versions
+			next.Purges++
+			if err := eh.versions.UpdateItem(ctx, id, next); err != nil {
+				logger.Ctx(ctx).Error().Err(err).Int("empId", id).Msg("Error ending employee versions")
+			}
+		}
+		// A purge that cannot be audited is undone, the next run retries it. The entry
+		// only names the employee, the trail is never purged
+		if err := eh.record(ctx, id, models.AuditPurge, models.Employee{ID: id}, nil); err != nil {
+			if err := eh.db.SetItem(ctx, id, emp); err != nil {
+				logger.Ctx(ctx).Error().Err(err).Int("empId", id).Msg("Error reverting employee purge")
+			}
+			if hasHistory {
+				if err := eh.salaries.SetItem(ctx, id, history); err != nil {
+					logger.Ctx(ctx).Error().Err(err).Int("empId", id).Msg("Error reverting salary history purge")
+				}
+			}
+			if hasVersions {
+				if err := eh.versions.UpdateItem(ctx, id, versions); err != nil {
+					logger.Ctx(ctx).Error().Err(err).Int("empId", id).Msg("Error reverting employee versions purge")
+				}
+			}
+			return purged, err
+		}
+		purged++
+	}
+	logger.Ctx(ctx).Info().Int("employees", purged).Dur("retention", retention).Msg("Purged deleted employees")
+	return purged, nil
+}
+
+// RunPurge calls PurgeDeleted every interval until ctx is done.
+func (eh *Employee) RunPurge(ctx context.Context, retention, interval time.Duration) {
+	ctx = auth.WithPrincipal(ctx, &auth.Principal{Subject: PurgeActor})
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := eh.PurgeDeleted(ctx, retention); err != nil {
+			logger.Ctx(ctx).Error().Err(err).Msg("Failed to purge deleted employees")
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package employee
+
+import (
+	"context"
+	"employee/logic/audit"
+	"employee/models"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"employee/pkg/tracing"
+	"employee/service/simpledb"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxReasonLength is the longest reason accepted for a salary change.
+	maxReasonLength = 200
+	// legacySalaryReason marks the salary of an employee stored before salary
+	// histories were kept, recorded when it is first changed.
+	legacySalaryReason = "salary before history was recorded"
+	// positionChangeReason marks the salary restated when a position change overrides
+	// the band of the new position.
+	positionChangeReason = "position change"
+)
+
+// SetSalaryDB keeps the salary histories in db instead of a new in-memory database.
+func (eh *Employee) SetSalaryDB(db *simpledb.Database[int, models.SalaryHistory]) {
+	eh.salaries = db
+}
+
+// SetClock sets the function returning the current time, which decides the salary in effect.
+func (eh *Employee) SetClock(now func() time.Time) {
+	eh.now = now
+}
+
+// today returns the current date in YYYY-MM-DD form.
+func (eh *Employee) today() string {
+	return eh.now().UTC().Format(time.DateOnly)
+}
+
+// parseDate returns date in canonical YYYY-MM-DD form, or false if it is not a valid date.
+func parseDate(date string) (string, bool) {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return "", false
+	}
+	return t.Format(time.DateOnly), true
+}
+
+// salaryAsOf returns the salary change in effect on date, or false if none has taken
+// effect yet.
+func salaryAsOf(changes []models.SalaryChange, date string) (models.SalaryChange, bool) {
+	for i := len(changes) - 1; i >= 0; i-- {
+		if changes[i].EffectiveDate <= date {
+			return changes[i], true
+		}
+	}
+	return models.SalaryChange{}, false
+}
+
+// newSalaryChange returns a change recorded now by the caller of ctx.
+func (eh *Employee) newSalaryChange(ctx context.Context, salary models.Money, currency, effectiveDate, reason string) models.SalaryChange {
+	actor := audit.AnonymousActor
+	if p, ok := auth.PrincipalFromContext(ctx); ok {
+		actor = p.Subject
+	}
+	return models.SalaryChange{
+		Salary:        salary,
+		Currency:      currency,
+		EffectiveDate: effectiveDate,
+		Reason:        reason,
+		RecordedBy:    actor,
+		RecordedAt:    eh.now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+// addSalaryChange inserts change into the history of emp after every change effective
+// on or before the same date. It returns the history before and after the insert.
+//
+// Employees stored before salary histories were kept have none; their current salary
+// is recorded first, effective today.
+func (eh *Employee) addSalaryChange(ctx context.Context, emp models.Employee, change models.SalaryChange) (before, after models.SalaryHistory, err error) {
+	id := emp.ID
+	before, present := eh.salaries.GetItem(ctx, id)
+	// Copy, the stored slice must not be modified in place
+	after.Changes = slices.Clone(before.Changes)
+	if !present && emp.Salary != 0 {
+		after.Changes = append(after.Changes, eh.newSalaryChange(ctx, emp.Salary, emp.Currency, eh.today(), legacySalaryReason))
+	}
+	i := len(after.Changes)
+	for i > 0 && after.Changes[i-1].EffectiveDate > change.EffectiveDate {
+		i--
+	}
+	after.Changes = slices.Insert(after.Changes, i, change)
+	if present {
+		err = eh.salaries.UpdateItem(ctx, id, after)
+	} else {
+		err = eh.salaries.SetItem(ctx, id, after)
+	}
+	return before, after, err
+}
+
+// applySalaries replaces the salary and currency of each employee with those in effect
+// on date. Employees stored before salary histories were kept have none and keep their
+// salary.
+func (eh *Employee) applySalaries(ctx context.Context, emps []models.Employee, date string) {
+	for i := range emps {
+		history, ok := eh.salaries.GetItem(ctx, emps[i].ID)
+		if !ok || len(history.Changes) == 0 {
+			continue
+		}
+		change, _ := salaryAsOf(history.Changes, date)
+		emps[i].Salary = change.Salary
+		// Changes recorded before currencies were are in the currency of the employee
+		if change.Currency != "" {
+			emps[i].Currency = change.Currency
+		}
+	}
+}
+
+// GetSalaryHistory returns every salary change of an employee, past and scheduled,
+// ordered by effective date.
+//
+// It returns an error if the ID is invalid or the employee does not exist.
+func (eh *Employee) GetSalaryHistory(ctx context.Context, empID string) (res models.GetSalaryHistoryResponse, err error) {
+	ctx, span := tracing.Start(ctx, "employee.GetSalaryHistory")
+	defer func() { span.SetError(err); span.End() }()
+	span.SetAttribute("employee.id", empID)
+
+	logger.Ctx(ctx).Debug().Str("empId", empID).Msg("Salary history request received")
+
+	empInt, err := strconv.Atoi(empID)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("empId", empID).Msg("Invalid employee ID")
+		return res, GetEmpError(InvalidID)
+	}
+	if _, ok := eh.getItem(ctx, empInt); !ok {
+		logger.Ctx(ctx).Error().Int("empId", empInt).Msg("Employee not found")
+		return res, GetEmpError(InvalidID)
+	}
+
+	history, _ := eh.salaries.GetItem(ctx, empInt)
+	res.EmployeeID = empInt
+	res.Changes = slices.Clone(history.Changes)
+	if res.Changes == nil {
+		res.Changes = []models.SalaryChange{}
+	}
+	logger.Ctx(ctx).Debug().Str("empId", empID).Int("changes", len(res.Changes)).Msg("Request processed successfully")
+	return res, nil
+}
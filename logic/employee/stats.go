@@ -0,0 +1,212 @@
+package employee
+
+import (
+	"context"
+	"employee/models"
+	"employee/pkg/currency"
+	"employee/pkg/logger"
+	"employee/pkg/tracing"
+	"math/big"
+	"regexp"
+	"slices"
+	"sort"
+)
+
+const (
+	GroupByPosition   = "position"
+	GroupByDepartment = "department"
+)
+
+// defaultPercentiles are computed when a stats request lists none.
+var defaultPercentiles = []string{"25", "75", "90"}
+
+// percentilePattern matches the percentiles accepted, decimals up to 100.
+var percentilePattern = regexp.MustCompile(`^[0-9]{1,3}(\.[0-9]+)?$`)
+
+// distribution accumulates the salaries of a group. Salaries are counted by value
+// rather than kept one by one: a roster repeats the same salaries, so the counts stay
+// much smaller than the employees they stand for.
+type distribution struct {
+	count int
+	// sum is the exact sum of the converted salaries.
+	sum *big.Rat
+	// counts holds the number of employees paid each salary, rounded to the minor
+	// unit of the currency of the stats.
+	counts map[models.Money]int
+}
+
+func newDistribution() *distribution {
+	return &distribution{sum: new(big.Rat), counts: map[models.Money]int{}}
+}
+
+func (d *distribution) add(salary *big.Rat, code string) {
+	d.count++
+	d.sum.Add(d.sum, salary)
+	rounded, _ := models.MoneyFromRat(currency.Round(salary, code))
+	d.counts[rounded]++
+}
+
+// nth returns the salary of rank n, from 0, among values, the salaries of d in
+// increasing order.
+func (d *distribution) nth(values []models.Money, n int) models.Money {
+	for _, v := range values {
+		if n < d.counts[v] {
+			return v
+		}
+		n -= d.counts[v]
+	}
+	return values[len(values)-1]
+}
+
+// quantile returns the salary a fraction q of the others are below, interpolated
+// linearly between the two closest ranks. d must not be empty.
+func (d *distribution) quantile(values []models.Money, q *big.Rat) *big.Rat {
+	rank := new(big.Rat).Mul(q, big.NewRat(int64(d.count-1), 1))
+	lower := new(big.Int).Quo(rank.Num(), rank.Denom())
+	low := d.nth(values, int(lower.Int64())).Rat()
+	fraction := rank.Sub(rank, new(big.Rat).SetInt(lower))
+	if fraction.Sign() == 0 {
+		return low
+	}
+	high := d.nth(values, int(lower.Int64())+1).Rat()
+	step := high.Sub(high, low)
+	return low.Add(low, step.Mul(step, fraction))
+}
+
+// stats returns the statistics of d, in code. quantiles are the fractions of
+// percentiles.
+func (d *distribution) stats(group, code string, percentiles []string, quantiles []*big.Rat) models.SalaryStats {
+	s := models.SalaryStats{Group: group, Count: d.count, Currency: code}
+	if d.count == 0 {
+		return s
+	}
+	round := func(r *big.Rat) models.Money {
+		m, _ := models.MoneyFromRat(currency.Round(r, code))
+		return m
+	}
+	values := make([]models.Money, 0, len(d.counts))
+	for v := range d.counts {
+		values = append(values, v)
+	}
+	slices.Sort(values)
+
+	s.Sum = round(d.sum)
+	s.Mean = round(new(big.Rat).Quo(d.sum, big.NewRat(int64(d.count), 1)))
+	s.Median = round(d.quantile(values, big.NewRat(1, 2)))
+	s.Percentiles = make(map[string]models.Money, len(percentiles))
+	for i, p := range percentiles {
+		s.Percentiles["p"+p] = round(d.quantile(values, quantiles[i]))
+	}
+	return s
+}
+
+// matchesStats reports whether emp is selected by the filters of req.
+func matchesStats(emp models.Employee, req models.EmployeeStatsRequest) bool {
+	status := emp.Status
+	// Employees stored before statuses were are active
+	if status == "" {
+		status = models.StatusActive
+	}
+	return emp.DeletedAt == nil &&
+		(req.Position == "" || emp.Position == req.Position) &&
+		(req.Department == "" || emp.Department == req.Department) &&
+		(req.Status == "" || status == req.Status)
+}
+
+// GetEmployeeStats returns the count, sum, mean, median and percentiles of the
+// salaries in effect today of the employees matching the filters of req, converted
+// into the currency of req at today's rates and grouped by position or department.
+//
+// The employees are read a page at a time and only their salaries are kept, so the
+// roster is never loaded whole.
+func (eh *Employee) GetEmployeeStats(ctx context.Context, req models.EmployeeStatsRequest) (res models.GetEmployeeStatsResponse, err error) {
+	ctx, span := tracing.Start(ctx, "employee.GetEmployeeStats")
+	defer func() { span.SetError(err); span.End() }()
+
+	logger.Ctx(ctx).Debug().Str("groupBy", req.GroupBy).Str("position", req.Position).Str("department", req.Department).
+		Str("status", req.Status).Str("currency", req.Currency).Strs("percentiles", req.Percentiles).
+		Msg("Employee stats request received")
+
+	if req.GroupBy != "" && req.GroupBy != GroupByPosition && req.GroupBy != GroupByDepartment {
+		logger.Ctx(ctx).Error().Str("groupBy", req.GroupBy).Msg("Invalid stats grouping")
+		return res, GetEmpError(InvalidStatsQuery)
+	}
+	switch req.Status {
+	case "", models.StatusActive, models.StatusOnLeave, models.StatusTerminated:
+	default:
+		logger.Ctx(ctx).Error().Str("status", req.Status).Msg("Invalid employee status")
+		return res, GetEmpError(InvalidStatus)
+	}
+	if req.Currency == "" {
+		req.Currency = eh.defaultCurrency
+	}
+	if err := checkCurrency(ctx, req.Currency); err != nil {
+		return res, err
+	}
+	if len(req.Percentiles) == 0 {
+		req.Percentiles = defaultPercentiles
+	}
+	quantiles := make([]*big.Rat, len(req.Percentiles))
+	for i, p := range req.Percentiles {
+		q, ok := new(big.Rat).SetString(p)
+		if !percentilePattern.MatchString(p) || !ok || q.Cmp(big.NewRat(100, 1)) > 0 {
+			logger.Ctx(ctx).Error().Str("percentile", p).Msg("Invalid percentile")
+			return res, GetEmpError(InvalidStatsQuery)
+		}
+		quantiles[i] = q.Quo(q, big.NewRat(100, 1))
+	}
+
+	today := eh.today()
+	groups := map[string]*distribution{}
+	if req.GroupBy == "" {
+		groups[""] = newDistribution()
+	}
+	for last := 0; ; {
+		emps, next, err := eh.db.GetItems(ctx, last, scanPageSize)
+		if err != nil {
+			logger.Ctx(ctx).Error().Err(err).Msg("Error getting employees")
+			return res, GetEmpError(ErrorGettingStats)
+		}
+		var matched []models.Employee
+		for _, emp := range emps {
+			if matchesStats(emp, req) {
+				matched = append(matched, emp)
+			}
+		}
+		// The stored salary misses the scheduled changes that took effect since the
+		// last write
+		eh.applySalaries(ctx, matched, today)
+		for _, emp := range matched {
+			salary, err := eh.convert(ctx, emp.Salary.Rat(), eh.currencyOf(emp), req.Currency, today)
+			if err != nil {
+				return res, err
+			}
+			var group string
+			switch req.GroupBy {
+			case GroupByPosition:
+				group = emp.Position
+			case GroupByDepartment:
+				group = emp.Department
+			}
+			d := groups[group]
+			if d == nil {
+				d = newDistribution()
+				groups[group] = d
+			}
+			d.add(salary, req.Currency)
+		}
+		if next == 0 {
+			break
+		}
+		last = next
+	}
+
+	res.GroupBy = req.GroupBy
+	res.Stats = make([]models.SalaryStats, 0, len(groups))
+	for group, d := range groups {
+		res.Stats = append(res.Stats, d.stats(group, req.Currency, req.Percentiles, quantiles))
+	}
+	sort.Slice(res.Stats, func(i, j int) bool { return res.Stats[i].Group < res.Stats[j].Group })
+	logger.Ctx(ctx).Debug().Int("groups", len(res.Stats)).Msg("Request processed successfully")
+	return res, nil
+}
@@ -0,0 +1,147 @@
+package employee_test
+
+import (
+	"context"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/pkg/auth"
+	"employee/pkg/currency"
+	"employee/service/simpledb"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Currencies", func() {
+	var (
+		eh  *employee.Employee
+		db  *simpledb.Database[int, models.Employee]
+		now time.Time
+		ctx context.Context
+	)
+
+	get := func(id, asOf string) models.Employee {
+		res, err := eh.GetEmployeeAsOf(ctx, id, "", "", asOf, "")
+		Expect(err).To(BeNil())
+		return res.Employees[0]
+	}
+
+	BeforeEach(func() {
+		var d simpledb.Database[int, models.Employee]
+		db = d.Init()
+		eh = employee.NewEmployeeWithDB(db)
+		now = time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+		eh.SetClock(func() time.Time { return now })
+		rates, err := currency.NewTable([]currency.Rate{
+			{From: "EUR", To: "USD", Rate: "1.10", EffectiveDate: "2026-01-01"},
+			{From: "EUR", To: "USD", Rate: "1.20", EffectiveDate: "2026-04-01"},
+			{From: "GBP", To: "USD", Rate: "1.25", EffectiveDate: "2026-01-01"},
+		})
+		Expect(err).To(BeNil())
+		eh.SetCurrencies("USD", rates)
+		ctx = auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "hr"})
+		Expect(eh.CreateDepartment(ctx, models.Department{ID: "eng", Name: "Engineering"})).To(BeNil())
+	})
+
+	It("stores the currency of every salary, the default one when none is given", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100)})).To(BeNil())
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 2, Name: "Alan", Position: "Engineer", Salary: models.MoneyOf(100), Currency: "EUR"})).To(BeNil())
+		Expect(get("1", "").Currency).To(Equal("USD"))
+		Expect(get("2", "").Currency).To(Equal("EUR"))
+
+		res, err := eh.GetSalaryHistory(ctx, "2")
+		Expect(err).To(BeNil())
+		Expect(res.Changes[0].Currency).To(Equal("EUR"))
+	})
+
+	It("rejects unknown currencies and currency changes without a salary", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100), Currency: "usd"})).
+			To(Equal(employee.GetEmpError(employee.InvalidCurrency)))
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100)})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Currency: "EUR"})).
+			To(Equal(employee.GetEmpError(employee.InvalidCurrency)))
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(90), Currency: "XXX"})).
+			To(Equal(employee.GetEmpError(employee.InvalidCurrency)))
+	})
+
+	It("rejects salaries and bands finer than the minor unit of their currency", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MustParseMoney("100.5"), Currency: "JPY"})).
+			To(Equal(employee.GetEmpError(employee.InvalidSalary)))
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MustParseMoney("100.005")})).
+			To(Equal(employee.GetEmpError(employee.InvalidSalary)))
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MustParseMoney("100.005"), Currency: "KWD"})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MustParseMoney("0.0001")})).
+			To(Equal(employee.GetEmpError(employee.InvalidSalary)))
+		Expect(eh.CreatePosition(ctx, models.Position{Title: "Engineer", MinSalary: models.MustParseMoney("99.5"), MidSalary: models.MoneyOf(150), MaxSalary: models.MoneyOf(200), Currency: "JPY"})).
+			To(Equal(employee.GetEmpError(employee.InvalidPositionBand)))
+	})
+
+	It("changes the currency on the date the salary in it takes effect", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100)})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(95), Currency: "EUR", EffectiveDate: "2026-04-01"})).To(BeNil())
+
+		Expect(get("1", "")).To(HaveField("Currency", "USD"))
+		Expect(get("1", "2026-04-01")).To(And(HaveField("Salary", models.MoneyOf(95)), HaveField("Currency", "EUR")))
+
+		// A later raise without a currency stays in the currency paid today
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(110)})).To(BeNil())
+		Expect(get("1", "")).To(And(HaveField("Salary", models.MoneyOf(110)), HaveField("Currency", "USD")))
+	})
+
+	It("checks salaries against bands converted at today's rate", func() {
+		Expect(eh.CreatePosition(ctx, models.Position{Title: "Engineer", MinSalary: models.MoneyOf(100), MidSalary: models.MoneyOf(150), MaxSalary: models.MoneyOf(200)})).To(BeNil())
+		Expect(eh.CreatePosition(ctx, models.Position{Title: "Lead", MinSalary: models.MoneyOf(150), MidSalary: models.MoneyOf(200), MaxSalary: models.MoneyOf(250), Currency: "JPY"})).To(BeNil())
+
+		// 90 EUR is 99 USD, 100 EUR is 110 USD
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(90), Currency: "EUR"})).
+			To(Equal(employee.GetEmpError(employee.SalaryOutsideBand)))
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100), Currency: "EUR"})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Position: "Lead"})).
+			To(Equal(employee.GetEmpError(employee.ExchangeRateNotFound)))
+	})
+
+	It("converts the rollups into the reporting currency", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100), Department: "eng"})).To(BeNil())
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 2, Name: "Alan", Position: "Engineer", Salary: models.MustParseMoney("100.1"), Currency: "EUR", Department: "eng"})).To(BeNil())
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 3, Name: "Grace", Position: "Engineer", Salary: models.MoneyOf(80), Currency: "GBP", Department: "eng"})).To(BeNil())
+
+		res, err := eh.GetDepartmentRollups(ctx, "eng", "")
+		Expect(err).To(BeNil())
+		// 100 + 110.11 + 100
+		Expect(res.Rollups).To(Equal([]models.DepartmentRollup{
+			{Department: "eng", Headcount: 3, TotalSalary: models.MustParseMoney("310.11"), AverageSalary: models.MustParseMoney("103.37"), Currency: "USD"},
+		}))
+
+		res, err = eh.GetDepartmentRollups(ctx, "eng", "EUR")
+		Expect(err).To(BeNil())
+		// 90.909... + 100.1 + 90.909...
+		Expect(res.Rollups[0]).To(And(HaveField("TotalSalary", models.MustParseMoney("281.92")), HaveField("AverageSalary", models.MustParseMoney("93.97"))))
+
+		now = time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+		res, err = eh.GetDepartmentRollups(ctx, "eng", "")
+		Expect(err).To(BeNil())
+		Expect(res.Rollups[0].TotalSalary).To(Equal(models.MustParseMoney("320.12")))
+
+		_, err = eh.GetDepartmentRollups(ctx, "eng", "JPY")
+		Expect(err).To(Equal(employee.GetEmpError(employee.ExchangeRateNotFound)))
+		_, err = eh.GetDepartmentRollups(ctx, "eng", "Euro")
+		Expect(err).To(Equal(employee.GetEmpError(employee.InvalidCurrency)))
+	})
+
+	It("reads employees stored before currencies were as paid in the default currency", func() {
+		Expect(db.SetItem(ctx, 7, models.Employee{ID: 7, Name: "Linus", Position: "Engineer", Salary: models.MoneyOf(90), Department: "eng"})).To(BeNil())
+		other := employee.NewEmployeeWithDB(db)
+		other.SetCurrencies("EUR", nil)
+		Expect(other.CreateDepartment(ctx, models.Department{ID: "eng", Name: "Engineering"})).To(BeNil())
+
+		res, err := other.GetDepartmentRollups(ctx, "eng", "")
+		Expect(err).To(BeNil())
+		Expect(res.Rollups).To(Equal([]models.DepartmentRollup{{Department: "eng", Headcount: 1, TotalSalary: models.MoneyOf(90), AverageSalary: models.MoneyOf(90), Currency: "EUR"}}))
+
+		Expect(other.UpdateEmployee(ctx, "7", models.EmployeeUpdateRequest{Salary: models.MoneyOf(95)})).To(BeNil())
+		history, err := other.GetSalaryHistory(ctx, "7")
+		Expect(err).To(BeNil())
+		Expect(history.Changes[1]).To(And(HaveField("Salary", models.MoneyOf(95)), HaveField("Currency", "EUR")))
+	})
+})
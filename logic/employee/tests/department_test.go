@@ -0,0 +1,129 @@
+package employee_test
+
+import (
+	"context"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/service/simpledb"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Departments", func() {
+	var (
+		eh  *employee.Employee
+		db  *simpledb.Database[int, models.Employee]
+		ctx = context.Background()
+	)
+
+	rollup := func(id string) models.DepartmentRollup {
+		res, err := eh.GetDepartmentRollups(ctx, id, "")
+		Expect(err).To(BeNil())
+		Expect(res.Rollups).To(HaveLen(1))
+		return res.Rollups[0]
+	}
+
+	BeforeEach(func() {
+		var d simpledb.Database[int, models.Employee]
+		db = d.Init()
+		eh = employee.NewEmployeeWithDB(db)
+		Expect(eh.CreateDepartment(ctx, models.Department{ID: "eng", Name: "Engineering"})).To(BeNil())
+		Expect(eh.CreateDepartment(ctx, models.Department{ID: "ops", Name: "Operations"})).To(BeNil())
+	})
+
+	It("creates, lists, renames and deletes departments", func() {
+		Expect(eh.CreateDepartment(ctx, models.Department{ID: "eng", Name: "Engineering"})).
+			To(Equal(employee.GetEmpError(employee.DepartmentAlreadyExists)))
+		Expect(eh.CreateDepartment(ctx, models.Department{ID: "hr"})).
+			To(Equal(employee.GetEmpError(employee.InvalidDepartmentName)))
+		Expect(eh.CreateDepartment(ctx, models.Department{Name: "Human resources"})).
+			To(Equal(employee.GetEmpError(employee.InvalidID)))
+		Expect(eh.CreateDepartment(ctx, models.Department{ID: "rollups", Name: "Rollups"})).
+			To(Equal(employee.GetEmpError(employee.InvalidID)))
+
+		res, err := eh.GetDepartments(ctx, "", "", "1")
+		Expect(err).To(BeNil())
+		Expect(res.Departments).To(Equal([]models.Department{{ID: "eng", Name: "Engineering"}}))
+		res, err = eh.GetDepartments(ctx, "", res.LastEvalKeyID, "10")
+		Expect(err).To(BeNil())
+		Expect(res.Departments).To(Equal([]models.Department{{ID: "ops", Name: "Operations"}}))
+		Expect(res.LastEvalKeyID).To(BeEmpty())
+
+		Expect(eh.UpdateDepartment(ctx, "ops", models.DepartmentUpdateRequest{Name: "Site reliability"})).To(BeNil())
+		res, err = eh.GetDepartments(ctx, "ops", "", "")
+		Expect(err).To(BeNil())
+		Expect(res.Departments[0].Name).To(Equal("Site reliability"))
+		Expect(eh.UpdateDepartment(ctx, "hr", models.DepartmentUpdateRequest{Name: "Human resources"})).
+			To(Equal(employee.GetEmpError(employee.DepartmentNotFound)))
+
+		Expect(eh.DeleteDepartment(ctx, "ops")).To(BeNil())
+		_, err = eh.GetDepartments(ctx, "ops", "", "")
+		Expect(err).To(Equal(employee.GetEmpError(employee.DepartmentNotFound)))
+		Expect(eh.DeleteDepartment(ctx, "ops")).To(Equal(employee.GetEmpError(employee.DepartmentNotFound)))
+	})
+
+	It("only assigns employees to existing departments", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100), Department: "hr"})).
+			To(Equal(employee.GetEmpError(employee.DepartmentNotFound)))
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100), Department: "eng"})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Department: "hr"})).
+			To(Equal(employee.GetEmpError(employee.DepartmentNotFound)))
+	})
+
+	It("refuses to delete a department with employees", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100), Department: "eng"})).To(BeNil())
+		Expect(eh.DeleteDepartment(ctx, "eng")).To(Equal(employee.GetEmpError(employee.DepartmentHasEmployees)))
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Department: "ops"})).To(BeNil())
+		Expect(eh.DeleteDepartment(ctx, "eng")).To(BeNil())
+	})
+
+	It("keeps the rollups up to date on every write", func() {
+		Expect(rollup("eng")).To(Equal(models.DepartmentRollup{Department: "eng", Currency: "USD"}))
+
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100), Department: "eng"})).To(BeNil())
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 2, Name: "Alan", Position: "Engineer", Salary: models.MoneyOf(200), Department: "eng"})).To(BeNil())
+		Expect(rollup("eng")).To(Equal(models.DepartmentRollup{Department: "eng", Headcount: 2, TotalSalary: models.MoneyOf(300), AverageSalary: models.MoneyOf(150), Currency: "USD"}))
+
+		Expect(eh.UpdateEmployee(ctx, "2", models.EmployeeUpdateRequest{Salary: models.MoneyOf(400)})).To(BeNil())
+		Expect(rollup("eng")).To(Equal(models.DepartmentRollup{Department: "eng", Headcount: 2, TotalSalary: models.MoneyOf(500), AverageSalary: models.MoneyOf(250), Currency: "USD"}))
+
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Department: "ops"})).To(BeNil())
+		Expect(rollup("eng")).To(Equal(models.DepartmentRollup{Department: "eng", Headcount: 1, TotalSalary: models.MoneyOf(400), AverageSalary: models.MoneyOf(400), Currency: "USD"}))
+		Expect(rollup("ops")).To(Equal(models.DepartmentRollup{Department: "ops", Headcount: 1, TotalSalary: models.MoneyOf(100), AverageSalary: models.MoneyOf(100), Currency: "USD"}))
+
+		Expect(eh.DeleteEmployee(ctx, "2")).To(BeNil())
+		Expect(rollup("eng")).To(Equal(models.DepartmentRollup{Department: "eng", Currency: "USD"}))
+	})
+
+	It("counts scheduled salary changes once they take effect", func() {
+		now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+		eh.SetClock(func() time.Time { return now })
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100), Department: "eng"})).To(BeNil())
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 2, Name: "Alan", Position: "Engineer", Salary: models.MoneyOf(200), Department: "eng"})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(150), EffectiveDate: "2026-04-01"})).To(BeNil())
+		Expect(rollup("eng").TotalSalary).To(Equal(models.MoneyOf(300)))
+
+		now = time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+		Expect(rollup("eng")).To(Equal(models.DepartmentRollup{Department: "eng", Headcount: 2, TotalSalary: models.MoneyOf(350), AverageSalary: models.MoneyOf(175), Currency: "USD"}))
+
+		// The stored salary is still the one before the change, the raise moves with Ada
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Department: "ops"})).To(BeNil())
+		Expect(rollup("eng").TotalSalary).To(Equal(models.MoneyOf(200)))
+		Expect(rollup("ops").TotalSalary).To(Equal(models.MoneyOf(150)))
+	})
+
+	It("includes departments assigned before departments were managed", func() {
+		Expect(db.SetItem(ctx, 7, models.Employee{ID: 7, Name: "Linus", Position: "Engineer", Salary: models.MoneyOf(90), Department: "Kernel"})).To(BeNil())
+		other := employee.NewEmployeeWithDB(db)
+		Expect(other.CreateDepartment(ctx, models.Department{ID: "eng", Name: "Engineering"})).To(BeNil())
+
+		res, err := other.GetDepartmentRollups(ctx, "", "")
+		Expect(err).To(BeNil())
+		Expect(res.Rollups).To(Equal([]models.DepartmentRollup{
+			{Department: "Kernel", Headcount: 1, TotalSalary: models.MoneyOf(90), AverageSalary: models.MoneyOf(90), Currency: "USD"},
+			{Department: "eng", Currency: "USD"},
+		}))
+	})
+})
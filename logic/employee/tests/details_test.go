@@ -0,0 +1,122 @@
+package employee_test
+
+import (
+	"context"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/service/simpledb"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Employee details", func() {
+	var (
+		eh  *employee.Employee
+		db  *simpledb.Database[int, models.Employee]
+		ctx = context.Background()
+	)
+
+	valid := func() models.Employee {
+		return models.Employee{
+			ID: 2, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100), Currency: "USD",
+			Email: "ada@example.com", Department: "R&D", ManagerID: 1,
+			HireDate: "2024-02-01", Status: models.StatusActive,
+		}
+	}
+
+	get := func(id string) models.Employee {
+		res, err := eh.GetEmployee(ctx, id, "", "")
+		Expect(err).To(BeNil())
+		return res.Employees[0]
+	}
+
+	BeforeEach(func() {
+		var d simpledb.Database[int, models.Employee]
+		db = d.Init()
+		eh = employee.NewEmployeeWithDB(db)
+		Expect(eh.CreateDepartment(ctx, models.Department{ID: "R&D", Name: "Research and development"})).To(BeNil())
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Grace", Position: "Director", Salary: models.MoneyOf(200), Email: "grace@example.com"})).To(BeNil())
+	})
+
+	It("stores every field and makes new employees active by default", func() {
+		Expect(eh.CreateEmployee(ctx, valid())).To(BeNil())
+		Expect(get("2")).To(Equal(valid()))
+		Expect(get("1").Status).To(Equal(models.StatusActive))
+	})
+
+	DescribeTable("rejects invalid details on create",
+		func(change func(*models.Employee), code employee.EmpError) {
+			emp := valid()
+			change(&emp)
+			Expect(eh.CreateEmployee(ctx, emp)).To(Equal(employee.GetEmpError(code)))
+		},
+		Entry("malformed email", func(e *models.Employee) { e.Email = "ada.example.com" }, employee.InvalidEmail),
+		Entry("email with a display name", func(e *models.Employee) { e.Email = "Ada <ada@example.com>" }, employee.InvalidEmail),
+		Entry("overlong email", func(e *models.Employee) { e.Email = strings.Repeat("a", 250) + "@example.com" }, employee.InvalidEmail),
+		Entry("overlong department", func(e *models.Employee) { e.Department = strings.Repeat("d", 101) }, employee.InvalidDepartment),
+		Entry("negative manager", func(e *models.Employee) { e.ManagerID = -1 }, employee.InvalidManagerID),
+		Entry("own manager", func(e *models.Employee) { e.ManagerID = 2 }, employee.InvalidManagerID),
+		Entry("malformed hire date", func(e *models.Employee) { e.HireDate = "2024-02-30" }, employee.InvalidHireDate),
+		Entry("termination before hire", func(e *models.Employee) { e.TerminationDate = "2024-01-31" }, employee.InvalidTerminationDate),
+		Entry("unknown status", func(e *models.Employee) { e.Status = "retired" }, employee.InvalidStatus),
+		Entry("terminated without a date", func(e *models.Employee) { e.Status = models.StatusTerminated }, employee.InvalidStatus),
+	)
+
+	It("keeps emails unique regardless of case", func() {
+		emp := valid()
+		emp.Email = "Grace@Example.com"
+		Expect(eh.CreateEmployee(ctx, emp)).To(Equal(employee.GetEmpError(employee.EmailAlreadyExists)))
+
+		Expect(eh.CreateEmployee(ctx, valid())).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "2", models.EmployeeUpdateRequest{Email: "GRACE@example.com"})).
+			To(Equal(employee.GetEmpError(employee.EmailAlreadyExists)))
+		Expect(eh.UpdateEmployee(ctx, "2", models.EmployeeUpdateRequest{Email: "ada@example.com"})).To(BeNil())
+	})
+
+	It("frees the email of changed and deleted employees", func() {
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Email: "g.hopper@example.com"})).To(BeNil())
+		emp := valid()
+		emp.Email = "grace@example.com"
+		Expect(eh.CreateEmployee(ctx, emp)).To(BeNil())
+
+		Expect(eh.DeleteEmployee(ctx, "2")).To(BeNil())
+		emp.ID = 3
+		Expect(eh.CreateEmployee(ctx, emp)).To(BeNil())
+	})
+
+	It("indexes the emails of employees already stored", func() {
+		Expect(db.SetItem(ctx, 5, models.Employee{ID: 5, Name: "Linus", Position: "Engineer", Salary: models.MoneyOf(90), Email: "linus@example.com"})).To(BeNil())
+		eh = employee.NewEmployeeWithDB(db)
+		emp := valid()
+		emp.Email = "linus@example.com"
+		Expect(eh.CreateEmployee(ctx, emp)).To(Equal(employee.GetEmpError(employee.EmailAlreadyExists)))
+	})
+
+	It("updates and removes the manager", func() {
+		Expect(eh.CreateEmployee(ctx, valid())).To(BeNil())
+		none := 0
+		Expect(eh.UpdateEmployee(ctx, "2", models.EmployeeUpdateRequest{ManagerID: &none, Department: "Platform"})).
+			To(Equal(employee.GetEmpError(employee.DepartmentNotFound)))
+		Expect(eh.CreateDepartment(ctx, models.Department{ID: "Platform", Name: "Platform"})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "2", models.EmployeeUpdateRequest{ManagerID: &none, Department: "Platform"})).To(BeNil())
+		emp := get("2")
+		Expect(emp.ManagerID).To(BeZero())
+		Expect(emp.Department).To(Equal("Platform"))
+	})
+
+	It("terminates an employee with a termination date", func() {
+		Expect(eh.CreateEmployee(ctx, valid())).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "2", models.EmployeeUpdateRequest{Status: models.StatusTerminated})).
+			To(Equal(employee.GetEmpError(employee.InvalidStatus)))
+		Expect(eh.UpdateEmployee(ctx, "2", models.EmployeeUpdateRequest{Status: models.StatusTerminated, TerminationDate: "2026-06-30"})).To(BeNil())
+		Expect(get("2").Status).To(Equal(models.StatusTerminated))
+	})
+
+	It("makes employees stored without a status active on update", func() {
+		Expect(db.SetItem(ctx, 5, models.Employee{ID: 5, Name: "Linus", Position: "Engineer", Salary: models.MoneyOf(90)})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "5", models.EmployeeUpdateRequest{Position: "Maintainer"})).To(BeNil())
+		Expect(get("5").Status).To(Equal(models.StatusActive))
+	})
+})
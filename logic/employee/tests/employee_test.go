@@ -1,6 +1,7 @@
 package employee_test
 
 import (
+	"context"
 	"employee/logic/employee"
 	"employee/models"
 
@@ -24,11 +25,11 @@ var _ = Describe("Employee", func() {
 				ID:       1,
 				Name:     "John Doe",
 				Position: "Software Engineer",
-				Salary:   10000,
+				Salary:   models.MoneyOf(10000),
 			}
 
 			// when
-			err := eh.CreateEmployee(emp)
+			err := eh.CreateEmployee(context.Background(), emp)
 
 			// then
 			Expect(err).To(BeNil())
@@ -40,11 +41,11 @@ var _ = Describe("Employee", func() {
 				ID:       0,
 				Name:     "John Doe",
 				Position: "Software Engineer",
-				Salary:   10000,
+				Salary:   models.MoneyOf(10000),
 			}
 
 			// when
-			err := eh.CreateEmployee(emp)
+			err := eh.CreateEmployee(context.Background(), emp)
 
 			// then
 			Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
@@ -56,11 +57,11 @@ var _ = Describe("Employee", func() {
 				ID:       1,
 				Name:     "",
 				Position: "Software Engineer",
-				Salary:   10000,
+				Salary:   models.MoneyOf(10000),
 			}
 
 			// when
-			err := eh.CreateEmployee(emp)
+			err := eh.CreateEmployee(context.Background(), emp)
 
 			// then
 			Expect(err).To(Equal(employee.GetEmpError(employee.NameInvalid)))
@@ -72,11 +73,11 @@ var _ = Describe("Employee", func() {
 				ID:       1,
 				Name:     "ThisNameIsWayTooLongAndInvalidThisNameIsWayTooLongAndInvalidThisNameIsWayTooLongAndInvalidThisNameIsWayTooLongAndInvalid",
 				Position: "Software Engineer",
-				Salary:   10000,
+				Salary:   models.MoneyOf(10000),
 			}
 
 			// when
-			err := eh.CreateEmployee(emp)
+			err := eh.CreateEmployee(context.Background(), emp)
 
 			// then
 			Expect(err).To(Equal(employee.GetEmpError(employee.NameInvalid)))
@@ -88,11 +89,11 @@ var _ = Describe("Employee", func() {
 				ID:       1,
 				Name:     "John Doe",
 				Position: "",
-				Salary:   10000,
+				Salary:   models.MoneyOf(10000),
 			}
 
 			// when
-			err := eh.CreateEmployee(emp)
+			err := eh.CreateEmployee(context.Background(), emp)
 
 			// then
 			Expect(err).To(Equal(employee.GetEmpError(employee.InvalidPosition)))
@@ -104,11 +105,11 @@ var _ = Describe("Employee", func() {
 				ID:       1,
 				Name:     "John Doe",
 				Position: "ThisNameIsWayTooLongAndInvalidThisNameIsWayTooLongAndInvalidThisNameIsWayTooLongAndInvalidThisNameIsWayTooLongAndInvalid",
-				Salary:   10000,
+				Salary:   models.MoneyOf(10000),
 			}
 
 			// when
-			err := eh.CreateEmployee(emp)
+			err := eh.CreateEmployee(context.Background(), emp)
 
 			// then
 			Expect(err).To(Equal(employee.GetEmpError(employee.InvalidPosition)))
@@ -120,11 +121,11 @@ var _ = Describe("Employee", func() {
 				ID:       1,
 				Name:     "John Doe",
 				Position: "Software Engineer",
-				Salary:   0,
+				Salary:   models.MoneyOf(0),
 			}
 
 			// when
-			err := eh.CreateEmployee(emp)
+			err := eh.CreateEmployee(context.Background(), emp)
 
 			// then
 			Expect(err).To(Equal(employee.GetEmpError(employee.InvalidSalary)))
@@ -136,13 +137,13 @@ var _ = Describe("Employee", func() {
 				ID:       1,
 				Name:     "John Doe",
 				Position: "Software Engineer",
-				Salary:   10000,
+				Salary:   models.MoneyOf(10000),
 			}
 
 			// Call the CreateEmployee function
-			err := eh.CreateEmployee(emp)
+			err := eh.CreateEmployee(context.Background(), emp)
 			Expect(err).To(BeNil())
-			err = eh.CreateEmployee(emp)
+			err = eh.CreateEmployee(context.Background(), emp)
 			// Check if the function returned the correct error
 			Expect(err).To(Equal(employee.GetEmpError(employee.EmpAlreadyExists)))
 		})
@@ -153,11 +154,11 @@ var _ = Describe("Employee", func() {
 				ID:       1,
 				Name:     "John Doe",
 				Position: "Software Engineer",
-				Salary:   -1000,
+				Salary:   models.MoneyOf(-1000),
 			}
 
 			// Call the CreateEmployee function
-			err := eh.CreateEmployee(emp)
+			err := eh.CreateEmployee(context.Background(), emp)
 
 			// Check if the function returned the correct error
 			Expect(err).To(Equal(employee.GetEmpError(employee.InvalidSalary)))
@@ -173,13 +174,13 @@ var _ = Describe("Employee", func() {
 			numRecords := ""
 			expected := models.GetEmployeeResponse{
 				Employees: []models.Employee{
-					{ID: 1, Name: "John Doe", Position: "Developer", Salary: 50000},
+					{ID: 1, Name: "John Doe", Position: "Developer", Salary: models.MoneyOf(50000), Currency: "USD", Status: models.StatusActive},
 				},
 			}
-			eh.CreateEmployee(models.Employee{ID: 1, Name: "John Doe", Position: "Developer", Salary: 50000})
+			eh.CreateEmployee(context.Background(), models.Employee{ID: 1, Name: "John Doe", Position: "Developer", Salary: models.MoneyOf(50000), Currency: "USD", Status: models.StatusActive})
 
 			// when
-			res, err := eh.GetEmployee(empID, LastEvalKeyID, numRecords)
+			res, err := eh.GetEmployee(context.Background(), empID, LastEvalKeyID, numRecords)
 
 			// then
 			Expect(err).To(BeNil())
@@ -192,19 +193,19 @@ var _ = Describe("Employee", func() {
 			LastEvalKeyID := "2"
 			numRecords := "3"
 			emps := []models.Employee{
-				{ID: 2, Name: "Jane Doe", Position: "Manager", Salary: 80000},
-				{ID: 3, Name: "Bob Smith", Position: "Designer", Salary: 70000},
-				{ID: 4, Name: "Alice Johnson", Position: "Engineer", Salary: 60000},
+				{ID: 2, Name: "Jane Doe", Position: "Manager", Salary: models.MoneyOf(80000), Currency: "USD", Status: models.StatusActive},
+				{ID: 3, Name: "Bob Smith", Position: "Designer", Salary: models.MoneyOf(70000), Currency: "USD", Status: models.StatusActive},
+				{ID: 4, Name: "Alice Johnson", Position: "Engineer", Salary: models.MoneyOf(60000), Currency: "USD", Status: models.StatusActive},
 			}
 			expected := models.GetEmployeeResponse{
 				Employees: emps[1:],
 			}
 			for _, emp := range emps {
-				eh.CreateEmployee(emp)
+				eh.CreateEmployee(context.Background(), emp)
 			}
 
 			// when
-			res, err := eh.GetEmployee(empID, LastEvalKeyID, numRecords)
+			res, err := eh.GetEmployee(context.Background(), empID, LastEvalKeyID, numRecords)
 
 			// then
 			Expect(err).To(BeNil())
@@ -217,17 +218,17 @@ var _ = Describe("Employee", func() {
 			LastEvalKeyID := "4"
 			numRecords := "3"
 			emps := []models.Employee{
-				{ID: 4, Name: "Alice Johnson", Position: "Engineer", Salary: 60000},
+				{ID: 4, Name: "Alice Johnson", Position: "Engineer", Salary: models.MoneyOf(60000), Currency: "USD", Status: models.StatusActive},
 			}
 			expected := models.GetEmployeeResponse{
 				Employees: []models.Employee{},
 			}
 			for _, emp := range emps {
-				eh.CreateEmployee(emp)
+				eh.CreateEmployee(context.Background(), emp)
 			}
 
 			// when
-			res, err := eh.GetEmployee(empID, LastEvalKeyID, numRecords)
+			res, err := eh.GetEmployee(context.Background(), empID, LastEvalKeyID, numRecords)
 
 			// then
 			Expect(err).To(BeNil())
@@ -241,31 +242,47 @@ var _ = Describe("Employee", func() {
 			numRecords := ""
 			expected := models.GetEmployeeResponse{
 				Employees: []models.Employee{
-					{ID: 1, Name: "John Doe", Position: "Developer", Salary: 50000},
-					{ID: 2, Name: "Jane Doe", Position: "Manager", Salary: 80000},
-					{ID: 3, Name: "Bob Smith", Position: "Designer", Salary: 70000},
-					{ID: 4, Name: "Alice Johnson", Position: "Engineer", Salary: 60000},
-					{ID: 5, Name: "Mark Brown", Position: "Analyst", Salary: 55000},
-					{ID: 6, Name: "Sarah Lee", Position: "Architect", Salary: 75000},
-					{ID: 7, Name: "Chris Evans", Position: "Coordinator", Salary: 45000},
-					{ID: 8, Name: "Emma Watson", Position: "Manager", Salary: 82000},
-					{ID: 9, Name: "Tom Hardy", Position: "Developer", Salary: 48000},
-					{ID: 10, Name: "Olivia Williams", Position: "Designer", Salary: 69000},
+					{ID: 1, Name: "John Doe", Position: "Developer", Salary: models.MoneyOf(50000), Currency: "USD", Status: models.StatusActive},
+					{ID: 2, Name: "Jane Doe", Position: "Manager", Salary: models.MoneyOf(80000), Currency: "USD", Status: models.StatusActive},
+					{ID: 3, Name: "Bob Smith", Position: "Designer", Salary: models.MoneyOf(70000), Currency: "USD", Status: models.StatusActive},
+					{ID: 4, Name: "Alice Johnson", Position: "Engineer", Salary: models.MoneyOf(60000), Currency: "USD", Status: models.StatusActive},
+					{ID: 5, Name: "Mark Brown", Position: "Analyst", Salary: models.MoneyOf(55000), Currency: "USD", Status: models.StatusActive},
+					{ID: 6, Name: "Sarah Lee", Position: "Architect", Salary: models.MoneyOf(75000), Currency: "USD", Status: models.StatusActive},
+					{ID: 7, Name: "Chris Evans", Position: "Coordinator", Salary: models.MoneyOf(45000), Currency: "USD", Status: models.StatusActive},
+					{ID: 8, Name: "Emma Watson", Position: "Manager", Salary: models.MoneyOf(82000), Currency: "USD", Status: models.StatusActive},
+					{ID: 9, Name: "Tom Hardy", Position: "Developer", Salary: models.MoneyOf(48000), Currency: "USD", Status: models.StatusActive},
+					{ID: 10, Name: "Olivia Williams", Position: "Designer", Salary: models.MoneyOf(69000), Currency: "USD", Status: models.StatusActive},
 				},
 				LastEvalKeyID: 10,
 			}
 			for _, emp := range expected.Employees {
-				eh.CreateEmployee(emp)
+				eh.CreateEmployee(context.Background(), emp)
 			}
 
 			// when
-			res, err := eh.GetEmployee(empID, LastEvalKeyID, numRecords)
+			res, err := eh.GetEmployee(context.Background(), empID, LastEvalKeyID, numRecords)
 
 			// then
 			Expect(err).To(BeNil())
 			Expect(res.Employees).To(Equal(expected.Employees))
 		})
 
+		It("should cap the number of records at the maximum page size", func() {
+			// given
+			eh.SetPageSizes(1, 2)
+			for i := 1; i <= 3; i++ {
+				eh.CreateEmployee(context.Background(), models.Employee{ID: i, Name: "John Doe", Position: "Developer", Salary: models.MoneyOf(50000)})
+			}
+
+			// when
+			res, err := eh.GetEmployee(context.Background(), "", "", "100000")
+
+			// then
+			Expect(err).To(BeNil())
+			Expect(res.Employees).To(HaveLen(2))
+			Expect(res.LastEvalKeyID).To(Equal(2))
+		})
+
 		It("should return an error with InvalidID when empID is not a valid integer", func() {
 			// given
 			empID := "invalid"
@@ -273,7 +290,7 @@ var _ = Describe("Employee", func() {
 			numRecords := ""
 
 			// when
-			_, err := eh.GetEmployee(empID, startID, numRecords)
+			_, err := eh.GetEmployee(context.Background(), empID, startID, numRecords)
 
 			// then
 			Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
@@ -286,7 +303,7 @@ var _ = Describe("Employee", func() {
 			numRecords := ""
 
 			// when
-			_, err := eh.GetEmployee(empID, startID, numRecords)
+			_, err := eh.GetEmployee(context.Background(), empID, startID, numRecords)
 
 			// then
 			Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
@@ -299,7 +316,7 @@ var _ = Describe("Employee", func() {
 			numRecords := ""
 
 			// when
-			_, err := eh.GetEmployee(empID, startID, numRecords)
+			_, err := eh.GetEmployee(context.Background(), empID, startID, numRecords)
 
 			// then
 			Expect(err).To(Equal(employee.GetEmpError(employee.InvalidLastEvalKeyID)))
@@ -312,7 +329,7 @@ var _ = Describe("Employee", func() {
 			numRecords := ""
 
 			// when
-			_, err := eh.GetEmployee(empID, startID, numRecords)
+			_, err := eh.GetEmployee(context.Background(), empID, startID, numRecords)
 
 			// then
 			Expect(err).To(Equal(employee.GetEmpError(employee.InvalidLastEvalKeyID)))
@@ -328,7 +345,7 @@ var _ = Describe("Employee", func() {
 				empUpdateReq := models.EmployeeUpdateRequest{}
 
 				// when
-				err := eh.UpdateEmployee(empID, empUpdateReq)
+				err := eh.UpdateEmployee(context.Background(), empID, empUpdateReq)
 
 				// then
 				Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
@@ -341,11 +358,11 @@ var _ = Describe("Employee", func() {
 				empID := "1"
 				empUpdateReq := models.EmployeeUpdateRequest{
 					Position: "",
-					Salary:   0,
+					Salary:   models.MoneyOf(0),
 				}
 
 				// when
-				err := eh.UpdateEmployee(empID, empUpdateReq)
+				err := eh.UpdateEmployee(context.Background(), empID, empUpdateReq)
 
 				// then
 				Expect(err).To(Equal(employee.GetEmpError(employee.InvalidEmpUpdate)))
@@ -358,11 +375,11 @@ var _ = Describe("Employee", func() {
 				empID := "1"
 				empUpdateReq := models.EmployeeUpdateRequest{
 					Position: "Manager",
-					Salary:   -100,
+					Salary:   models.MoneyOf(-100),
 				}
 
 				// when
-				err := eh.UpdateEmployee(empID, empUpdateReq)
+				err := eh.UpdateEmployee(context.Background(), empID, empUpdateReq)
 
 				// then
 				Expect(err).To(Equal(employee.GetEmpError(employee.InvalidSalary)))
@@ -375,11 +392,11 @@ var _ = Describe("Employee", func() {
 				empID := "999"
 				empUpdateReq := models.EmployeeUpdateRequest{
 					Position: "Manager",
-					Salary:   50000,
+					Salary:   models.MoneyOf(50000),
 				}
 
 				// when
-				err := eh.UpdateEmployee(empID, empUpdateReq)
+				err := eh.UpdateEmployee(context.Background(), empID, empUpdateReq)
 
 				// then
 				Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
@@ -392,11 +409,11 @@ var _ = Describe("Employee", func() {
 				empID := "invalid"
 				empUpdateReq := models.EmployeeUpdateRequest{
 					Position: "Manager",
-					Salary:   50000,
+					Salary:   models.MoneyOf(50000),
 				}
 
 				// when
-				err := eh.UpdateEmployee(empID, empUpdateReq)
+				err := eh.UpdateEmployee(context.Background(), empID, empUpdateReq)
 
 				// then
 				Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
@@ -410,19 +427,19 @@ var _ = Describe("Employee", func() {
 				empUpdateReq := models.EmployeeUpdateRequest{
 					Position: "Manager",
 				}
-				eh.CreateEmployee(models.Employee{
+				eh.CreateEmployee(context.Background(), models.Employee{
 					ID:       1,
 					Name:     "John Doe",
 					Position: "Software Engineer",
-					Salary:   10000,
+					Salary:   models.MoneyOf(10000),
 				})
 
 				// when
-				err := eh.UpdateEmployee(empID, empUpdateReq)
+				err := eh.UpdateEmployee(context.Background(), empID, empUpdateReq)
 
 				// then
 				Expect(err).To(BeNil())
-				emp, err := eh.GetEmployee(empID, "", "")
+				emp, err := eh.GetEmployee(context.Background(), empID, "", "")
 				Expect(err).To(BeNil())
 				Expect(emp.Employees[0].Position).To(Equal("Manager"))
 			})
@@ -433,23 +450,23 @@ var _ = Describe("Employee", func() {
 				// given
 				empID := "1"
 				empUpdateReq := models.EmployeeUpdateRequest{
-					Salary: 20000,
+					Salary: models.MoneyOf(20000),
 				}
-				eh.CreateEmployee(models.Employee{
+				eh.CreateEmployee(context.Background(), models.Employee{
 					ID:       1,
 					Name:     "John Doe",
 					Position: "Software Engineer",
-					Salary:   10000,
+					Salary:   models.MoneyOf(10000),
 				})
 
 				// when
-				err := eh.UpdateEmployee(empID, empUpdateReq)
+				err := eh.UpdateEmployee(context.Background(), empID, empUpdateReq)
 
 				// then
 				Expect(err).To(BeNil())
-				emp, err := eh.GetEmployee(empID, "", "")
+				emp, err := eh.GetEmployee(context.Background(), empID, "", "")
 				Expect(err).To(BeNil())
-				Expect(emp.Employees[0].Salary).To(Equal(float64(20000)))
+				Expect(emp.Employees[0].Salary).To(Equal(models.MoneyOf(20000)))
 			})
 		})
 	})
@@ -462,7 +479,7 @@ var _ = Describe("Employee", func() {
 				empID := ""
 
 				// when
-				err := eh.DeleteEmployee(empID)
+				err := eh.DeleteEmployee(context.Background(), empID)
 
 				// then
 				Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
@@ -475,7 +492,7 @@ var _ = Describe("Employee", func() {
 				empID := "invalid"
 
 				// when
-				err := eh.DeleteEmployee(empID)
+				err := eh.DeleteEmployee(context.Background(), empID)
 
 				// then
 				Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
@@ -488,7 +505,7 @@ var _ = Describe("Employee", func() {
 				empID := "999"
 
 				// when
-				err := eh.DeleteEmployee(empID)
+				err := eh.DeleteEmployee(context.Background(), empID)
 
 				// then
 				Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
@@ -499,15 +516,15 @@ var _ = Describe("Employee", func() {
 			It("should delete the employee from the database", func() {
 				// given
 				empID := "1"
-				eh.CreateEmployee(models.Employee{
+				eh.CreateEmployee(context.Background(), models.Employee{
 					ID:       1,
 					Name:     "John Doe",
 					Position: "Software Engineer",
-					Salary:   10000,
+					Salary:   models.MoneyOf(10000),
 				})
 
 				// when
-				err := eh.DeleteEmployee(empID)
+				err := eh.DeleteEmployee(context.Background(), empID)
 
 				// then
 				Expect(err).To(BeNil())
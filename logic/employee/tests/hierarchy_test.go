@@ -0,0 +1,122 @@
+package employee_test
+
+import (
+	"context"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/service/simpledb"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reporting hierarchy", func() {
+	var (
+		eh  *employee.Employee
+		ctx = context.Background()
+	)
+
+	ids := func(emps []models.Employee) []int {
+		res := []int{}
+		for _, e := range emps {
+			res = append(res, e.ID)
+		}
+		return res
+	}
+
+	reports := func(id string, recursive bool) []int {
+		res, err := eh.GetReports(ctx, id, recursive)
+		Expect(err).To(BeNil())
+		return ids(res.Employees)
+	}
+
+	chain := func(id string) []int {
+		res, err := eh.GetChain(ctx, id)
+		Expect(err).To(BeNil())
+		return ids(res.Employees)
+	}
+
+	BeforeEach(func() {
+		var d simpledb.Database[int, models.Employee]
+		eh = employee.NewEmployeeWithDB(d.Init())
+		// 1 leads 2 and 3, 2 leads 4 and 5, 3 leads 6
+		for _, emp := range []models.Employee{
+			{ID: 1, Name: "Grace", Position: "CEO", Salary: models.MoneyOf(300)},
+			{ID: 2, Name: "Ada", Position: "Director", Salary: models.MoneyOf(200), ManagerID: 1},
+			{ID: 3, Name: "Alan", Position: "Director", Salary: models.MoneyOf(200), ManagerID: 1},
+			{ID: 5, Name: "Edsger", Position: "Engineer", Salary: models.MoneyOf(100), ManagerID: 2},
+			{ID: 4, Name: "Barbara", Position: "Engineer", Salary: models.MoneyOf(100), ManagerID: 2},
+			{ID: 6, Name: "Donald", Position: "Engineer", Salary: models.MoneyOf(100), ManagerID: 3},
+		} {
+			Expect(eh.CreateEmployee(ctx, emp)).To(BeNil())
+		}
+	})
+
+	It("lists direct and all reports", func() {
+		Expect(reports("1", false)).To(Equal([]int{2, 3}))
+		Expect(reports("2", false)).To(Equal([]int{4, 5}))
+		Expect(reports("1", true)).To(Equal([]int{2, 3, 4, 5, 6}))
+		Expect(reports("6", true)).To(BeEmpty())
+
+		_, err := eh.GetReports(ctx, "7", false)
+		Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
+	})
+
+	It("lists the management chain up to the top", func() {
+		Expect(chain("5")).To(Equal([]int{2, 1}))
+		Expect(chain("1")).To(BeEmpty())
+	})
+
+	It("rejects managers that do not exist", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 7, Name: "Ken", Position: "Engineer", Salary: models.MoneyOf(100), ManagerID: 42})).
+			To(Equal(employee.GetEmpError(employee.ManagerNotFound)))
+		manager := 42
+		Expect(eh.UpdateEmployee(ctx, "6", models.EmployeeUpdateRequest{ManagerID: &manager})).
+			To(Equal(employee.GetEmpError(employee.ManagerNotFound)))
+	})
+
+	It("rejects managers that report to the employee", func() {
+		manager := 4
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{ManagerID: &manager})).
+			To(Equal(employee.GetEmpError(employee.ManagerCycle)))
+
+		manager = 3
+		Expect(eh.UpdateEmployee(ctx, "4", models.EmployeeUpdateRequest{ManagerID: &manager})).To(BeNil())
+		Expect(reports("2", false)).To(Equal([]int{5}))
+		Expect(chain("4")).To(Equal([]int{3, 1}))
+
+		manager = 0
+		Expect(eh.UpdateEmployee(ctx, "3", models.EmployeeUpdateRequest{ManagerID: &manager})).To(BeNil())
+		Expect(chain("4")).To(Equal([]int{3}))
+	})
+
+	It("refuses to delete an employee with reports", func() {
+		Expect(eh.DeleteEmployee(ctx, "3")).To(Equal(employee.GetEmpError(employee.HasReports)))
+		Expect(eh.DeleteEmployee(ctx, "6")).To(BeNil())
+		Expect(eh.DeleteEmployee(ctx, "3")).To(BeNil())
+		Expect(reports("1", false)).To(Equal([]int{2}))
+	})
+
+	It("moves a whole team to another manager", func() {
+		moved, err := eh.MoveReports(ctx, "2", 3)
+		Expect(err).To(BeNil())
+		Expect(moved).To(Equal([]int{4, 5}))
+		Expect(reports("2", false)).To(BeEmpty())
+		Expect(reports("3", false)).To(Equal([]int{4, 5, 6}))
+
+		res, err := eh.GetEmployee(ctx, "5", "", "")
+		Expect(err).To(BeNil())
+		Expect(res.Employees[0].ManagerID).To(Equal(3))
+	})
+
+	It("moves nobody when the new manager is in the team", func() {
+		_, err := eh.MoveReports(ctx, "1", 5)
+		Expect(err).To(Equal(employee.GetEmpError(employee.ManagerCycle)))
+		Expect(reports("1", false)).To(Equal([]int{2, 3}))
+
+		_, err = eh.MoveReports(ctx, "2", 42)
+		Expect(err).To(Equal(employee.GetEmpError(employee.ManagerNotFound)))
+		_, err = eh.MoveReports(ctx, "2", 2)
+		Expect(err).To(Equal(employee.GetEmpError(employee.InvalidManagerID)))
+	})
+})
@@ -0,0 +1,94 @@
+package employee_test
+
+import (
+	"context"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/pkg/logger"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+// These salaries are distinctive enough that any occurrence in a sink is a leak.
+var (
+	createdSalary = models.MustParseMoney("987654.32")
+	updatedSalary = models.MustParseMoney("876543.21")
+	invalidSalary = models.MustParseMoney("-765432.1")
+)
+
+var _ = Describe("Salary logging", func() {
+	var sinks []string
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+		sinks = []string{filepath.Join(dir, "json.log"), filepath.Join(dir, "console.log")}
+		previous, level := logger.Log, zerolog.GlobalLevel()
+		DeferCleanup(func() {
+			logger.Log = previous
+			zerolog.SetGlobalLevel(level)
+		})
+	})
+
+	for _, format := range []string{logger.FormatJSON, logger.FormatConsole} {
+		format := format
+		It("should never write a salary to a "+format+" sink at debug level", func() {
+			sink := sinks[0]
+			if format == logger.FormatConsole {
+				sink = sinks[1]
+			}
+			closer, err := logger.Init(logger.Options{Level: "Debug", Format: format, Outputs: []string{sink}})
+			Expect(err).To(BeNil())
+
+			eh := employee.NewEmployee()
+			ctx := context.Background()
+			Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: createdSalary})).To(Succeed())
+			Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: updatedSalary})).To(Succeed())
+			Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: invalidSalary})).NotTo(Succeed())
+			Expect(eh.CreateEmployee(ctx, models.Employee{ID: 2, Name: "Bob", Position: "Engineer", Salary: invalidSalary})).NotTo(Succeed())
+			_, err = eh.GetEmployee(ctx, "1", "", "")
+			Expect(err).To(BeNil())
+			Expect(closer.Close()).To(Succeed())
+
+			data, err := os.ReadFile(sink)
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(ContainSubstring("Update Request received"))
+			for _, salary := range []string{"987654", "876543", "765432"} {
+				Expect(string(data)).NotTo(ContainSubstring(salary))
+			}
+			Expect(strings.ToLower(string(data))).NotTo(ContainSubstring("ada"))
+		})
+
+		It("should never write a salary band to a "+format+" sink at debug level", func() {
+			sink := sinks[0]
+			if format == logger.FormatConsole {
+				sink = sinks[1]
+			}
+			closer, err := logger.Init(logger.Options{Level: "Debug", Format: format, Outputs: []string{sink}})
+			Expect(err).To(BeNil())
+
+			eh := employee.NewEmployee()
+			ctx := context.Background()
+			band := models.Position{Title: "Engineer", MinSalary: models.MustParseMoney("432109.87"), MidSalary: models.MustParseMoney("543210.98"), MaxSalary: models.MustParseMoney("654321.09")}
+			Expect(eh.CreatePosition(ctx, band)).To(Succeed())
+			invalid := models.Position{Title: "Manager", MinSalary: band.MaxSalary, MidSalary: band.MidSalary, MaxSalary: band.MinSalary}
+			Expect(eh.CreatePosition(ctx, invalid)).NotTo(Succeed())
+			Expect(eh.UpdatePosition(ctx, "Engineer", models.PositionUpdateRequest{MinSalary: band.MaxSalary, MidSalary: band.MidSalary, MaxSalary: band.MinSalary})).NotTo(Succeed())
+			Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: createdSalary})).NotTo(Succeed())
+			Expect(eh.CreateEmployeeWithJustification(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: createdSalary}, "Exceptional hire")).To(Succeed())
+			Expect(closer.Close()).To(Succeed())
+
+			data, err := os.ReadFile(sink)
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(ContainSubstring("Invalid salary band"))
+			Expect(string(data)).To(ContainSubstring("Salary outside band accepted with a justification"))
+			for _, salary := range []string{"432109", "543210", "654321", "987654"} {
+				Expect(string(data)).NotTo(ContainSubstring(salary))
+			}
+		})
+	}
+})
@@ -0,0 +1,127 @@
+package employee_test
+
+import (
+	"context"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/pkg/auth"
+	"employee/service/simpledb"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Positions", func() {
+	var (
+		eh  *employee.Employee
+		now time.Time
+		ctx context.Context
+	)
+
+	history := func() []models.SalaryChange {
+		res, err := eh.GetSalaryHistory(ctx, "1")
+		Expect(err).To(BeNil())
+		return res.Changes
+	}
+
+	BeforeEach(func() {
+		var d simpledb.Database[int, models.Employee]
+		eh = employee.NewEmployeeWithDB(d.Init())
+		now = time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+		eh.SetClock(func() time.Time { return now })
+		ctx = auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "hr"})
+		Expect(eh.CreatePosition(ctx, models.Position{Title: "Engineer", MinSalary: models.MoneyOf(100), MidSalary: models.MoneyOf(150), MaxSalary: models.MoneyOf(200)})).To(BeNil())
+		Expect(eh.CreatePosition(ctx, models.Position{Title: "Manager", MinSalary: models.MoneyOf(180), MidSalary: models.MoneyOf(240), MaxSalary: models.MoneyOf(300)})).To(BeNil())
+	})
+
+	It("creates, lists, updates and deletes positions", func() {
+		Expect(eh.CreatePosition(ctx, models.Position{Title: "Engineer", MinSalary: models.MoneyOf(1), MidSalary: models.MoneyOf(2), MaxSalary: models.MoneyOf(3)})).
+			To(Equal(employee.GetEmpError(employee.PositionAlreadyExists)))
+		Expect(eh.CreatePosition(ctx, models.Position{Title: "Analyst", MinSalary: models.MoneyOf(200), MidSalary: models.MoneyOf(150), MaxSalary: models.MoneyOf(300)})).
+			To(Equal(employee.GetEmpError(employee.InvalidPositionBand)))
+		Expect(eh.CreatePosition(ctx, models.Position{Title: "Analyst", MidSalary: models.MoneyOf(150), MaxSalary: models.MoneyOf(300)})).
+			To(Equal(employee.GetEmpError(employee.InvalidPositionBand)))
+		Expect(eh.CreatePosition(ctx, models.Position{Title: strings.Repeat("a", 101), MinSalary: models.MoneyOf(1), MidSalary: models.MoneyOf(2), MaxSalary: models.MoneyOf(3)})).
+			To(Equal(employee.GetEmpError(employee.InvalidPosition)))
+
+		res, err := eh.GetPositions(ctx, "", "", "1")
+		Expect(err).To(BeNil())
+		Expect(res.Positions).To(Equal([]models.Position{{Title: "Engineer", MinSalary: models.MoneyOf(100), MidSalary: models.MoneyOf(150), MaxSalary: models.MoneyOf(200), Currency: "USD"}}))
+		res, err = eh.GetPositions(ctx, "", res.LastEvalKeyID, "10")
+		Expect(err).To(BeNil())
+		Expect(res.Positions).To(HaveLen(1))
+		Expect(res.Positions[0].Title).To(Equal("Manager"))
+		Expect(res.LastEvalKeyID).To(BeEmpty())
+
+		Expect(eh.UpdatePosition(ctx, "Engineer", models.PositionUpdateRequest{MinSalary: models.MoneyOf(110), MidSalary: models.MoneyOf(160), MaxSalary: models.MoneyOf(210)})).To(BeNil())
+		res, err = eh.GetPositions(ctx, "Engineer", "", "")
+		Expect(err).To(BeNil())
+		Expect(res.Positions).To(Equal([]models.Position{{Title: "Engineer", MinSalary: models.MoneyOf(110), MidSalary: models.MoneyOf(160), MaxSalary: models.MoneyOf(210), Currency: "USD"}}))
+		Expect(eh.UpdatePosition(ctx, "Analyst", models.PositionUpdateRequest{MinSalary: models.MoneyOf(1), MidSalary: models.MoneyOf(2), MaxSalary: models.MoneyOf(3)})).
+			To(Equal(employee.GetEmpError(employee.PositionNotFound)))
+
+		Expect(eh.DeletePosition(ctx, "Manager")).To(BeNil())
+		_, err = eh.GetPositions(ctx, "Manager", "", "")
+		Expect(err).To(Equal(employee.GetEmpError(employee.PositionNotFound)))
+		Expect(eh.DeletePosition(ctx, "Manager")).To(Equal(employee.GetEmpError(employee.PositionNotFound)))
+	})
+
+	It("rejects a salary outside the band without a justification", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(250)})).
+			To(Equal(employee.GetEmpError(employee.SalaryOutsideBand)))
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(200)})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(90)})).
+			To(Equal(employee.GetEmpError(employee.SalaryOutsideBand)))
+		// A scheduled raise must fit the band too
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(210), Currency: "USD", EffectiveDate: "2026-04-01"})).
+			To(Equal(employee.GetEmpError(employee.SalaryOutsideBand)))
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Position: "Manager", Salary: models.MoneyOf(250)})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Position: "Engineer", Salary: models.MoneyOf(170), EffectiveDate: "2026-04-01"})).
+			To(Equal(employee.GetEmpError(employee.SalaryOutsideBand)))
+	})
+
+	It("records the justification of a salary accepted outside the band", func() {
+		Expect(eh.CreateEmployeeWithJustification(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(250)}, "market rate")).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(260), SalaryJustification: "retention"})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(190), SalaryJustification: "not needed"})).To(BeNil())
+
+		Expect(history()).To(Equal([]models.SalaryChange{
+			{Salary: models.MoneyOf(250), Currency: "USD", EffectiveDate: "2026-03-10", RecordedBy: "hr", RecordedAt: now, OverrideJustification: "market rate"},
+			{Salary: models.MoneyOf(260), Currency: "USD", EffectiveDate: "2026-03-10", RecordedBy: "hr", RecordedAt: now, OverrideJustification: "retention"},
+			{Salary: models.MoneyOf(190), Currency: "USD", EffectiveDate: "2026-03-10", RecordedBy: "hr", RecordedAt: now},
+		}))
+	})
+
+	It("checks the salary in effect against the band of a new position", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(150)})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Position: "Manager"})).
+			To(Equal(employee.GetEmpError(employee.SalaryOutsideBand)))
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Position: "Manager", Salary: models.MoneyOf(240)})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Position: "Engineer", SalaryJustification: "temporary cover"})).To(BeNil())
+
+		res, err := eh.GetEmployee(ctx, "1", "", "")
+		Expect(err).To(BeNil())
+		Expect(res.Employees[0].Position).To(Equal("Engineer"))
+		Expect(res.Employees[0].Salary).To(Equal(models.MoneyOf(240)))
+		Expect(history()[2]).To(Equal(models.SalaryChange{Salary: models.MoneyOf(240), Currency: "USD", EffectiveDate: "2026-03-10", Reason: "position change",
+			RecordedBy: "hr", RecordedAt: now, OverrideJustification: "temporary cover"}))
+	})
+
+	It("validates the justification", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(150)})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Email: "ada@example.com", SalaryJustification: "retention"})).
+			To(Equal(employee.GetEmpError(employee.InvalidSalaryJustification)))
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(300), SalaryJustification: strings.Repeat("a", 501)})).
+			To(Equal(employee.GetEmpError(employee.InvalidSalaryJustification)))
+		Expect(eh.CreateEmployeeWithJustification(ctx, models.Employee{ID: 2, Name: "Alan", Position: "Engineer", Salary: models.MoneyOf(300)}, strings.Repeat("a", 501))).
+			To(Equal(employee.GetEmpError(employee.InvalidSalaryJustification)))
+	})
+
+	It("accepts any salary for a position missing from the catalogue", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Researcher", Salary: models.MoneyOf(1000)})).To(BeNil())
+		Expect(eh.DeletePosition(ctx, "Engineer")).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Position: "Engineer", Salary: models.MoneyOf(5)})).To(BeNil())
+	})
+})
@@ -0,0 +1,138 @@
+package employee_test
+
+import (
+	"context"
+	"employee/logic/audit"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/service/simpledb"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Soft delete and retention", func() {
+	var (
+		eh  *employee.Employee
+		db  *simpledb.Database[int, models.Employee]
+		now time.Time
+		ctx = context.Background()
+	)
+
+	ids := func() []int {
+		res, err := eh.GetEmployee(ctx, "", "", "")
+		Expect(err).To(BeNil())
+		ids := []int{}
+		for _, e := range res.Employees {
+			ids = append(ids, e.ID)
+		}
+		return ids
+	}
+
+	BeforeEach(func() {
+		var d simpledb.Database[int, models.Employee]
+		db = d.Init()
+		eh = employee.NewEmployeeWithDB(db)
+		now = time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+		eh.SetClock(func() time.Time { return now })
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Grace", Position: "Director", Salary: models.MoneyOf(200)})).To(BeNil())
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 2, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100), Email: "ada@example.com", ManagerID: 1})).To(BeNil())
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 3, Name: "Alan", Position: "Engineer", Salary: models.MoneyOf(100)})).To(BeNil())
+	})
+
+	It("keeps deleted employees out of every read", func() {
+		Expect(eh.DeleteEmployee(ctx, "2")).To(BeNil())
+
+		stored, ok := db.GetItem(ctx, 2)
+		Expect(ok).To(BeTrue())
+		Expect(*stored.DeletedAt).To(Equal(now))
+
+		Expect(ids()).To(Equal([]int{1, 3}))
+		res, err := eh.GetEmployee(ctx, "", "", "1")
+		Expect(err).To(BeNil())
+		res, err = eh.GetEmployee(ctx, "", "1", "1")
+		Expect(err).To(BeNil())
+		Expect(res.Employees[0].ID).To(Equal(3))
+
+		_, err = eh.GetEmployee(ctx, "2", "", "")
+		Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
+		_, err = eh.GetSalaryHistory(ctx, "2")
+		Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
+		Expect(eh.UpdateEmployee(ctx, "2", models.EmployeeUpdateRequest{Position: "Lead"})).To(Equal(employee.GetEmpError(employee.InvalidID)))
+		Expect(eh.DeleteEmployee(ctx, "2")).To(Equal(employee.GetEmpError(employee.InvalidID)))
+		reports, err := eh.GetReports(ctx, "1", false)
+		Expect(err).To(BeNil())
+		Expect(reports.Employees).To(BeEmpty())
+	})
+
+	It("restores a deleted employee", func() {
+		Expect(eh.DeleteEmployee(ctx, "2")).To(BeNil())
+		Expect(eh.RestoreEmployee(ctx, "2")).To(BeNil())
+		Expect(ids()).To(Equal([]int{1, 2, 3}))
+		res, err := eh.GetEmployee(ctx, "2", "", "")
+		Expect(err).To(BeNil())
+		Expect(res.Employees[0].DeletedAt).To(BeNil())
+
+		Expect(eh.RestoreEmployee(ctx, "2")).To(Equal(employee.GetEmpError(employee.EmpNotDeleted)))
+		Expect(eh.RestoreEmployee(ctx, "42")).To(Equal(employee.GetEmpError(employee.InvalidID)))
+	})
+
+	It("checks the email and manager of a restored employee again", func() {
+		Expect(eh.DeleteEmployee(ctx, "2")).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "3", models.EmployeeUpdateRequest{Email: "ada@example.com"})).To(BeNil())
+		Expect(eh.RestoreEmployee(ctx, "2")).To(Equal(employee.GetEmpError(employee.EmailAlreadyExists)))
+
+		Expect(eh.UpdateEmployee(ctx, "3", models.EmployeeUpdateRequest{Email: "alan@example.com"})).To(BeNil())
+		Expect(eh.DeleteEmployee(ctx, "1")).To(BeNil())
+		Expect(eh.RestoreEmployee(ctx, "2")).To(Equal(employee.GetEmpError(employee.ManagerNotFound)))
+		Expect(eh.RestoreEmployee(ctx, "1")).To(BeNil())
+		Expect(eh.RestoreEmployee(ctx, "2")).To(BeNil())
+	})
+
+	It("purges employees once the retention period has passed", func() {
+		now = now.Add(24 * time.Hour)
+		Expect(eh.DeleteEmployee(ctx, "3")).To(BeNil())
+		now = now.Add(24 * time.Hour)
+		Expect(eh.DeleteEmployee(ctx, "2")).To(BeNil())
+
+		now = now.Add(30*24*time.Hour - 12*time.Hour)
+		Expect(eh.PurgeDeleted(ctx, 30*24*time.Hour)).To(Equal(1))
+		_, ok := db.GetItem(ctx, 3)
+		Expect(ok).To(BeFalse())
+		Expect(eh.RestoreEmployee(ctx, "3")).To(Equal(employee.GetEmpError(employee.InvalidID)))
+
+		// The ID is free again, the purged employee is still there in past versions
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 3, Name: "Barbara", Position: "Engineer", Salary: models.MoneyOf(100)})).To(BeNil())
+		res, err := eh.GetEmployeeAsOf(ctx, "3", "", "", "2026-03-10", "2026-03-10")
+		Expect(err).To(BeNil())
+		Expect(res.Employees[0].Name).To(Equal("Alan"))
+
+		Expect(eh.RestoreEmployee(ctx, "2")).To(BeNil())
+		Expect(eh.PurgeDeleted(ctx, 0)).To(Equal(0))
+	})
+
+	It("keeps the changes to a reused ID out of the purged employee", func() {
+		trail := audit.NewTrail()
+		trail.SetClock(func() time.Time { return now })
+		eh.SetAuditTrail(trail)
+		now = now.Add(24 * time.Hour)
+		Expect(eh.DeleteEmployee(ctx, "3")).To(BeNil())
+		now = now.Add(24 * time.Hour)
+		Expect(eh.PurgeDeleted(ctx, 0)).To(Equal(1))
+
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 3, Name: "Barbara", Position: "Engineer", Salary: models.MoneyOf(100)})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "3", models.EmployeeUpdateRequest{Salary: models.MoneyOf(120), EffectiveDate: "2026-03-10"})).To(BeNil())
+		res, err := eh.GetEmployeeAsOf(ctx, "3", "", "", "2026-03-10", "")
+		Expect(err).To(BeNil())
+		Expect(res.Employees[0]).To(And(HaveField("Name", "Alan"), HaveField("Salary", models.MoneyOf(100))))
+
+		// The trail is never purged, it keeps nothing of the employee but the ID
+		entries, err := trail.List(ctx, "3")
+		Expect(err).To(BeNil())
+		purge := entries[1]
+		Expect(purge.Action).To(Equal(models.AuditPurge))
+		Expect(purge.Timestamp).To(Equal(now))
+		Expect(purge.Changes).To(Equal([]models.FieldChange{{Field: "id", Old: []byte("3")}}))
+	})
+})
@@ -0,0 +1,135 @@
+package employee_test
+
+import (
+	"context"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/pkg/auth"
+	"employee/service/simpledb"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Salary history", func() {
+	var (
+		eh  *employee.Employee
+		db  *simpledb.Database[int, models.Employee]
+		now time.Time
+		ctx context.Context
+	)
+
+	salaryOn := func(asOf string) models.Money {
+		res, err := eh.GetEmployeeAsOf(ctx, "1", "", "", asOf, "")
+		Expect(err).To(BeNil())
+		return res.Employees[0].Salary
+	}
+
+	BeforeEach(func() {
+		var d simpledb.Database[int, models.Employee]
+		db = d.Init()
+		eh = employee.NewEmployeeWithDB(db)
+		now = time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+		eh.SetClock(func() time.Time { return now })
+		ctx = auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "hr"})
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100)})).To(BeNil())
+	})
+
+	It("records the starting salary effective on the day of creation", func() {
+		res, err := eh.GetSalaryHistory(ctx, "1")
+		Expect(err).To(BeNil())
+		Expect(res.EmployeeID).To(Equal(1))
+		Expect(res.Changes).To(Equal([]models.SalaryChange{
+			{Salary: models.MoneyOf(100), Currency: "USD", EffectiveDate: "2026-03-10", RecordedBy: "hr", RecordedAt: now},
+		}))
+	})
+
+	It("keeps a scheduled raise out of the salary until its effective date", func() {
+		err := eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(120), EffectiveDate: "2026-04-01", Reason: "promotion"})
+		Expect(err).To(BeNil())
+
+		Expect(salaryOn("")).To(Equal(models.MoneyOf(100)))
+		Expect(salaryOn("2026-03-31")).To(Equal(models.MoneyOf(100)))
+		Expect(salaryOn("2026-04-01")).To(Equal(models.MoneyOf(120)))
+
+		now = time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+		Expect(salaryOn("")).To(Equal(models.MoneyOf(120)))
+	})
+
+	It("orders the changes by effective date", func() {
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(130), EffectiveDate: "2026-06-01"})).To(BeNil())
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(110), EffectiveDate: "2026-01-01", Reason: "backdated correction"})).To(BeNil())
+
+		res, err := eh.GetSalaryHistory(ctx, "1")
+		Expect(err).To(BeNil())
+		var dates []string
+		for _, ch := range res.Changes {
+			dates = append(dates, ch.EffectiveDate)
+		}
+		Expect(dates).To(Equal([]string{"2026-01-01", "2026-03-10", "2026-06-01"}))
+		Expect(salaryOn("")).To(Equal(models.MoneyOf(100)))
+		Expect(salaryOn("2026-06-01")).To(Equal(models.MoneyOf(130)))
+	})
+
+	It("applies the latest of several changes effective the same day", func() {
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(105)})).To(BeNil())
+		Expect(salaryOn("")).To(Equal(models.MoneyOf(105)))
+		stored, _ := db.GetItem(ctx, 1)
+		Expect(stored.Salary).To(Equal(models.MoneyOf(105)))
+	})
+
+	It("seeds the history of employees stored without one", func() {
+		Expect(db.SetItem(ctx, 2, models.Employee{ID: 2, Name: "Grace", Position: "Engineer", Salary: models.MoneyOf(90)})).To(BeNil())
+		res, err := eh.GetEmployee(ctx, "2", "", "")
+		Expect(err).To(BeNil())
+		Expect(res.Employees[0].Salary).To(Equal(models.MoneyOf(90)))
+
+		Expect(eh.UpdateEmployee(ctx, "2", models.EmployeeUpdateRequest{Salary: models.MoneyOf(95), EffectiveDate: "2026-05-01"})).To(BeNil())
+		history, err := eh.GetSalaryHistory(ctx, "2")
+		Expect(err).To(BeNil())
+		Expect(history.Changes).To(HaveLen(2))
+		Expect(history.Changes[0].Salary).To(Equal(models.MoneyOf(90)))
+		res, err = eh.GetEmployee(ctx, "2", "", "")
+		Expect(err).To(BeNil())
+		Expect(res.Employees[0].Salary).To(Equal(models.MoneyOf(90)))
+	})
+
+	It("starts a new history when the ID is reused", func() {
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(120)})).To(BeNil())
+		Expect(eh.DeleteEmployee(ctx, "1")).To(BeNil())
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(80)})).
+			To(Equal(employee.GetEmpError(employee.EmpAlreadyExists)))
+		Expect(eh.PurgeDeleted(ctx, 0)).To(Equal(1))
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(80)})).To(BeNil())
+
+		res, err := eh.GetSalaryHistory(ctx, "1")
+		Expect(err).To(BeNil())
+		Expect(res.Changes).To(HaveLen(1))
+		Expect(res.Changes[0].Salary).To(Equal(models.MoneyOf(80)))
+	})
+
+	DescribeTable("rejects invalid requests",
+		func(req models.EmployeeUpdateRequest, code employee.EmpError) {
+			Expect(eh.UpdateEmployee(ctx, "1", req)).To(Equal(employee.GetEmpError(code)))
+			res, err := eh.GetSalaryHistory(ctx, "1")
+			Expect(err).To(BeNil())
+			Expect(res.Changes).To(HaveLen(1))
+		},
+		Entry("malformed effective date", models.EmployeeUpdateRequest{Salary: models.MoneyOf(120), EffectiveDate: "01/04/2026"}, employee.InvalidEffectiveDate),
+		Entry("effective date without salary", models.EmployeeUpdateRequest{Position: "Lead", EffectiveDate: "2026-04-01"}, employee.InvalidEffectiveDate),
+		Entry("reason without salary", models.EmployeeUpdateRequest{Position: "Lead", Reason: "promotion"}, employee.InvalidSalaryReason),
+		Entry("overlong reason", models.EmployeeUpdateRequest{Salary: models.MoneyOf(120), Reason: strings.Repeat("x", 201)}, employee.InvalidSalaryReason),
+	)
+
+	It("rejects a malformed as_of date", func() {
+		_, err := eh.GetEmployeeAsOf(ctx, "", "", "", "2026-13-01", "")
+		Expect(err).To(Equal(employee.GetEmpError(employee.InvalidAsOf)))
+	})
+
+	It("returns an error for an unknown employee", func() {
+		_, err := eh.GetSalaryHistory(ctx, "42")
+		Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
+	})
+})
@@ -0,0 +1,112 @@
+package employee_test
+
+import (
+	"context"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/pkg/auth"
+	"employee/pkg/currency"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Employee stats", func() {
+	var (
+		eh  *employee.Employee
+		now time.Time
+		ctx context.Context
+	)
+
+	create := func(id int, position, department, salary, code string) {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: id, Name: "Ada", Position: position, Department: department,
+			Salary: models.MustParseMoney(salary), Currency: code})).To(BeNil())
+	}
+
+	BeforeEach(func() {
+		eh = employee.NewEmployee()
+		now = time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+		eh.SetClock(func() time.Time { return now })
+		rates, err := currency.NewTable([]currency.Rate{{From: "EUR", To: "USD", Rate: "1.10", EffectiveDate: "2026-01-01"}})
+		Expect(err).To(BeNil())
+		eh.SetCurrencies("USD", rates)
+		ctx = auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "hr"})
+		Expect(eh.CreateDepartment(ctx, models.Department{ID: "eng", Name: "Engineering"})).To(BeNil())
+		Expect(eh.CreateDepartment(ctx, models.Department{ID: "ops", Name: "Operations"})).To(BeNil())
+
+		create(1, "Engineer", "eng", "100", "")
+		create(2, "Engineer", "eng", "200", "")
+		create(3, "Engineer", "ops", "300", "")
+		create(4, "Lead", "eng", "400", "")
+		create(5, "Lead", "ops", "454.55", "EUR")
+	})
+
+	It("summarizes every salary converted into the reporting currency", func() {
+		res, err := eh.GetEmployeeStats(ctx, models.EmployeeStatsRequest{})
+		Expect(err).To(BeNil())
+		// 454.55 EUR is 500.005 USD, counted as 500.01, and p90 is 400 + 0.6 * 100.01
+		Expect(res.Stats).To(Equal([]models.SalaryStats{{
+			Count:  5,
+			Sum:    models.MustParseMoney("1500.01"),
+			Mean:   models.MoneyOf(300),
+			Median: models.MoneyOf(300),
+			Percentiles: map[string]models.Money{
+				"p25": models.MoneyOf(200), "p75": models.MoneyOf(400), "p90": models.MustParseMoney("460.01"),
+			},
+			Currency: "USD",
+		}}))
+	})
+
+	It("counts scheduled salary changes once they take effect", func() {
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(600), EffectiveDate: "2026-04-01"})).To(BeNil())
+		res, err := eh.GetEmployeeStats(ctx, models.EmployeeStatsRequest{})
+		Expect(err).To(BeNil())
+		Expect(res.Stats[0].Sum).To(Equal(models.MustParseMoney("1500.01")))
+
+		now = time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+		res, err = eh.GetEmployeeStats(ctx, models.EmployeeStatsRequest{})
+		Expect(err).To(BeNil())
+		Expect(res.Stats[0].Sum).To(Equal(models.MustParseMoney("2000.01")))
+		Expect(res.Stats[0].Median).To(Equal(models.MoneyOf(400)))
+	})
+
+	It("groups and filters the employees", func() {
+		Expect(eh.DeleteEmployee(ctx, "3")).To(BeNil())
+
+		res, err := eh.GetEmployeeStats(ctx, models.EmployeeStatsRequest{GroupBy: employee.GroupByPosition, Percentiles: []string{"50", "100"}})
+		Expect(err).To(BeNil())
+		Expect(res.GroupBy).To(Equal("position"))
+		Expect(res.Stats).To(HaveLen(2))
+		Expect(res.Stats[0]).To(And(HaveField("Group", "Engineer"), HaveField("Count", 2), HaveField("Median", models.MoneyOf(150)),
+			HaveField("Percentiles", map[string]models.Money{"p50": models.MoneyOf(150), "p100": models.MoneyOf(200)})))
+		Expect(res.Stats[1]).To(And(HaveField("Group", "Lead"), HaveField("Sum", models.MustParseMoney("900.01"))))
+
+		res, err = eh.GetEmployeeStats(ctx, models.EmployeeStatsRequest{GroupBy: employee.GroupByDepartment, Position: "Lead", Currency: "EUR"})
+		Expect(err).To(BeNil())
+		Expect(res.Stats).To(HaveLen(2))
+		// 400 USD is 363.6363... EUR
+		Expect(res.Stats[0]).To(And(HaveField("Group", "eng"), HaveField("Sum", models.MustParseMoney("363.64"))))
+		Expect(res.Stats[1]).To(And(HaveField("Group", "ops"), HaveField("Sum", models.MustParseMoney("454.55"))))
+
+		res, err = eh.GetEmployeeStats(ctx, models.EmployeeStatsRequest{Status: models.StatusTerminated})
+		Expect(err).To(BeNil())
+		Expect(res.Stats).To(Equal([]models.SalaryStats{{Currency: "USD"}}))
+	})
+
+	It("rejects invalid queries", func() {
+		for _, req := range []models.EmployeeStatsRequest{
+			{GroupBy: "manager"},
+			{Percentiles: []string{"101"}},
+			{Percentiles: []string{"-5"}},
+			{Percentiles: []string{"p90"}},
+		} {
+			_, err := eh.GetEmployeeStats(ctx, req)
+			Expect(err).To(Equal(employee.GetEmpError(employee.InvalidStatsQuery)))
+		}
+		_, err := eh.GetEmployeeStats(ctx, models.EmployeeStatsRequest{Status: "retired"})
+		Expect(err).To(Equal(employee.GetEmpError(employee.InvalidStatus)))
+		_, err = eh.GetEmployeeStats(ctx, models.EmployeeStatsRequest{Currency: "JPY"})
+		Expect(err).To(Equal(employee.GetEmpError(employee.ExchangeRateNotFound)))
+	})
+})
@@ -0,0 +1,185 @@
+package employee_test
+
+import (
+	"context"
+	"employee/logic/audit"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/service/simpledb"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bitemporal reads", func() {
+	var (
+		eh  *employee.Employee
+		db  *simpledb.Database[int, models.Employee]
+		now time.Time
+		ctx = context.Background()
+	)
+
+	on := func(date string) {
+		var err error
+		now, err = time.Parse(time.DateOnly, date)
+		Expect(err).To(BeNil())
+		now = now.Add(9 * time.Hour)
+	}
+
+	getAsOf := func(id, asOf, knownAt string) (models.Employee, error) {
+		res, err := eh.GetEmployeeAsOf(ctx, id, "", "", asOf, knownAt)
+		if err != nil {
+			return models.Employee{}, err
+		}
+		return res.Employees[0], nil
+	}
+
+	ids := func(emps []models.Employee) []int {
+		var ids []int
+		for _, e := range emps {
+			ids = append(ids, e.ID)
+		}
+		return ids
+	}
+
+	BeforeEach(func() {
+		var d simpledb.Database[int, models.Employee]
+		db = d.Init()
+		eh = employee.NewEmployeeWithDB(db)
+		eh.SetClock(func() time.Time { return now })
+		on("2026-03-10")
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100)})).To(BeNil())
+	})
+
+	It("reconstructs the record valid on as_of", func() {
+		on("2026-03-20")
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Position: "Lead"})).To(BeNil())
+		on("2026-04-01")
+		Expect(eh.DeleteEmployee(ctx, "1")).To(BeNil())
+
+		emp, err := getAsOf("1", "2026-03-15", "")
+		Expect(err).To(BeNil())
+		Expect(emp).To(Equal(models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100), Currency: "USD", Status: models.StatusActive}))
+		emp, err = getAsOf("1", "2026-03-25", "")
+		Expect(err).To(BeNil())
+		Expect(emp.Position).To(Equal("Lead"))
+
+		_, err = getAsOf("1", "2026-04-01", "")
+		Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
+		_, err = getAsOf("1", "2026-03-09", "")
+		Expect(err).To(Equal(employee.GetEmpError(employee.InvalidID)))
+	})
+
+	It("ignores what was recorded after known_at", func() {
+		on("2026-03-20")
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(110), EffectiveDate: "2026-03-12", Reason: "backdated"})).To(BeNil())
+
+		emp, err := getAsOf("1", "2026-03-15", "2026-03-15")
+		Expect(err).To(BeNil())
+		Expect(emp.Salary).To(Equal(models.MoneyOf(100)))
+		emp, err = getAsOf("1", "2026-03-15", "2026-03-20T09:00:00Z")
+		Expect(err).To(BeNil())
+		Expect(emp.Salary).To(Equal(models.MoneyOf(110)))
+		emp, err = getAsOf("1", "", "2026-03-19")
+		Expect(err).To(BeNil())
+		Expect(emp.Salary).To(Equal(models.MoneyOf(100)))
+	})
+
+	It("drops changes scheduled before the employee was deleted and created again", func() {
+		Expect(eh.UpdateEmployee(ctx, "1", models.EmployeeUpdateRequest{Salary: models.MoneyOf(150), EffectiveDate: "2026-06-01"})).To(BeNil())
+		on("2026-04-01")
+		Expect(eh.DeleteEmployee(ctx, "1")).To(BeNil())
+		Expect(eh.PurgeDeleted(ctx, 0)).To(Equal(1))
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(90)})).To(BeNil())
+
+		emp, err := getAsOf("1", "2026-07-01", "")
+		Expect(err).To(BeNil())
+		Expect(emp.Salary).To(Equal(models.MoneyOf(90)))
+		emp, err = getAsOf("1", "2026-07-01", "2026-03-31")
+		Expect(err).To(BeNil())
+		Expect(emp.Salary).To(Equal(models.MoneyOf(150)))
+	})
+
+	It("pages through the roster including deleted employees", func() {
+		for id := 2; id <= 5; id++ {
+			Expect(eh.CreateEmployee(ctx, models.Employee{ID: id, Name: "Emp", Position: "Engineer", Salary: models.MoneyOf(100)})).To(BeNil())
+		}
+		on("2026-03-31")
+		Expect(eh.DeleteEmployee(ctx, "2")).To(BeNil())
+		Expect(eh.DeleteEmployee(ctx, "4")).To(BeNil())
+
+		res, err := eh.GetEmployeeAsOf(ctx, "", "", "2", "2026-03-31", "")
+		Expect(err).To(BeNil())
+		Expect(ids(res.Employees)).To(Equal([]int{1, 3}))
+		Expect(res.LastEvalKeyID).To(Equal(3))
+		res, err = eh.GetEmployeeAsOf(ctx, "", "3", "2", "2026-03-31", "")
+		Expect(err).To(BeNil())
+		Expect(ids(res.Employees)).To(Equal([]int{5}))
+		Expect(res.LastEvalKeyID).To(BeZero())
+
+		res, err = eh.GetEmployeeAsOf(ctx, "", "", "10", "2026-03-30", "")
+		Expect(err).To(BeNil())
+		Expect(ids(res.Employees)).To(Equal([]int{1, 2, 3, 4, 5}))
+	})
+
+	It("includes employees stored before versions were kept", func() {
+		Expect(db.SetItem(ctx, 7, models.Employee{ID: 7, Name: "Grace", Position: "Engineer", Salary: models.MoneyOf(90)})).To(BeNil())
+		// Restart, the records are migrated on first use
+		eh = employee.NewEmployeeWithDB(db)
+		eh.SetClock(func() time.Time { return now })
+
+		emp, err := getAsOf("7", "2020-01-01", "")
+		Expect(err).To(BeNil())
+		Expect(emp.Name).To(Equal("Grace"))
+
+		on("2026-03-20")
+		Expect(eh.UpdateEmployee(ctx, "7", models.EmployeeUpdateRequest{Position: "Lead"})).To(BeNil())
+		emp, err = getAsOf("7", "2026-03-19", "")
+		Expect(err).To(BeNil())
+		Expect(emp.Position).To(Equal("Engineer"))
+		emp, err = getAsOf("7", "2026-03-20", "")
+		Expect(err).To(BeNil())
+		Expect(emp.Position).To(Equal("Lead"))
+	})
+
+	It("undoes a change whose version cannot be recorded", func() {
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 2, Name: "Alan", Position: "Engineer", Salary: models.MoneyOf(100), ManagerID: 1})).To(BeNil())
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 3, Name: "Grace", Position: "Engineer", Salary: models.MoneyOf(100)})).To(BeNil())
+		Expect(eh.DeleteEmployee(ctx, "3")).To(BeNil())
+		trail := audit.NewTrail()
+		eh.SetAuditTrail(trail)
+		var d simpledb.Database[int, models.EmployeeVersions]
+		versions := d.Init()
+		eh.SetVersionDB(versions)
+		Expect(versions.Close()).To(Succeed())
+
+		failed := employee.GetEmpError(employee.ErrorRecordingVersion)
+		Expect(eh.CreateEmployee(ctx, models.Employee{ID: 4, Name: "Barbara", Position: "Engineer", Salary: models.MoneyOf(100)})).To(Equal(failed))
+		Expect(eh.UpdateEmployee(ctx, "2", models.EmployeeUpdateRequest{Position: "Lead", Salary: models.MoneyOf(120)})).To(Equal(failed))
+		Expect(eh.DeleteEmployee(ctx, "2")).To(Equal(failed))
+		Expect(eh.RestoreEmployee(ctx, "3")).To(Equal(failed))
+		_, err := eh.MoveReports(ctx, "1", 0)
+		Expect(err).To(Equal(failed))
+
+		res, err := eh.GetEmployee(ctx, "", "", "")
+		Expect(err).To(BeNil())
+		Expect(res.Employees).To(Equal([]models.Employee{
+			{ID: 1, Name: "Ada", Position: "Engineer", Salary: models.MoneyOf(100), Currency: "USD", Status: models.StatusActive},
+			{ID: 2, Name: "Alan", Position: "Engineer", Salary: models.MoneyOf(100), Currency: "USD", Status: models.StatusActive, ManagerID: 1},
+		}))
+		history, err := eh.GetSalaryHistory(ctx, "2")
+		Expect(err).To(BeNil())
+		Expect(history.Changes).To(HaveLen(1))
+		for _, id := range []string{"1", "2", "3", "4"} {
+			entries, err := trail.List(ctx, id)
+			Expect(err).To(BeNil())
+			Expect(entries).To(BeEmpty())
+		}
+	})
+
+	It("rejects a malformed known_at", func() {
+		_, err := getAsOf("1", "", "yesterday")
+		Expect(err).To(Equal(employee.GetEmpError(employee.InvalidKnownAt)))
+	})
+})
@@ -0,0 +1,255 @@
+package employee
+
+import (
+	"context"
+	"employee/models"
+	"employee/pkg/logger"
+	"employee/service/simpledb"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// scanPageSize is the number of records read at a time when scanning a database.
+	scanPageSize = 256
+	// beginningOfTime is the valid-from date of employees stored before versions were kept.
+	beginningOfTime = "0001-01-01"
+)
+
+// employeeFields maps the JSON name of each employee field to its index.
+var employeeFields = func() map[string]int {
+	fields := map[string]int{}
+	t := reflect.TypeOf(models.Employee{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		fields[name] = i
+	}
+	return fields
+}()
+
+// SetVersionDB keeps the employee versions in db instead of a new in-memory database.
+func (eh *Employee) SetVersionDB(db *simpledb.Database[int, models.EmployeeVersions]) {
+	eh.versions = db
+}
+
+// newVersion returns a version of emp recorded now.
+func (eh *Employee) newVersion(action string, emp models.Employee, validFrom string, fields ...string) models.EmployeeVersion {
+	return models.EmployeeVersion{
+		Action:     action,
+		Fields:     fields,
+		Employee:   emp,
+		ValidFrom:  validFrom,
+		RecordedAt: eh.now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+// migrateVersions gives every employee stored before versions were kept a version
+// valid since the beginning of time, followed by its salary history. It runs once,
+// before the first version is read or written.
+func (eh *Employee) migrateVersions(ctx context.Context) error {
+	eh.versionsMutex.Lock()
+	defer eh.versionsMutex.Unlock()
+	if eh.versionsMigrated {
+		return nil
+	}
+
+	var last, migrated int
+	for {
+		emps, next, err := eh.db.GetItems(ctx, last, scanPageSize)
+		if err != nil {
+			return err
+		}
+		for _, emp := range emps {
+			if _, ok := eh.versions.GetItem(ctx, emp.ID); ok {
+				continue
+			}
+			history := models.EmployeeVersions{Versions: []models.EmployeeVersion{
+				{Action: models.AuditCreate, Employee: emp, ValidFrom: beginningOfTime},
+			}}
+			salaries, _ := eh.salaries.GetItem(ctx, emp.ID)
+			for _, ch := range salaries.Changes {
+				history.Versions = append(history.Versions, models.EmployeeVersion{
+					Action: models.AuditUpdate, Fields: []string{"salary", "currency"},
+					Employee:  models.Employee{ID: emp.ID, Salary: ch.Salary, Currency: ch.Currency},
+					ValidFrom: ch.EffectiveDate, RecordedAt: ch.RecordedAt,
+				})
+			}
+			if err := eh.versions.SetItem(ctx, emp.ID, history); err != nil {
+				return err
+			}
+			migrated++
+		}
+		if next == 0 {
+			break
+		}
+		last = next
+	}
+	if migrated > 0 {
+		logger.Ctx(ctx).Info().Int("employees", migrated).Msg("Recorded versions of employees stored before versions were kept")
+	}
+	eh.versionsMigrated = true
+	return nil
+}
+
+// addVersions inserts versions into the history of id, each after every version valid
+// on or before the same date, in the current incarnation of id. It returns the history
+// before, for restoreVersions to undo the insert.
+func (eh *Employee) addVersions(ctx context.Context, id int, versions ...models.EmployeeVersion) (models.EmployeeVersions, error) {
+	history, present := eh.versions.GetItem(ctx, id)
+	// Copy, the stored slice must not be modified in place
+	all := slices.Clone(history.Versions)
+	for _, v := range versions {
+		v.Incarnation = history.Purges
+		i := len(all)
+		for i > 0 && all[i-1].ValidFrom > v.ValidFrom {
+			i--
+		}
+		all = slices.Insert(all, i, v)
+	}
+	if present {
+		return history, eh.versions.UpdateItem(ctx, id, models.EmployeeVersions{Versions: all, Purges: history.Purges})
+	}
+	return history, eh.versions.SetItem(ctx, id, models.EmployeeVersions{Versions: all})
+}
+
+// restoreVersions puts back the history of id returned by addVersions, when the
+// mutation versioned fails.
+func (eh *Employee) restoreVersions(ctx context.Context, id int, history models.EmployeeVersions) {
+	undo := func() error { return eh.versions.UpdateItem(ctx, id, history) }
+	if len(history.Versions) == 0 {
+		undo = func() error { return eh.versions.DeleteItem(ctx, id) }
+	}
+	if err := undo(); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Int("empId", id).Msg("Error reverting employee versions")
+	}
+}
+
+// reconstruct returns the employee record valid on date as it was known at knownAt,
+// or false if the employee did not exist then. The incarnations of an ID purged and
+// reused are replayed apart, the latest existing on date wins.
+func reconstruct(versions []models.EmployeeVersion, date string, knownAt time.Time) (models.Employee, bool) {
+	latest := 0
+	for _, v := range versions {
+		latest = max(latest, v.Incarnation)
+	}
+	for n := latest; n >= 0; n-- {
+		if emp, ok := replay(versions, n, date, knownAt); ok {
+			return emp, true
+		}
+	}
+	return models.Employee{}, false
+}
+
+// replay returns the employee record of incarnation n valid on date as it was known
+// at knownAt, or false if it did not exist then.
+func replay(versions []models.EmployeeVersion, n int, date string, knownAt time.Time) (models.Employee, bool) {
+	var emp models.Employee
+	var exists bool
+	// base is the recording time of the create or delete in effect. Updates recorded
+	// before it were made to an earlier incarnation of the record. A restore brings
+	// back the incarnation deleted, and with it the base before the delete.
+	var base, deletedBase time.Time
+	for _, v := range versions {
+		if v.ValidFrom > date {
+			break
+		}
+		if v.Incarnation != n || v.RecordedAt.After(knownAt) {
+			continue
+		}
+		switch v.Action {
+		case models.AuditCreate:
+			emp, exists, base = v.Employee, true, v.RecordedAt
+		case models.AuditDelete:
+			emp, exists, base, deletedBase = models.Employee{}, false, v.RecordedAt, base
+		case models.AuditRestore:
+			emp, exists, base = v.Employee, true, deletedBase
+		case models.AuditUpdate:
+			if exists && !v.RecordedAt.Before(base) {
+				dst, src := reflect.ValueOf(&emp).Elem(), reflect.ValueOf(v.Employee)
+				for _, field := range v.Fields {
+					if idx, ok := employeeFields[field]; ok {
+						dst.Field(idx).Set(src.Field(idx))
+					}
+				}
+			}
+		}
+	}
+	return emp, exists
+}
+
+// parseKnownAt returns the latest recording time included for knownAt, an RFC 3339
+// timestamp or a YYYY-MM-DD date standing for the end of that day.
+func parseKnownAt(knownAt string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, knownAt); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse(time.DateOnly, knownAt); err == nil {
+		return t.Add(24*time.Hour - time.Nanosecond), true
+	}
+	return time.Time{}, false
+}
+
+// getVersions serves GetEmployeeAsOf from the versions, returning the employees as
+// they were on date according to what was recorded up to knownAt. Deleted employees
+// are included for the dates they existed.
+func (eh *Employee) getVersions(ctx context.Context, empID, LastEvalKeyID string, numRecords int, date string, knownAt time.Time) (res models.GetEmployeeResponse, err error) {
+	if err := eh.migrateVersions(ctx); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Error recording employee versions")
+		return res, GetEmpError(ErrorGettingEmp)
+	}
+
+	if empID != "" {
+		empIDInt, err := strconv.Atoi(empID)
+		if err != nil {
+			logger.Ctx(ctx).Error().Err(err).Str("empId", empID).Msg("Invalid employee ID")
+			return res, GetEmpError(InvalidID)
+		}
+		history, _ := eh.versions.GetItem(ctx, empIDInt)
+		emp, ok := reconstruct(history.Versions, date, knownAt)
+		if !ok {
+			logger.Ctx(ctx).Error().Str("empId", empID).Str("asOf", date).Msg("Employee not found")
+			return res, GetEmpError(InvalidID)
+		}
+		res.Employees = []models.Employee{emp}
+		return res, nil
+	}
+
+	var last int
+	if LastEvalKeyID != "" {
+		if last, err = strconv.Atoi(LastEvalKeyID); err != nil {
+			logger.Ctx(ctx).Error().Err(err).Str("lastEvalKeyId", LastEvalKeyID).Msg("Invalid lastEvalKeyId")
+			return res, GetEmpError(InvalidLastEvalKeyID)
+		}
+	}
+	res.Employees = []models.Employee{}
+	// Employees that did not exist on date are skipped, so read pages until this
+	// one is full or every history has been read
+	for len(res.Employees) < numRecords {
+		histories, next, err := eh.versions.GetItems(ctx, last, numRecords)
+		if err != nil {
+			logger.Ctx(ctx).Error().Err(err).Str("lastEvalKeyId", LastEvalKeyID).Msg("Error getting employee versions")
+			return res, GetEmpError(InvalidLastEvalKeyID)
+		}
+		for _, history := range histories {
+			if len(history.Versions) == 0 {
+				continue
+			}
+			last = history.Versions[0].Employee.ID
+			if emp, ok := reconstruct(history.Versions, date, knownAt); ok {
+				res.Employees = append(res.Employees, emp)
+				if len(res.Employees) == numRecords {
+					res.LastEvalKeyID = last
+					return res, nil
+				}
+			}
+		}
+		if next == 0 {
+			break
+		}
+		last = next
+	}
+	return res, nil
+}
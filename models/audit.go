@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+	// AuditRestore undoes a delete, AuditPurge removes a deleted employee for good.
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+)
+
+// FieldChange is the JSON encoded value of one field before and after a mutation.
+// Old is absent on create and New on delete.
+type FieldChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old,omitempty"`
+	New   json.RawMessage `json:"new,omitempty"`
+}
+
+// AuditEntry records one employee mutation. Hash covers every other field and the
+// hash of the previous entry, chaining the entries so that any change is detected.
+type AuditEntry struct {
+	Seq        uint64        `json:"seq"`
+	EmployeeID int           `json:"employee_id"`
+	Action     string        `json:"action"`
+	Actor      string        `json:"actor"`
+	RequestID  string        `json:"request_id,omitempty"`
+	Timestamp  time.Time     `json:"timestamp"`
+	Changes    []FieldChange `json:"changes"`
+	PrevHash   string        `json:"prev_hash"`
+	Hash       string        `json:"hash"`
+}
+
+type GetAuditResponse struct {
+	Entries []AuditEntry `json:"entries"`
+}
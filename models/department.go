@@ -0,0 +1,30 @@
+package models
+
+// Department groups employees. Employees refer to it by ID in their department field.
+type Department struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type DepartmentUpdateRequest struct {
+	Name string `json:"name"`
+}
+
+type GetDepartmentsResponse struct {
+	Departments   []Department `json:"departments"`
+	LastEvalKeyID string       `json:"last_eval_id,omitempty"`
+}
+
+// DepartmentRollup is the headcount and payroll of a department, from the salaries
+// stored with its employees converted into Currency.
+type DepartmentRollup struct {
+	Department    string `json:"department"`
+	Headcount     int    `json:"headcount"`
+	TotalSalary   Money  `json:"total_salary,omitempty"`
+	AverageSalary Money  `json:"average_salary,omitempty"`
+	Currency      string `json:"currency"`
+}
+
+type GetDepartmentRollupsResponse struct {
+	Rollups []DepartmentRollup `json:"rollups"`
+}
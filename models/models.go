@@ -1,10 +1,34 @@
 package models
 
+import "time"
+
+const (
+	StatusActive     = "active"
+	StatusOnLeave    = "on_leave"
+	StatusTerminated = "terminated"
+)
+
+// Employee is an employee record. Dates are in YYYY-MM-DD form and a zero ManagerID
+// means the employee has no manager.
+//
+// Salary is in Currency, an ISO 4217 code. Employees stored before currencies were
+// recorded are paid in the default currency.
+//
+// A deleted employee is kept with DeletedAt set until it is purged, hidden from
+// every read but those of past versions.
 type Employee struct {
-	ID       int     `json:"id"`
-	Name     string  `json:"name,omitempty"`
-	Position string  `json:"position,omitempty"`
-	Salary   float64 `json:"salary,omitempty"`
+	ID              int        `json:"id"`
+	Name            string     `json:"name,omitempty"`
+	Position        string     `json:"position,omitempty"`
+	Salary          Money      `json:"salary,omitempty"`
+	Currency        string     `json:"currency,omitempty"`
+	Email           string     `json:"email,omitempty"`
+	Department      string     `json:"department,omitempty"`
+	ManagerID       int        `json:"manager_id,omitempty"`
+	HireDate        string     `json:"hire_date,omitempty"`
+	TerminationDate string     `json:"termination_date,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
 }
 
 type GetEmployeeResponse struct {
@@ -16,7 +40,33 @@ type APIResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
+// EmployeeUpdateRequest changes the fields it sets and leaves the others as they are.
 type EmployeeUpdateRequest struct {
-	Position string  `json:"position,omitempty"`
-	Salary   float64 `json:"salary,omitempty"`
+	Position        string `json:"position,omitempty"`
+	Salary          Money  `json:"salary,omitempty"`
+	Email           string `json:"email,omitempty"`
+	Department      string `json:"department,omitempty"`
+	HireDate        string `json:"hire_date,omitempty"`
+	TerminationDate string `json:"termination_date,omitempty"`
+	Status          string `json:"status,omitempty"`
+	// ManagerID is a pointer so that 0 can remove the manager.
+	ManagerID *int `json:"manager_id,omitempty"`
+	// EffectiveDate is the YYYY-MM-DD date the salary takes effect, today when empty.
+	EffectiveDate string `json:"effective_date,omitempty"`
+	// Currency is the ISO 4217 code of Salary, the current currency when empty. It
+	// can only be set together with Salary.
+	Currency string `json:"currency,omitempty"`
+	// Reason is recorded in the salary history.
+	Reason string `json:"reason,omitempty"`
+	// SalaryJustification accepts a salary outside the band of the position.
+	SalaryJustification string `json:"salary_justification,omitempty"`
+}
+
+// MoveReportsRequest names the manager the reports of an employee are moved to.
+type MoveReportsRequest struct {
+	ManagerID int `json:"manager_id"`
+}
+
+type MoveReportsResponse struct {
+	Moved []int `json:"moved"`
 }
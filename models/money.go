@@ -0,0 +1,145 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MoneyDecimals is the number of decimal places a Money keeps, enough for the minor
+// unit of every currency.
+const MoneyDecimals = 4
+
+// moneyScale is the number of Money units in one unit of currency.
+const moneyScale = 10000
+
+// moneyPattern matches the decimal strings accepted as money.
+var moneyPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// Money is an exact decimal amount of a currency, counted in ten-thousandths so that
+// sums and comparisons never drift. It is encoded in JSON as a decimal string and
+// decoded from a decimal string or a JSON number, which is read digit by digit rather
+// than through a float64.
+type Money int64
+
+// MoneyOf returns units whole units of a currency.
+func MoneyOf(units int64) Money {
+	return Money(units * moneyScale)
+}
+
+// ParseMoney parses a decimal such as "1234.56". It returns an error, without s, if s
+// is not a decimal, has more than MoneyDecimals decimals or is too large.
+func ParseMoney(s string) (Money, error) {
+	if !moneyPattern.MatchString(s) {
+		return 0, errors.New("invalid amount, must be a decimal")
+	}
+	r, _ := new(big.Rat).SetString(s)
+	return moneyFromExactRat(r)
+}
+
+// MustParseMoney is like ParseMoney but panics if s cannot be parsed.
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MoneyFromRat returns r rounded half away from zero to MoneyDecimals decimals. It
+// returns false if r is too large.
+func MoneyFromRat(r *big.Rat) (Money, bool) {
+	n := new(big.Int).Mul(r.Num(), big.NewInt(moneyScale))
+	q, rem := new(big.Int).QuoRem(n, r.Denom(), new(big.Int))
+	if rem.Abs(rem).Lsh(rem, 1).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(n.Sign())))
+	}
+	if !q.IsInt64() {
+		return 0, false
+	}
+	return Money(q.Int64()), true
+}
+
+// moneyFromExactRat converts r, failing unless it fits in a Money exactly. The
+// errors leave the amount out, they end up in logs.
+func moneyFromExactRat(r *big.Rat) (Money, error) {
+	scaled := new(big.Rat).Mul(r, big.NewRat(moneyScale, 1))
+	if !scaled.IsInt() {
+		return 0, fmt.Errorf("invalid amount, cannot have more than %d decimals", MoneyDecimals)
+	}
+	if !scaled.Num().IsInt64() {
+		return 0, errors.New("invalid amount, too large")
+	}
+	return Money(scaled.Num().Int64()), nil
+}
+
+// Rat returns the exact value of m.
+func (m Money) Rat() *big.Rat {
+	return big.NewRat(int64(m), moneyScale)
+}
+
+// Decimals returns the number of decimals needed to write m.
+func (m Money) Decimals() int {
+	decimals := MoneyDecimals
+	for v := int64(m); decimals > 0 && v%10 == 0; v /= 10 {
+		decimals--
+	}
+	return decimals
+}
+
+// String returns m as a decimal with no trailing zeros, such as "1234.5".
+func (m Money) String() string {
+	v := int64(m)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+	}
+	// The absolute value of the smallest Money does not fit in an int64
+	abs := new(big.Int).Abs(big.NewInt(v)).Uint64()
+	units, fraction := abs/moneyScale, abs%moneyScale
+	s := sign + strconv.FormatUint(units, 10)
+	if fraction != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%0*d", MoneyDecimals, fraction), "0")
+	}
+	return s
+}
+
+// MarshalJSON encodes m as a decimal string.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON decodes a decimal string or a JSON number, exactly.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		parsed, err := ParseMoney(s)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+	// Numbers may have an exponent, which big.Rat reads exactly
+	r, ok := new(big.Rat).SetString(string(data))
+	if !ok {
+		return errors.New("invalid amount, must be a number or a decimal string")
+	}
+	parsed, err := moneyFromExactRat(r)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
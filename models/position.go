@@ -0,0 +1,30 @@
+package models
+
+// Position is a catalogued job title with its salary band, in Currency. Employees
+// refer to it by title in their position field.
+type Position struct {
+	Title     string `json:"title"`
+	MinSalary Money  `json:"min_salary,omitempty"`
+	MidSalary Money  `json:"mid_salary,omitempty"`
+	MaxSalary Money  `json:"max_salary,omitempty"`
+	Currency  string `json:"currency,omitempty"`
+}
+
+type PositionUpdateRequest struct {
+	MinSalary Money  `json:"min_salary"`
+	MidSalary Money  `json:"mid_salary"`
+	MaxSalary Money  `json:"max_salary"`
+	Currency  string `json:"currency,omitempty"`
+}
+
+type GetPositionsResponse struct {
+	Positions     []Position `json:"positions"`
+	LastEvalKeyID string     `json:"last_eval_id,omitempty"`
+}
+
+// CreateEmployeeRequest is an employee to create. A salary outside the band of its
+// position requires SalaryJustification.
+type CreateEmployeeRequest struct {
+	Employee
+	SalaryJustification string `json:"salary_justification,omitempty"`
+}
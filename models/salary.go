@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// SalaryChange is a salary that takes effect on EffectiveDate, a YYYY-MM-DD date.
+// It stays in effect until the next change.
+type SalaryChange struct {
+	Salary Money `json:"salary"`
+	// Currency is empty for changes recorded before currencies were, which are in the
+	// default currency.
+	Currency      string `json:"currency,omitempty"`
+	EffectiveDate string `json:"effective_date"`
+	Reason        string `json:"reason,omitempty"`
+	// OverrideJustification is set when the salary is outside the band of the position.
+	OverrideJustification string    `json:"override_justification,omitempty"`
+	RecordedBy            string    `json:"recorded_by"`
+	RecordedAt            time.Time `json:"recorded_at"`
+}
+
+// SalaryHistory is the stored compensation of one employee, ordered by effective date.
+type SalaryHistory struct {
+	Changes []SalaryChange `json:"changes"`
+}
+
+type GetSalaryHistoryResponse struct {
+	EmployeeID int            `json:"employee_id"`
+	Changes    []SalaryChange `json:"changes"`
+}
@@ -0,0 +1,35 @@
+package models
+
+// EmployeeStatsRequest selects the employees to summarize and how to group them.
+// Empty filters match every employee.
+type EmployeeStatsRequest struct {
+	// GroupBy is "position", "department" or empty for a single group.
+	GroupBy    string
+	Position   string
+	Department string
+	Status     string
+	// Currency is the ISO 4217 code the statistics are in, the default currency when
+	// empty.
+	Currency string
+	// Percentiles lists the percentiles to compute, decimals between 0 and 100.
+	Percentiles []string
+}
+
+// SalaryStats summarizes the salaries of a group of employees, converted into
+// Currency. Percentiles are keyed by the percentile, as in "p90", and interpolated
+// between the closest salaries like Median.
+type SalaryStats struct {
+	// Group is the position or department of the group, empty when not grouped.
+	Group       string           `json:"group"`
+	Count       int              `json:"count"`
+	Sum         Money            `json:"sum,omitempty"`
+	Mean        Money            `json:"mean,omitempty"`
+	Median      Money            `json:"median,omitempty"`
+	Percentiles map[string]Money `json:"percentiles,omitempty"`
+	Currency    string           `json:"currency"`
+}
+
+type GetEmployeeStatsResponse struct {
+	GroupBy string        `json:"group_by,omitempty"`
+	Stats   []SalaryStats `json:"stats"`
+}
@@ -0,0 +1,60 @@
+package models_test
+
+import (
+	"employee/models"
+	"encoding/json"
+	"math/big"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Money", func() {
+	It("should parse and print decimals exactly", func() {
+		Expect(models.MustParseMoney("1234.56")).To(Equal(models.Money(12345600)))
+		Expect(models.MustParseMoney("0.1").String()).To(Equal("0.1"))
+		Expect(models.MustParseMoney("-2.0050").String()).To(Equal("-2.005"))
+		Expect(models.MoneyOf(100).String()).To(Equal("100"))
+		Expect(models.MustParseMoney("1234.56").Decimals()).To(Equal(2))
+		Expect(models.MoneyOf(7).Decimals()).To(Equal(0))
+
+		for _, s := range []string{"", "1.", ".5", "1e3", "1/3", "0.00001", "1000000000000000"} {
+			_, err := models.ParseMoney(s)
+			Expect(err).NotTo(BeNil(), s)
+		}
+	})
+
+	It("should add up without drift", func() {
+		var sum models.Money
+		for i := 0; i < 10; i++ {
+			sum += models.MustParseMoney("0.1")
+		}
+		Expect(sum).To(Equal(models.MoneyOf(1)))
+	})
+
+	It("should encode as a string and decode strings and numbers", func() {
+		data, err := json.Marshal(models.Employee{ID: 1, Salary: models.MustParseMoney("1234.56")})
+		Expect(err).To(BeNil())
+		Expect(string(data)).To(Equal(`{"id":1,"salary":"1234.56"}`))
+
+		for _, body := range []string{`{"salary":"1234.56"}`, `{"salary":1234.56}`, `{"salary":1.23456e3}`} {
+			var emp models.Employee
+			Expect(json.Unmarshal([]byte(body), &emp)).To(Succeed())
+			Expect(emp.Salary).To(Equal(models.MustParseMoney("1234.56")), body)
+		}
+
+		var emp models.Employee
+		Expect(json.Unmarshal([]byte(`{"salary":0.00001}`), &emp)).NotTo(Succeed())
+		Expect(json.Unmarshal([]byte(`{"salary":"12,5"}`), &emp)).NotTo(Succeed())
+		Expect(json.Unmarshal([]byte(`{"salary":true}`), &emp)).NotTo(Succeed())
+	})
+
+	It("should round rationals half away from zero", func() {
+		m, ok := models.MoneyFromRat(big.NewRat(1, 3))
+		Expect(ok).To(BeTrue())
+		Expect(m.String()).To(Equal("0.3333"))
+		m, _ = models.MoneyFromRat(big.NewRat(-1, 20000))
+		Expect(m.String()).To(Equal("-0.0001"))
+		Expect(models.MustParseMoney("2.5").Rat()).To(Equal(big.NewRat(5, 2)))
+	})
+})
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// EmployeeVersion is one change to an employee record. It takes effect on ValidFrom,
+// a YYYY-MM-DD date, and is known from RecordedAt on.
+//
+// Action is one of the audit actions. A create carries the whole record, an update
+// only the Fields it sets and a delete none.
+type EmployeeVersion struct {
+	Action     string    `json:"action"`
+	Fields     []string  `json:"fields,omitempty"`
+	Employee   Employee  `json:"employee"`
+	ValidFrom  string    `json:"valid_from"`
+	RecordedAt time.Time `json:"recorded_at"`
+	// Incarnation is the number of purges of the ID before the version, so that the
+	// history of a reused ID starts anew.
+	Incarnation int `json:"incarnation,omitempty"`
+}
+
+// EmployeeVersions is the stored history of one employee, ordered by ValidFrom and,
+// for the same date, by RecordedAt.
+type EmployeeVersions struct {
+	Versions []EmployeeVersion `json:"versions"`
+	// Purges is the number of times the ID was purged, the incarnation of the next
+	// versions.
+	Purges int `json:"purges,omitempty"`
+}
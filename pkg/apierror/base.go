@@ -4,6 +4,7 @@ type APIError struct {
 	HttpStatusCode int    `json:"-"`
 	ErrCode        int    `json:"code,omitempty"`
 	ErrorMessage   string `json:"error_msg,omitempty"`
+	RequestID      string `json:"request_id,omitempty"`
 }
 
 func (e *APIError) Error() string {
@@ -0,0 +1,18 @@
+package apierror
+
+import (
+	"employee/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Abort stops the request with err as the response body, stamped with the request
+// ID. The error is also recorded on the context so middlewares can see which error
+// code was returned.
+func Abort(c *gin.Context, err *APIError) {
+	// The catalog errors are shared, so the request ID goes on a copy
+	body := *err
+	body.RequestID = logger.RequestID(c.Request.Context())
+	_ = c.Error(err)
+	c.AbortWithStatusJSON(body.HttpStatusCode, &body)
+}
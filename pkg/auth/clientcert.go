@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+)
+
+// ClientIdentity is the identity proven by a verified TLS client certificate.
+type ClientIdentity struct {
+	// Subject is the distinguished name of the certificate.
+	Subject    string
+	CommonName string
+	DNSNames   []string
+	// Fingerprint is the hex SHA-256 of the DER certificate.
+	Fingerprint string
+}
+
+// CertSubjectPrefix prefixes the principal subject of callers authenticated by a
+// client certificate, so that policy bindings cannot collide with token subjects.
+const CertSubjectPrefix = "cert:"
+
+// ClientIdentityFromTLS returns the identity of the leaf certificate of a verified
+// client chain, or nil when the client did not present a verified certificate.
+func ClientIdentityFromTLS(state *tls.ConnectionState) *ClientIdentity {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	leaf := state.VerifiedChains[0][0]
+	sum := sha256.Sum256(leaf.Raw)
+	return &ClientIdentity{
+		Subject:     leaf.Subject.String(),
+		CommonName:  leaf.Subject.CommonName,
+		DNSNames:    leaf.DNSNames,
+		Fingerprint: hex.EncodeToString(sum[:]),
+	}
+}
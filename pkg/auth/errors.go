@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"employee/pkg/apierror"
+	"net/http"
+)
+
+func GetAuthError(c AuthError) *apierror.APIError {
+	return AuthErrors[c]
+}
+
+type AuthError int
+
+const (
+	MissingToken AuthError = iota + 100100
+	MalformedToken
+	UnsupportedAlgorithm
+	UnknownSigningKey
+	InvalidSignature
+	TokenExpired
+	TokenNotYetValid
+	InvalidAudience
+	InvalidIssuer
+	MissingSubject
+)
+
+var AuthErrors = map[AuthError]*apierror.APIError{
+	MissingToken:         {HttpStatusCode: http.StatusUnauthorized, ErrCode: int(MissingToken), ErrorMessage: "Missing bearer token"},
+	MalformedToken:       {HttpStatusCode: http.StatusUnauthorized, ErrCode: int(MalformedToken), ErrorMessage: "Malformed bearer token"},
+	UnsupportedAlgorithm: {HttpStatusCode: http.StatusUnauthorized, ErrCode: int(UnsupportedAlgorithm), ErrorMessage: "Unsupported token signing algorithm"},
+	UnknownSigningKey:    {HttpStatusCode: http.StatusUnauthorized, ErrCode: int(UnknownSigningKey), ErrorMessage: "Unknown token signing key"},
+	InvalidSignature:     {HttpStatusCode: http.StatusUnauthorized, ErrCode: int(InvalidSignature), ErrorMessage: "Invalid token signature"},
+	TokenExpired:         {HttpStatusCode: http.StatusUnauthorized, ErrCode: int(TokenExpired), ErrorMessage: "Token has expired"},
+	TokenNotYetValid:     {HttpStatusCode: http.StatusUnauthorized, ErrCode: int(TokenNotYetValid), ErrorMessage: "Token is not valid yet"},
+	InvalidAudience:      {HttpStatusCode: http.StatusUnauthorized, ErrCode: int(InvalidAudience), ErrorMessage: "Token audience is not accepted"},
+	InvalidIssuer:        {HttpStatusCode: http.StatusUnauthorized, ErrCode: int(InvalidIssuer), ErrorMessage: "Token issuer is not accepted"},
+	MissingSubject:       {HttpStatusCode: http.StatusUnauthorized, ErrCode: int(MissingSubject), ErrorMessage: "Token has no subject"},
+}
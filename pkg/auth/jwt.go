@@ -0,0 +1,249 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"employee/pkg/logger"
+)
+
+// Config describes which keys and claims a Verifier accepts.
+// At least one of JWKSFile, HMACSecret or RSAPublicKeyFile must be set.
+type Config struct {
+	JWKSFile         string
+	HMACSecret       []byte
+	RSAPublicKeyFile string
+	Issuer           string
+	Audience         string
+	Leeway           time.Duration
+}
+
+// Verifier validates HS256 and RS256 signed JWTs.
+type Verifier struct {
+	keys     []*verificationKey
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// NumericDate is a JWT timestamp in seconds since the epoch.
+type NumericDate int64
+
+func (d *NumericDate) UnmarshalJSON(b []byte) error {
+	f, err := strconv.ParseFloat(string(b), 64)
+	if err != nil {
+		return err
+	}
+	*d = NumericDate(f)
+	return nil
+}
+
+// StringList accepts both the single string and the array form of a claim such as aud.
+type StringList []string
+
+func (a *StringList) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*a = StringList{single}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(b, &multi); err != nil {
+		return err
+	}
+	*a = multi
+	return nil
+}
+
+func (a StringList) contains(aud string) bool {
+	for _, v := range a {
+		if v == aud {
+			return true
+		}
+	}
+	return false
+}
+
+// Claims holds the registered claims the service relies on.
+type Claims struct {
+	Subject   string      `json:"sub"`
+	Issuer    string      `json:"iss"`
+	Audience  StringList  `json:"aud"`
+	ExpiresAt NumericDate `json:"exp"`
+	NotBefore NumericDate `json:"nbf"`
+	IssuedAt  NumericDate `json:"iat"`
+	Roles     StringList  `json:"roles"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// NewVerifier creates a Verifier from the given configuration.
+//
+// It returns an error if no key source is configured or a key source cannot be loaded.
+func NewVerifier(cfg Config) (*Verifier, error) {
+	v := &Verifier{
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		leeway:   cfg.Leeway,
+		now:      time.Now,
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKSFile(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = append(v.keys, keys...)
+	}
+	if len(cfg.HMACSecret) > 0 {
+		v.keys = append(v.keys, &verificationKey{alg: AlgHS256, hmacKey: cfg.HMACSecret})
+	}
+	if cfg.RSAPublicKeyFile != "" {
+		pub, err := LoadRSAPublicKeyFile(cfg.RSAPublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = append(v.keys, &verificationKey{alg: AlgRS256, rsaKey: pub})
+	}
+	if len(v.keys) == 0 {
+		return nil, errors.New("no JWT verification key configured")
+	}
+	return v, nil
+}
+
+// SetClock overrides the time source used for expiry checks.
+func (v *Verifier) SetClock(now func() time.Time) {
+	v.now = now
+}
+
+// Verify checks the signature and the registered claims of a compact serialized JWT.
+//
+// It returns the authenticated principal, or an *apierror.APIError describing why the token was rejected.
+func (v *Verifier) Verify(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, GetAuthError(MalformedToken)
+	}
+
+	var hdr header
+	if err := decodeSegment(parts[0], &hdr); err != nil {
+		logger.Log.Debug().Err(err).Msg("Invalid token header")
+		return nil, GetAuthError(MalformedToken)
+	}
+	if hdr.Alg != AlgHS256 && hdr.Alg != AlgRS256 {
+		logger.Log.Debug().Str("alg", hdr.Alg).Msg("Unsupported token algorithm")
+		return nil, GetAuthError(UnsupportedAlgorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, GetAuthError(MalformedToken)
+	}
+
+	candidates := v.candidateKeys(hdr)
+	if len(candidates) == 0 {
+		logger.Log.Debug().Str("alg", hdr.Alg).Str("kid", hdr.Kid).Msg("No matching verification key")
+		return nil, GetAuthError(UnknownSigningKey)
+	}
+	signingInput := parts[0] + "." + parts[1]
+	verified := false
+	for _, key := range candidates {
+		if key.verify(signingInput, signature) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, GetAuthError(InvalidSignature)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		logger.Log.Debug().Err(err).Msg("Invalid token claims")
+		return nil, GetAuthError(MalformedToken)
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return &Principal{Subject: claims.Subject, Method: MethodJWT, Roles: claims.Roles, Claims: claims}, nil
+}
+
+// candidateKeys returns the keys that may have signed a token with the given header.
+// A key is only eligible for the algorithm it was configured for, which rules out
+// algorithm confusion between RSA public keys and HMAC secrets.
+func (v *Verifier) candidateKeys(hdr header) []*verificationKey {
+	var byKid, others []*verificationKey
+	for _, key := range v.keys {
+		if key.alg != hdr.Alg {
+			continue
+		}
+		switch {
+		case hdr.Kid != "" && key.kid == hdr.Kid:
+			byKid = append(byKid, key)
+		case hdr.Kid == "" || key.kid == "":
+			others = append(others, key)
+		}
+	}
+	if len(byKid) > 0 {
+		return byKid
+	}
+	return others
+}
+
+func (v *Verifier) validateClaims(claims Claims) error {
+	now := v.now()
+	if claims.ExpiresAt == 0 {
+		logger.Log.Debug().Msg("Token has no expiry")
+		return GetAuthError(MalformedToken)
+	}
+	if now.After(time.Unix(int64(claims.ExpiresAt), 0).Add(v.leeway)) {
+		return GetAuthError(TokenExpired)
+	}
+	if claims.NotBefore != 0 && now.Before(time.Unix(int64(claims.NotBefore), 0).Add(-v.leeway)) {
+		return GetAuthError(TokenNotYetValid)
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return GetAuthError(InvalidIssuer)
+	}
+	if v.audience != "" && !claims.Audience.contains(v.audience) {
+		return GetAuthError(InvalidAudience)
+	}
+	if claims.Subject == "" {
+		return GetAuthError(MissingSubject)
+	}
+	return nil
+}
+
+func (k *verificationKey) verify(signingInput string, signature []byte) bool {
+	sum := sha256.Sum256([]byte(signingInput))
+	switch k.alg {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, k.hmacKey)
+		mac.Write([]byte(signingInput))
+		return hmac.Equal(signature, mac.Sum(nil))
+	case AlgRS256:
+		return rsa.VerifyPKCS1v15(k.rsaKey, crypto.SHA256, sum[:], signature) == nil
+	}
+	return false
+}
+
+func decodeSegment(seg string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// SubjectKey is the gin context key holding the authenticated subject.
+	SubjectKey = "auth.subject"
+	// PrincipalKey is the gin context key holding the authenticated *Principal.
+	PrincipalKey = "auth.principal"
+)
+
+const (
+	MethodJWT        = "jwt"
+	MethodAPIKey     = "api_key"
+	MethodClientCert = "client_cert"
+)
+
+// Scopes granted to API keys.
+const (
+	ScopeEmployeesRead  = "employees:read"
+	ScopeEmployeesWrite = "employees:write"
+	ScopeSalaryRead     = "employees:salary:read"
+	ScopeAPIKeysAdmin   = "apikeys:admin"
+	ScopeLoggingAdmin   = "logging:admin"
+	ScopeAuditRead      = "audit:read"
+)
+
+// KnownScopes lists every scope an API key may be granted.
+var KnownScopes = []string{ScopeEmployeesRead, ScopeEmployeesWrite, ScopeSalaryRead, ScopeAPIKeysAdmin, ScopeLoggingAdmin, ScopeAuditRead}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string
+	// Method is how the caller authenticated, MethodJWT, MethodAPIKey or MethodClientCert.
+	Method string
+	// Roles are held by JWT callers and evaluated against the authorization policy.
+	Roles []string
+	// Scopes are held by API key callers.
+	Scopes []string
+	Claims Claims
+	// Client is the verified TLS client certificate of the connection, if any.
+	Client *ClientIdentity
+}
+
+// SetPrincipal stores the principal and its subject in the gin context.
+func SetPrincipal(c *gin.Context, p *Principal) {
+	c.Set(PrincipalKey, p)
+	c.Set(SubjectKey, p.Subject)
+}
+
+// GetPrincipal returns the principal stored in the gin context, if any.
+func GetPrincipal(c *gin.Context) (*Principal, bool) {
+	v, ok := c.Get(PrincipalKey)
+	if !ok {
+		return nil, false
+	}
+	p, ok := v.(*Principal)
+	return p, ok
+}
+
+// GetSubject returns the authenticated subject, or an empty string for anonymous requests.
+func GetSubject(c *gin.Context) string {
+	return c.GetString(SubjectKey)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p, for the layers below the handlers.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,184 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"employee/pkg/auth"
+	"employee/pkg/testhelpers"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"sub": "user-1",
+		"iss": "https://issuer.test",
+		"aud": "employee-api",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func writeJWKS(dir string, pub *rsa.PublicKey, kid string) string {
+	set := map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	}
+	data, _ := json.Marshal(set)
+	path := filepath.Join(dir, "jwks.json")
+	Expect(os.WriteFile(path, data, 0o600)).To(Succeed())
+	return path
+}
+
+var _ = Describe("Verifier", func() {
+	var (
+		secret = []byte("0123456789abcdef0123456789abcdef")
+		rsaKey *rsa.PrivateKey
+		dir    string
+	)
+
+	BeforeEach(func() {
+		var err error
+		rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).To(BeNil())
+		dir = GinkgoT().TempDir()
+	})
+
+	Context("HS256 with a static secret", func() {
+		var v *auth.Verifier
+
+		BeforeEach(func() {
+			var err error
+			v, err = auth.NewVerifier(auth.Config{HMACSecret: secret, Issuer: "https://issuer.test", Audience: "employee-api"})
+			Expect(err).To(BeNil())
+		})
+
+		It("should accept a valid token and return its subject", func() {
+			p, err := v.Verify(testhelpers.SignHS256(secret, "", validClaims()))
+			Expect(err).To(BeNil())
+			Expect(p.Subject).To(Equal("user-1"))
+		})
+
+		It("should reject a token signed with another secret", func() {
+			_, err := v.Verify(testhelpers.SignHS256([]byte("other"), "", validClaims()))
+			Expect(err).To(Equal(auth.GetAuthError(auth.InvalidSignature)))
+		})
+
+		It("should reject an expired token", func() {
+			claims := validClaims()
+			claims["exp"] = time.Now().Add(-time.Minute).Unix()
+			_, err := v.Verify(testhelpers.SignHS256(secret, "", claims))
+			Expect(err).To(Equal(auth.GetAuthError(auth.TokenExpired)))
+		})
+
+		It("should reject a token without expiry", func() {
+			claims := validClaims()
+			delete(claims, "exp")
+			_, err := v.Verify(testhelpers.SignHS256(secret, "", claims))
+			Expect(err).To(Equal(auth.GetAuthError(auth.MalformedToken)))
+		})
+
+		It("should reject a token that is not valid yet", func() {
+			claims := validClaims()
+			claims["nbf"] = time.Now().Add(time.Hour).Unix()
+			_, err := v.Verify(testhelpers.SignHS256(secret, "", claims))
+			Expect(err).To(Equal(auth.GetAuthError(auth.TokenNotYetValid)))
+		})
+
+		It("should reject a token for another audience", func() {
+			claims := validClaims()
+			claims["aud"] = []string{"billing", "payroll"}
+			_, err := v.Verify(testhelpers.SignHS256(secret, "", claims))
+			Expect(err).To(Equal(auth.GetAuthError(auth.InvalidAudience)))
+		})
+
+		It("should accept an audience array containing the configured audience", func() {
+			claims := validClaims()
+			claims["aud"] = []string{"billing", "employee-api"}
+			_, err := v.Verify(testhelpers.SignHS256(secret, "", claims))
+			Expect(err).To(BeNil())
+		})
+
+		It("should reject a token from another issuer", func() {
+			claims := validClaims()
+			claims["iss"] = "https://evil.test"
+			_, err := v.Verify(testhelpers.SignHS256(secret, "", claims))
+			Expect(err).To(Equal(auth.GetAuthError(auth.InvalidIssuer)))
+		})
+
+		It("should reject an RS256 token when only a secret is configured", func() {
+			_, err := v.Verify(testhelpers.SignRS256(rsaKey, "", validClaims()))
+			Expect(err).To(Equal(auth.GetAuthError(auth.UnknownSigningKey)))
+		})
+
+		It("should reject a malformed token", func() {
+			_, err := v.Verify("not-a-token")
+			Expect(err).To(Equal(auth.GetAuthError(auth.MalformedToken)))
+		})
+	})
+
+	Context("RS256 with a JWKS file", func() {
+		It("should accept a token signed by the key with the matching kid", func() {
+			v, err := auth.NewVerifier(auth.Config{JWKSFile: writeJWKS(dir, &rsaKey.PublicKey, "key-1")})
+			Expect(err).To(BeNil())
+
+			p, err := v.Verify(testhelpers.SignRS256(rsaKey, "key-1", validClaims()))
+			Expect(err).To(BeNil())
+			Expect(p.Subject).To(Equal("user-1"))
+		})
+
+		It("should reject a token signed by an unknown key", func() {
+			v, err := auth.NewVerifier(auth.Config{JWKSFile: writeJWKS(dir, &rsaKey.PublicKey, "key-1")})
+			Expect(err).To(BeNil())
+			other, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).To(BeNil())
+
+			_, err = v.Verify(testhelpers.SignRS256(other, "key-1", validClaims()))
+			Expect(err).To(Equal(auth.GetAuthError(auth.InvalidSignature)))
+		})
+
+		It("should not accept the RSA public key as an HMAC secret", func() {
+			v, err := auth.NewVerifier(auth.Config{JWKSFile: writeJWKS(dir, &rsaKey.PublicKey, "key-1")})
+			Expect(err).To(BeNil())
+
+			forged := testhelpers.SignHS256(x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey), "key-1", validClaims())
+			_, err = v.Verify(forged)
+			Expect(err).To(Equal(auth.GetAuthError(auth.UnknownSigningKey)))
+		})
+	})
+
+	Context("RS256 with a static PEM key", func() {
+		It("should accept a token signed by the key", func() {
+			der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+			Expect(err).To(BeNil())
+			path := filepath.Join(dir, "key.pem")
+			Expect(os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600)).To(Succeed())
+
+			v, err := auth.NewVerifier(auth.Config{RSAPublicKeyFile: path})
+			Expect(err).To(BeNil())
+
+			_, err = v.Verify(testhelpers.SignRS256(rsaKey, "", validClaims()))
+			Expect(err).To(BeNil())
+		})
+	})
+
+	It("should fail to build without any key", func() {
+		_, err := auth.NewVerifier(auth.Config{})
+		Expect(err).NotTo(BeNil())
+	})
+})
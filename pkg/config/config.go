@@ -0,0 +1,220 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// Config is the effective configuration of the server. It is built from the
+// defaults, a YAML or TOML file, environment variables and command line flags,
+// each overriding the previous one.
+//
+// Every leaf field carries its file key, environment variable and flag. Fields
+// tagged secret are redacted when the configuration is printed.
+type Config struct {
+	Server    ServerConfig    `config:"server"`
+	TLS       TLSConfig       `config:"tls"`
+	Storage   StorageConfig   `config:"storage"`
+	Limits    LimitsConfig    `config:"limits"`
+	Auth      AuthConfig      `config:"auth"`
+	Logging   LoggingConfig   `config:"logging"`
+	Health    HealthConfig    `config:"health"`
+	Metrics   MetricsConfig   `config:"metrics"`
+	Tracing   TracingConfig   `config:"tracing"`
+	Retention RetentionConfig `config:"retention"`
+	Currency  CurrencyConfig  `config:"currency"`
+}
+
+type ServerConfig struct {
+	Address         string        `config:"address" env:"EMPLOYEE_ADDRESS" flag:"address" help:"host:port to listen on"`
+	ReadTimeout     time.Duration `config:"read_timeout" env:"EMPLOYEE_READ_TIMEOUT" flag:"read-timeout" help:"maximum time to read a request"`
+	WriteTimeout    time.Duration `config:"write_timeout" env:"EMPLOYEE_WRITE_TIMEOUT" flag:"write-timeout" help:"maximum time to write a response"`
+	IdleTimeout     time.Duration `config:"idle_timeout" env:"EMPLOYEE_IDLE_TIMEOUT" flag:"idle-timeout" help:"maximum time a keep-alive connection is idle"`
+	ShutdownTimeout time.Duration `config:"shutdown_timeout" env:"EMPLOYEE_SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" help:"time allowed to drain in-flight requests on shutdown"`
+	ShutdownDelay   time.Duration `config:"shutdown_delay" env:"EMPLOYEE_SHUTDOWN_DELAY" flag:"shutdown-delay" help:"time between failing readiness and closing the listener on shutdown"`
+	TrustedProxies  string        `config:"trusted_proxies" env:"EMPLOYEE_TRUSTED_PROXIES" flag:"trusted-proxies" help:"comma separated proxy IPs or CIDRs whose X-Forwarded-For is trusted, none when empty"`
+}
+
+// TrustedProxyList returns the configured trusted proxies.
+func (s ServerConfig) TrustedProxyList() []string {
+	var proxies []string
+	for _, p := range strings.Split(s.TrustedProxies, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
+
+const (
+	ClientAuthNone     = "none"
+	ClientAuthOptional = "optional"
+	ClientAuthRequire  = "require"
+)
+
+type TLSConfig struct {
+	Enabled        bool          `config:"enabled" env:"EMPLOYEE_TLS_ENABLED" flag:"tls" help:"serve HTTPS"`
+	CertFile       string        `config:"cert_file" env:"EMPLOYEE_TLS_CERT_FILE" flag:"tls-cert" help:"PEM certificate chain"`
+	KeyFile        string        `config:"key_file" env:"EMPLOYEE_TLS_KEY_FILE" flag:"tls-key" help:"PEM private key"`
+	ClientCAFile   string        `config:"client_ca_file" env:"EMPLOYEE_TLS_CLIENT_CA_FILE" flag:"tls-client-ca" help:"PEM CA bundle verifying client certificates"`
+	ClientAuth     string        `config:"client_auth" env:"EMPLOYEE_TLS_CLIENT_AUTH" flag:"tls-client-auth" help:"client certificates: none, optional or require"`
+	ReloadInterval time.Duration `config:"reload_interval" env:"EMPLOYEE_TLS_RELOAD_INTERVAL" flag:"tls-reload-interval" help:"how often certificate files are checked for changes"`
+}
+
+const (
+	StorageMemory = "memory"
+	StorageFile   = "file"
+)
+
+type StorageConfig struct {
+	Backend string `config:"backend" env:"EMPLOYEE_STORAGE_BACKEND" flag:"storage" help:"storage backend, memory or file"`
+	Path    string `config:"path" env:"EMPLOYEE_STORAGE_PATH" flag:"storage-path" help:"data directory of the file backend"`
+}
+
+type LimitsConfig struct {
+	DefaultPageSize int     `config:"default_page_size" env:"EMPLOYEE_DEFAULT_PAGE_SIZE" flag:"default-page-size" help:"records returned when num_records is not provided"`
+	MaxPageSize     int     `config:"max_page_size" env:"EMPLOYEE_MAX_PAGE_SIZE" flag:"max-page-size" help:"largest num_records accepted"`
+	ReadRate        float64 `config:"read_rate" env:"EMPLOYEE_READ_RATE" flag:"read-rate" help:"reads per second per client, 0 leaves reads unlimited"`
+	ReadBurst       int     `config:"read_burst" env:"EMPLOYEE_READ_BURST" flag:"read-burst" help:"read burst per client"`
+	WriteRate       float64 `config:"write_rate" env:"EMPLOYEE_WRITE_RATE" flag:"write-rate" help:"writes per second per client, 0 leaves writes unlimited"`
+	WriteBurst      int     `config:"write_burst" env:"EMPLOYEE_WRITE_BURST" flag:"write-burst" help:"write burst per client"`
+	AddressRate     float64 `config:"address_rate" env:"EMPLOYEE_ADDRESS_RATE" flag:"address-rate" help:"requests per second per IP address, counted before authentication, 0 disables it"`
+	AddressBurst    int     `config:"address_burst" env:"EMPLOYEE_ADDRESS_BURST" flag:"address-burst" help:"request burst per IP address"`
+}
+
+type AuthConfig struct {
+	JWKSFile         string        `config:"jwks_file" env:"AUTH_JWKS_FILE" flag:"auth-jwks-file" help:"JWKS file with the token verification keys"`
+	HMACSecret       string        `config:"hmac_secret" env:"AUTH_HMAC_SECRET" flag:"auth-hmac-secret" help:"HS256 token secret" secret:"true"`
+	RSAPublicKeyFile string        `config:"rsa_public_key_file" env:"AUTH_RSA_PUBLIC_KEY_FILE" flag:"auth-rsa-public-key-file" help:"PEM RS256 token verification key"`
+	Issuer           string        `config:"issuer" env:"AUTH_ISSUER" flag:"auth-issuer" help:"required token issuer"`
+	Audience         string        `config:"audience" env:"AUTH_AUDIENCE" flag:"auth-audience" help:"required token audience"`
+	Leeway           time.Duration `config:"leeway" env:"AUTH_LEEWAY" flag:"auth-leeway" help:"clock skew tolerated on token expiry"`
+	PolicyFile       string        `config:"policy_file" env:"AUTHZ_POLICY_FILE" flag:"authz-policy-file" help:"authorization policy, the built-in roles apply when empty"`
+	APIKeys          bool          `config:"api_keys" env:"AUTH_API_KEYS" flag:"auth-api-keys" help:"accept API keys without token authentication, they always are with it"`
+}
+
+// Enabled reports whether a token verification key is configured.
+func (a AuthConfig) Enabled() bool {
+	return a.JWKSFile != "" || a.HMACSecret != "" || a.RSAPublicKeyFile != ""
+}
+
+// APIKeysEnabled reports whether API keys are accepted, on their own or alongside
+// tokens.
+func (a AuthConfig) APIKeysEnabled() bool {
+	return a.APIKeys || a.Enabled()
+}
+
+type HealthConfig struct {
+	Timeout      time.Duration `config:"timeout" env:"EMPLOYEE_HEALTH_TIMEOUT" flag:"health-timeout" help:"time allowed to each health check"`
+	Dependencies string        `config:"dependencies" env:"EMPLOYEE_HEALTH_DEPENDENCIES" flag:"health-dependencies" help:"comma separated URLs that must answer 2xx for the server to be ready"`
+}
+
+// DependencyURLs returns the configured dependency URLs.
+func (h HealthConfig) DependencyURLs() []string {
+	var urls []string
+	for _, u := range strings.Split(h.Dependencies, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+type MetricsConfig struct {
+	Enabled bool `config:"enabled" env:"EMPLOYEE_METRICS_ENABLED" flag:"metrics" help:"serve Prometheus metrics on /metrics"`
+}
+
+const (
+	TracingNone   = "none"
+	TracingStdout = "stdout"
+	TracingFile   = "file"
+)
+
+type TracingConfig struct {
+	Exporter string `config:"exporter" env:"EMPLOYEE_TRACING_EXPORTER" flag:"tracing" help:"span exporter: none, stdout or file"`
+	File     string `config:"file" env:"EMPLOYEE_TRACING_FILE" flag:"tracing-file" help:"file the spans are appended to by the file exporter"`
+}
+
+type RetentionConfig struct {
+	Period        time.Duration `config:"period" env:"EMPLOYEE_RETENTION_PERIOD" flag:"retention-period" help:"time deleted employees are kept before they are purged, 0 keeps them forever"`
+	PurgeInterval time.Duration `config:"purge_interval" env:"EMPLOYEE_PURGE_INTERVAL" flag:"purge-interval" help:"how often deleted employees past the retention period are purged"`
+}
+
+type CurrencyConfig struct {
+	Default   string `config:"default" env:"EMPLOYEE_CURRENCY" flag:"currency" help:"ISO 4217 currency of salaries given without one and of reports"`
+	RatesFile string `config:"rates_file" env:"EMPLOYEE_RATES_FILE" flag:"rates-file" help:"JSON exchange rate table, reports only convert between currencies it covers"`
+}
+
+type LoggingConfig struct {
+	Level      string `config:"level" env:"LOG_LEVEL" flag:"log-level" help:"Debug, Info, Warn or Error"`
+	Format     string `config:"format" env:"LOG_FORMAT" flag:"log-format" help:"console or json"`
+	Output     string `config:"output" env:"LOG_OUTPUT" flag:"log-output" help:"comma separated sinks: stderr, stdout or file paths"`
+	MaxSizeMB  int    `config:"max_size_mb" env:"LOG_MAX_SIZE_MB" flag:"log-max-size-mb" help:"size at which log files are rotated, 0 disables rotation"`
+	MaxBackups int    `config:"max_backups" env:"LOG_MAX_BACKUPS" flag:"log-max-backups" help:"rotated log files kept per file sink"`
+	// The redaction actions are those of logger.RedactionPolicy.
+	RedactPII          string `config:"redact_pii" env:"LOG_REDACT_PII" flag:"log-redact-pii" help:"personal data in logs: allow, mask, hash or drop"`
+	RedactCompensation string `config:"redact_compensation" env:"LOG_REDACT_COMPENSATION" flag:"log-redact-compensation" help:"salaries in logs: mask or drop"`
+}
+
+// Outputs returns the configured log sinks.
+func (l LoggingConfig) Outputs() []string {
+	var outputs []string
+	for _, o := range strings.Split(l.Output, ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			outputs = append(outputs, o)
+		}
+	}
+	return outputs
+}
+
+// Default returns the configuration used for every setting that is not provided.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Address:         "localhost:8080",
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     2 * time.Minute,
+			ShutdownTimeout: 20 * time.Second,
+			ShutdownDelay:   5 * time.Second,
+		},
+		TLS: TLSConfig{
+			ClientAuth:     ClientAuthNone,
+			ReloadInterval: 30 * time.Second,
+		},
+		Storage: StorageConfig{
+			Backend: StorageMemory,
+		},
+		Limits: LimitsConfig{
+			DefaultPageSize: 10,
+			MaxPageSize:     100,
+			ReadRate:        20,
+			ReadBurst:       40,
+			WriteRate:       5,
+			WriteBurst:      10,
+			AddressRate:     50,
+			AddressBurst:    100,
+		},
+		Auth: AuthConfig{
+			Leeway: 30 * time.Second,
+		},
+		Logging: LoggingConfig{
+			Level:              "Info",
+			Format:             "console",
+			Output:             "stderr",
+			MaxSizeMB:          100,
+			MaxBackups:         5,
+			RedactPII:          "hash",
+			RedactCompensation: "drop",
+		},
+		Health: HealthConfig{
+			Timeout: 2 * time.Second,
+		},
+		Metrics: MetricsConfig{Enabled: true},
+		Tracing: TracingConfig{Exporter: TracingNone},
+		Retention: RetentionConfig{
+			PurgeInterval: time.Hour,
+		},
+		Currency: CurrencyConfig{Default: "USD"},
+	}
+}
@@ -0,0 +1,296 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileEnv names the environment variable holding the configuration file path.
+const ConfigFileEnv = "EMPLOYEE_CONFIG"
+
+// redacted replaces secret values when the configuration is printed.
+const redacted = "REDACTED"
+
+// field is a leaf setting of Config.
+type field struct {
+	key    string // dotted file key, e.g. storage.path
+	env    string
+	flag   string
+	help   string
+	secret bool
+	value  reflect.Value
+}
+
+// Loaded is the result of Load.
+type Loaded struct {
+	Config Config
+	// File is the configuration file that was read, if any.
+	File string
+	// Print is set when the effective configuration was requested with -print-config.
+	Print bool
+}
+
+// Load builds the configuration from the defaults, the configuration file, the
+// environment and the command line args, in increasing order of precedence, and
+// validates the result. lookupEnv is usually os.LookupEnv.
+//
+// The configuration file is given by -config or EMPLOYEE_CONFIG and is parsed as
+// TOML when its extension is .toml and as YAML otherwise.
+func Load(args []string, lookupEnv func(string) (string, bool)) (Loaded, error) {
+	res := Loaded{Config: Default()}
+	fields := leafFields(&res.Config)
+
+	fs := flag.NewFlagSet("employee", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&res.File, "config", "", "YAML or TOML configuration file")
+	fs.BoolVar(&res.Print, "print-config", false, "print the effective configuration with secrets redacted and exit")
+	flagValues := map[string]string{}
+	for _, f := range fields {
+		fs.Var(&rawFlag{name: f.flag, values: flagValues, isBool: f.value.Kind() == reflect.Bool}, f.flag, f.help)
+	}
+	if err := fs.Parse(args); err != nil {
+		return res, err
+	}
+
+	if res.File == "" {
+		res.File, _ = lookupEnv(ConfigFileEnv)
+	}
+	if res.File != "" {
+		if err := applyFile(res.File, fields); err != nil {
+			return res, err
+		}
+	}
+
+	for _, f := range fields {
+		if v, ok := lookupEnv(f.env); ok {
+			if err := set(f.value, v); err != nil {
+				return res, fmt.Errorf("environment %s: %w", f.env, err)
+			}
+		}
+	}
+
+	for _, f := range fields {
+		if v, ok := flagValues[f.flag]; ok {
+			if err := set(f.value, v); err != nil {
+				return res, fmt.Errorf("flag -%s: %w", f.flag, err)
+			}
+		}
+	}
+
+	if err := res.Config.Validate(); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// Usage returns the flags understood by Load with their environment variables.
+func Usage() string {
+	var b strings.Builder
+	b.WriteString("  -config FILE\n\tYAML or TOML configuration file (env " + ConfigFileEnv + ")\n")
+	b.WriteString("  -print-config\n\tprint the effective configuration with secrets redacted and exit\n")
+	cfg := Default()
+	for _, f := range leafFields(&cfg) {
+		fmt.Fprintf(&b, "  -%s\n\t%s (env %s, file %s)\n", f.flag, f.help, f.env, f.key)
+	}
+	return b.String()
+}
+
+// leafFields walks cfg and returns every settable leaf field.
+func leafFields(cfg *Config) []field {
+	var fields []field
+	var walk func(v reflect.Value, prefix string)
+	walk = func(v reflect.Value, prefix string) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			key := sf.Tag.Get("config")
+			if key == "" {
+				continue
+			}
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			if sf.Type.Kind() == reflect.Struct {
+				walk(v.Field(i), key)
+				continue
+			}
+			fields = append(fields, field{
+				key:    key,
+				env:    sf.Tag.Get("env"),
+				flag:   sf.Tag.Get("flag"),
+				help:   sf.Tag.Get("help"),
+				secret: sf.Tag.Get("secret") == "true",
+				value:  v.Field(i),
+			})
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem(), "")
+	return fields
+}
+
+func applyFile(path string, fields []field) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	tree := map[string]any{}
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		err = toml.Unmarshal(data, &tree)
+	} else {
+		err = yaml.Unmarshal(data, &tree)
+	}
+	if err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	if err := flatten(tree, "", values); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	byKey := map[string]field{}
+	for _, f := range fields {
+		byKey[f.key] = f
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		f, ok := byKey[k]
+		if !ok {
+			return fmt.Errorf("config file %s: unknown setting %q", path, k)
+		}
+		if err := set(f.value, values[k]); err != nil {
+			return fmt.Errorf("config file %s: %s: %w", path, k, err)
+		}
+	}
+	return nil
+}
+
+func flatten(tree map[string]any, prefix string, out map[string]string) error {
+	for k, v := range tree {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch val := v.(type) {
+		case map[string]any:
+			if err := flatten(val, key, out); err != nil {
+				return err
+			}
+		case []any:
+			return fmt.Errorf("%s: lists are not supported", key)
+		case nil:
+		default:
+			out[key] = fmt.Sprint(val)
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// set parses s into the leaf field v.
+func set(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(i))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+// rawFlag records the flags given on the command line so they can be applied
+// after the file and the environment.
+type rawFlag struct {
+	name   string
+	values map[string]string
+	isBool bool
+}
+
+func (f *rawFlag) String() string { return "" }
+
+func (f *rawFlag) Set(s string) error {
+	f.values[f.name] = s
+	return nil
+}
+
+func (f *rawFlag) IsBoolFlag() bool { return f.isBool }
+
+// Print writes the configuration as YAML with every secret redacted.
+func (c Config) Print(w io.Writer) error {
+	tree := map[string]any{}
+	for _, f := range leafFields(&c) {
+		var v any
+		switch {
+		case f.secret && !f.value.IsZero():
+			v = redacted
+		case f.value.Type() == durationType:
+			v = time.Duration(f.value.Int()).String()
+		default:
+			v = f.value.Interface()
+		}
+		node := tree
+		parts := strings.Split(f.key, ".")
+		for _, p := range parts[:len(parts)-1] {
+			child, ok := node[p].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				node[p] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = v
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(tree); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+var errNoValue = errors.New("must not be empty")
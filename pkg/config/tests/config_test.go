@@ -0,0 +1,150 @@
+package config_test
+
+import (
+	"bytes"
+	"employee/pkg/config"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func envOf(vars map[string]string) func(string) (string, bool) {
+	return func(k string) (string, bool) {
+		v, ok := vars[k]
+		return v, ok
+	}
+}
+
+var _ = Describe("Load", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+		return path
+	}
+
+	It("should return the defaults without any source", func() {
+		loaded, err := config.Load(nil, envOf(nil))
+		Expect(err).To(BeNil())
+		Expect(loaded.Config).To(Equal(config.Default()))
+	})
+
+	It("should apply file, environment and flags in increasing precedence", func() {
+		path := writeFile("config.yaml", `
+server:
+  address: "0.0.0.0:9000"
+limits:
+  max_page_size: 50
+  default_page_size: 5
+logging:
+  level: debug
+`)
+		loaded, err := config.Load(
+			[]string{"-config", path, "-max-page-size", "70"},
+			envOf(map[string]string{"EMPLOYEE_ADDRESS": "127.0.0.1:9100", "EMPLOYEE_MAX_PAGE_SIZE": "60"}),
+		)
+		Expect(err).To(BeNil())
+		Expect(loaded.Config.Server.Address).To(Equal("127.0.0.1:9100"))
+		Expect(loaded.Config.Limits.MaxPageSize).To(Equal(70))
+		Expect(loaded.Config.Limits.DefaultPageSize).To(Equal(5))
+		Expect(loaded.Config.Logging.Level).To(Equal("Debug"))
+	})
+
+	It("should read TOML files", func() {
+		path := writeFile("config.toml", `
+[auth]
+leeway = "1m"
+
+[storage]
+backend = "file"
+path = "`+filepath.Join(dir, "data")+`"
+`)
+		loaded, err := config.Load(nil, envOf(map[string]string{config.ConfigFileEnv: path}))
+		Expect(err).To(BeNil())
+		Expect(loaded.Config.Auth.Leeway).To(Equal(time.Minute))
+		Expect(loaded.Config.Storage.Backend).To(Equal(config.StorageFile))
+	})
+
+	It("should reject unknown settings in the file", func() {
+		path := writeFile("config.yaml", "server:\n  adress: localhost:1\n")
+		_, err := config.Load([]string{"-config", path}, envOf(nil))
+		Expect(err).To(MatchError(ContainSubstring("server.adress")))
+	})
+
+	It("should report every invalid setting", func() {
+		_, err := config.Load([]string{"-storage", "s3", "-max-page-size", "1", "-log-level", "loud"}, envOf(nil))
+		Expect(err).To(MatchError(ContainSubstring("storage.backend")))
+		Expect(err).To(MatchError(ContainSubstring("limits.max_page_size")))
+		Expect(err).To(MatchError(ContainSubstring("logging.level")))
+	})
+
+	It("should require an ISO 4217 default currency and a readable rates file", func() {
+		loaded, err := config.Load([]string{"-currency", "EUR"}, envOf(map[string]string{"EMPLOYEE_RATES_FILE": writeFile("rates.json", `{"rates":[]}`)}))
+		Expect(err).To(BeNil())
+		Expect(loaded.Config.Currency.Default).To(Equal("EUR"))
+
+		_, err = config.Load([]string{"-currency", "euro"}, envOf(nil))
+		Expect(err).To(MatchError(ContainSubstring("currency.default")))
+		_, err = config.Load([]string{"-rates-file", filepath.Join(dir, "missing.json")}, envOf(nil))
+		Expect(err).To(MatchError(ContainSubstring("currency.rates_file")))
+	})
+
+	It("should require a purge interval with a retention period", func() {
+		loaded, err := config.Load([]string{"-retention-period", "2160h"}, envOf(map[string]string{"EMPLOYEE_PURGE_INTERVAL": "30m"}))
+		Expect(err).To(BeNil())
+		Expect(loaded.Config.Retention).To(Equal(config.RetentionConfig{Period: 2160 * time.Hour, PurgeInterval: 30 * time.Minute}))
+
+		_, err = config.Load([]string{"-retention-period", "2160h", "-purge-interval", "0s"}, envOf(nil))
+		Expect(err).To(MatchError(ContainSubstring("retention.purge_interval")))
+		_, err = config.Load([]string{"-retention-period", "-1h"}, envOf(nil))
+		Expect(err).To(MatchError(ContainSubstring("retention.period")))
+	})
+
+	It("should enable API keys on their own or with tokens", func() {
+		loaded, err := config.Load(nil, envOf(map[string]string{"AUTH_API_KEYS": "true"}))
+		Expect(err).To(BeNil())
+		Expect(loaded.Config.Auth.Enabled()).To(BeFalse())
+		Expect(loaded.Config.Auth.APIKeysEnabled()).To(BeTrue())
+
+		loaded, err = config.Load(nil, envOf(map[string]string{"AUTH_HMAC_SECRET": "0123456789abcdef0123456789abcdef"}))
+		Expect(err).To(BeNil())
+		Expect(loaded.Config.Auth.APIKeysEnabled()).To(BeTrue())
+		Expect(config.Default().Auth.APIKeysEnabled()).To(BeFalse())
+	})
+
+	It("should read the trusted proxies and trust none by default", func() {
+		Expect(config.Default().Server.TrustedProxyList()).To(BeEmpty())
+		loaded, err := config.Load([]string{"-trusted-proxies", "10.0.0.0/8, 192.0.2.1"}, envOf(nil))
+		Expect(err).To(BeNil())
+		Expect(loaded.Config.Server.TrustedProxyList()).To(Equal([]string{"10.0.0.0/8", "192.0.2.1"}))
+
+		_, err = config.Load([]string{"-trusted-proxies", "proxy.internal"}, envOf(nil))
+		Expect(err).To(MatchError(ContainSubstring("server.trusted_proxies")))
+	})
+
+	It("should require certificate files when TLS is enabled", func() {
+		_, err := config.Load([]string{"-tls"}, envOf(nil))
+		Expect(err).To(MatchError(ContainSubstring("tls.cert_file")))
+	})
+
+	It("should redact secrets when printing", func() {
+		loaded, err := config.Load([]string{"-print-config"},
+			envOf(map[string]string{"AUTH_HMAC_SECRET": "0123456789abcdef0123456789abcdef"}))
+		Expect(err).To(BeNil())
+		Expect(loaded.Print).To(BeTrue())
+
+		var buf bytes.Buffer
+		Expect(loaded.Config.Print(&buf)).To(Succeed())
+		Expect(buf.String()).To(ContainSubstring("hmac_secret: REDACTED"))
+		Expect(buf.String()).NotTo(ContainSubstring("0123456789abcdef"))
+		Expect(buf.String()).To(ContainSubstring("leeway: 30s"))
+	})
+})
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"employee/pkg/currency"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate checks every setting and returns all problems found.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(key string, err error) {
+		errs = append(errs, fmt.Errorf("%s: %w", key, err))
+	}
+
+	if _, port, err := net.SplitHostPort(c.Server.Address); err != nil {
+		add("server.address", err)
+	} else if p, err := strconv.Atoi(port); err != nil || p < 0 || p > 65535 {
+		add("server.address", fmt.Errorf("invalid port %q", port))
+	}
+
+	for key, d := range map[string]time.Duration{
+		"server.read_timeout":     c.Server.ReadTimeout,
+		"server.write_timeout":    c.Server.WriteTimeout,
+		"server.idle_timeout":     c.Server.IdleTimeout,
+		"server.shutdown_timeout": c.Server.ShutdownTimeout,
+		"health.timeout":          c.Health.Timeout,
+	} {
+		if d <= 0 {
+			add(key, errors.New("must be positive"))
+		}
+	}
+
+	if c.Server.ShutdownDelay < 0 {
+		add("server.shutdown_delay", errors.New("must not be negative"))
+	}
+
+	if c.TLS.Enabled {
+		if err := readableFile(c.TLS.CertFile); err != nil {
+			add("tls.cert_file", err)
+		}
+		if err := readableFile(c.TLS.KeyFile); err != nil {
+			add("tls.key_file", err)
+		}
+		if c.TLS.ReloadInterval <= 0 {
+			add("tls.reload_interval", errors.New("must be positive"))
+		}
+	}
+	switch c.TLS.ClientAuth {
+	case ClientAuthNone:
+	case ClientAuthOptional, ClientAuthRequire:
+		if !c.TLS.Enabled {
+			add("tls.client_auth", errors.New("requires tls.enabled"))
+		}
+		if err := readableFile(c.TLS.ClientCAFile); err != nil {
+			add("tls.client_ca_file", err)
+		}
+	default:
+		add("tls.client_auth", fmt.Errorf("unknown mode %q, use %s, %s or %s", c.TLS.ClientAuth, ClientAuthNone, ClientAuthOptional, ClientAuthRequire))
+	}
+
+	switch c.Storage.Backend {
+	case StorageMemory:
+	case StorageFile:
+		if c.Storage.Path == "" {
+			add("storage.path", errNoValue)
+		} else if fi, err := os.Stat(c.Storage.Path); err == nil && !fi.IsDir() {
+			add("storage.path", errors.New("must be a directory"))
+		}
+	default:
+		add("storage.backend", fmt.Errorf("unknown backend %q, use %s or %s", c.Storage.Backend, StorageMemory, StorageFile))
+	}
+
+	if c.Limits.DefaultPageSize <= 0 {
+		add("limits.default_page_size", errors.New("must be positive"))
+	}
+	if c.Limits.MaxPageSize < c.Limits.DefaultPageSize {
+		add("limits.max_page_size", errors.New("must not be less than limits.default_page_size"))
+	}
+	if c.Limits.ReadRate < 0 || c.Limits.WriteRate < 0 || c.Limits.AddressRate < 0 {
+		add("limits", errors.New("rates must not be negative"))
+	}
+	if c.Limits.ReadRate > 0 && c.Limits.ReadBurst < 1 {
+		add("limits.read_burst", errors.New("must be at least 1 when rate limiting reads"))
+	}
+	if c.Limits.WriteRate > 0 && c.Limits.WriteBurst < 1 {
+		add("limits.write_burst", errors.New("must be at least 1 when rate limiting writes"))
+	}
+	if c.Limits.AddressRate > 0 && c.Limits.AddressBurst < 1 {
+		add("limits.address_burst", errors.New("must be at least 1 when rate limiting IP addresses"))
+	}
+
+	for _, proxy := range c.Server.TrustedProxyList() {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			add("server.trusted_proxies", fmt.Errorf("%q is neither an IP address nor a CIDR", proxy))
+		}
+	}
+
+	for key, path := range map[string]string{
+		"auth.jwks_file":           c.Auth.JWKSFile,
+		"auth.rsa_public_key_file": c.Auth.RSAPublicKeyFile,
+		"auth.policy_file":         c.Auth.PolicyFile,
+		"currency.rates_file":      c.Currency.RatesFile,
+	} {
+		if path != "" {
+			if err := readableFile(path); err != nil {
+				add(key, err)
+			}
+		}
+	}
+	if c.Auth.HMACSecret != "" && len(c.Auth.HMACSecret) < 32 {
+		add("auth.hmac_secret", errors.New("must be at least 32 bytes"))
+	}
+	if c.Auth.PolicyFile != "" && !c.Auth.Enabled() && c.TLS.ClientAuth == ClientAuthNone {
+		add("auth.policy_file", errors.New("requires a token verification key or client certificates"))
+	}
+	if c.Auth.Leeway < 0 {
+		add("auth.leeway", errors.New("must not be negative"))
+	}
+
+	for _, dep := range c.Health.DependencyURLs() {
+		if u, err := url.Parse(dep); err != nil {
+			add("health.dependencies", err)
+		} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("health.dependencies", fmt.Errorf("%q is not an absolute http or https URL", dep))
+		}
+	}
+
+	switch c.Tracing.Exporter {
+	case TracingNone, TracingStdout:
+	case TracingFile:
+		if c.Tracing.File == "" {
+			add("tracing.file", errNoValue)
+		}
+	default:
+		add("tracing.exporter", fmt.Errorf("unknown exporter %q, use %s, %s or %s", c.Tracing.Exporter, TracingNone, TracingStdout, TracingFile))
+	}
+
+	if c.Retention.Period < 0 {
+		add("retention.period", errors.New("must not be negative"))
+	}
+	if c.Retention.Period > 0 && c.Retention.PurgeInterval <= 0 {
+		add("retention.purge_interval", errors.New("must be positive when a retention period is set"))
+	}
+
+	if !currency.Valid(c.Currency.Default) {
+		add("currency.default", fmt.Errorf("unknown ISO 4217 currency %q", c.Currency.Default))
+	}
+
+	switch strings.ToLower(c.Logging.Level) {
+	case "debug", "info", "warn", "error":
+		c.Logging.Level = strings.ToUpper(c.Logging.Level[:1]) + strings.ToLower(c.Logging.Level[1:])
+	default:
+		add("logging.level", fmt.Errorf("unknown level %q", c.Logging.Level))
+	}
+	switch c.Logging.Format {
+	case "console", "json":
+	default:
+		add("logging.format", fmt.Errorf("unknown format %q, use console or json", c.Logging.Format))
+	}
+	if len(c.Logging.Outputs()) == 0 {
+		add("logging.output", errNoValue)
+	}
+	for _, out := range c.Logging.Outputs() {
+		if out == "stderr" || out == "stdout" {
+			continue
+		}
+		if fi, err := os.Stat(filepath.Dir(out)); err != nil {
+			add("logging.output", err)
+		} else if !fi.IsDir() {
+			add("logging.output", fmt.Errorf("%s is not a directory", filepath.Dir(out)))
+		}
+	}
+	switch c.Logging.RedactPII {
+	case "allow", "mask", "hash", "drop":
+	default:
+		add("logging.redact_pii", fmt.Errorf("unknown action %q, use allow, mask, hash or drop", c.Logging.RedactPII))
+	}
+	switch c.Logging.RedactCompensation {
+	case "mask", "drop":
+	default:
+		add("logging.redact_compensation", fmt.Errorf("unknown action %q, use mask or drop", c.Logging.RedactCompensation))
+	}
+	if c.Logging.MaxSizeMB < 0 || c.Logging.MaxBackups < 0 {
+		add("logging", errors.New("max_size_mb and max_backups must not be negative"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func readableFile(path string) error {
+	if path == "" {
+		return errNoValue
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,60 @@
+package currency
+
+import (
+	"math/big"
+	"strings"
+)
+
+// minorUnits maps the ISO 4217 codes of the currencies in circulation to the
+// number of digits of their minor unit. Precious metals, funds and testing codes
+// are left out.
+var minorUnits = func() map[string]int {
+	units := map[string]int{}
+	for _, code := range strings.Fields(`
+		AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BMD BND BOB BOV BRL BSD
+		BTN BWP BYN BZD CAD CDF CHE CHF CHW CNY COP COU CRC CUP CVE CZK DKK DOP DZD EGP
+		ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GTQ GYD HKD HNL HTG HUF IDR ILS INR IRR
+		JMD KES KGS KHR KPW KYD KZT LAK LBP LKR LRD LSL MAD MDL MGA MKD MMK MNT MOP MRU
+		MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD PAB PEN PGK PHP PKR PLN QAR
+		RON RSD RUB SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS
+		TMT TOP TRY TTD TWD TZS UAH USD USN UYU UZS VED VES WST XCD XCG YER ZAR ZMW ZWG`) {
+		units[code] = 2
+	}
+	for _, code := range strings.Fields("BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX UYI VND VUV XAF XOF XPF") {
+		units[code] = 0
+	}
+	for _, code := range strings.Fields("BHD IQD JOD KWD LYD OMR TND") {
+		units[code] = 3
+	}
+	for _, code := range strings.Fields("CLF UYW") {
+		units[code] = 4
+	}
+	return units
+}()
+
+// Valid reports whether code is the ISO 4217 code of a currency in circulation.
+func Valid(code string) bool {
+	_, ok := minorUnits[code]
+	return ok
+}
+
+// MinorUnits returns the number of decimal digits of the minor unit of code, 2 for
+// unknown codes.
+func MinorUnits(code string) int {
+	if digits, ok := minorUnits[code]; ok {
+		return digits
+	}
+	return 2
+}
+
+// Round returns amount rounded half away from zero to the minor unit of code.
+func Round(amount *big.Rat, code string) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(MinorUnits(code))), nil)
+	n := new(big.Int).Mul(amount.Num(), scale)
+	q, r := new(big.Int).QuoRem(n, amount.Denom(), new(big.Int))
+	// Round up when the remainder is at least half of the denominator
+	if r.Abs(r).Lsh(r, 1).Cmp(amount.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(n.Sign())))
+	}
+	return new(big.Rat).SetFrac(q, scale)
+}
@@ -0,0 +1,171 @@
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ErrNoRate is returned when no rate converts between two currencies on a date.
+var ErrNoRate = errors.New("no exchange rate")
+
+// decimal matches the rates accepted, plain decimal numbers.
+var decimal = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// Rate converts one unit of From into Rate units of To, from EffectiveDate, a
+// YYYY-MM-DD date, until the next rate of the same pair. Rate is a decimal string so
+// that it is read exactly.
+type Rate struct {
+	From          string `json:"from"`
+	To            string `json:"to"`
+	Rate          string `json:"rate"`
+	EffectiveDate string `json:"effective_date"`
+}
+
+// UnmarshalJSON decodes a rate given as a decimal string or a JSON number. A number
+// keeps the digits written rather than being read through a float64.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	type plain Rate
+	var decoded struct {
+		plain
+		Rate json.Number `json:"rate"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*r = Rate(decoded.plain)
+	r.Rate = decoded.Rate.String()
+	return nil
+}
+
+type pair struct{ from, to string }
+
+type datedRate struct {
+	date string
+	rate *big.Rat
+}
+
+// Table is a set of exchange rates with effective dates. A pair is converted with
+// its own rates or, if it has none, the inverse of the rates of the opposite pair.
+// Failing both, it is converted through a third currency, the first in alphabetical
+// order both currencies have a rate with. A nil table only converts a currency into
+// itself.
+type Table struct {
+	rates map[pair][]datedRate
+	// currencies lists the currencies with rates in alphabetical order.
+	currencies []string
+}
+
+// NewTable validates rates and returns their table.
+func NewTable(rates []Rate) (*Table, error) {
+	t := &Table{rates: map[pair][]datedRate{}}
+	for i, r := range rates {
+		if !Valid(r.From) || !Valid(r.To) || r.From == r.To {
+			return nil, fmt.Errorf("rate %d: invalid currency pair %q to %q", i, r.From, r.To)
+		}
+		if _, err := time.Parse(time.DateOnly, r.EffectiveDate); err != nil {
+			return nil, fmt.Errorf("rate %d: invalid effective date %q", i, r.EffectiveDate)
+		}
+		rate, ok := new(big.Rat).SetString(r.Rate)
+		if !decimal.MatchString(r.Rate) || !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("rate %d: invalid rate %q, must be a positive decimal", i, r.Rate)
+		}
+		p := pair{r.From, r.To}
+		t.currencies = append(t.currencies, r.From, r.To)
+		t.rates[p] = append(t.rates[p], datedRate{date: r.EffectiveDate, rate: rate})
+	}
+	slices.Sort(t.currencies)
+	t.currencies = slices.Compact(t.currencies)
+	for p, dated := range t.rates {
+		sort.SliceStable(dated, func(i, j int) bool { return dated[i].date < dated[j].date })
+		for i := 1; i < len(dated); i++ {
+			if dated[i].date == dated[i-1].date {
+				return nil, fmt.Errorf("two rates from %s to %s effective on %s", p.from, p.to, dated[i].date)
+			}
+		}
+	}
+	return t, nil
+}
+
+// LoadRatesFile reads a JSON rate table, an object with the list of rates under
+// "rates", from path and validates it.
+func LoadRatesFile(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rates file: %w", err)
+	}
+	var file struct {
+		Rates []Rate `json:"rates"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse rates file: %w", err)
+	}
+	return NewTable(file.Rates)
+}
+
+// rateOn returns the rate of p in effect on date.
+func (t *Table) rateOn(p pair, date string) (*big.Rat, bool) {
+	if t == nil {
+		return nil, false
+	}
+	dated := t.rates[p]
+	for i := len(dated) - 1; i >= 0; i-- {
+		if dated[i].date <= date {
+			return dated[i].rate, true
+		}
+	}
+	return nil, false
+}
+
+// direct returns the rate of from into to on date from the rates of the pair or its
+// opposite.
+func (t *Table) direct(from, to, date string) (*big.Rat, bool) {
+	if rate, ok := t.rateOn(pair{from, to}, date); ok {
+		return new(big.Rat).Set(rate), true
+	}
+	if rate, ok := t.rateOn(pair{to, from}, date); ok {
+		return new(big.Rat).Inv(rate), true
+	}
+	return nil, false
+}
+
+// Rate returns the rate converting from into to on date.
+func (t *Table) Rate(from, to, date string) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+	if rate, ok := t.direct(from, to, date); ok {
+		return rate, nil
+	}
+	if t != nil {
+		for _, via := range t.currencies {
+			if via == from || via == to {
+				continue
+			}
+			first, ok := t.direct(from, via, date)
+			if !ok {
+				continue
+			}
+			if second, ok := t.direct(via, to, date); ok {
+				return first.Mul(first, second), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w from %s to %s on %s", ErrNoRate, from, to, date)
+}
+
+// Convert returns amount in from converted into to at the rate in effect on date.
+// The result is exact, callers round it once they are done with it.
+func (t *Table) Convert(amount *big.Rat, from, to, date string) (*big.Rat, error) {
+	rate, err := t.Rate(from, to, date)
+	if err != nil {
+		return nil, err
+	}
+	return rate.Mul(rate, amount), nil
+}
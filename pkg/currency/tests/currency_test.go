@@ -0,0 +1,118 @@
+package currency_test
+
+import (
+	"employee/pkg/currency"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func rat(s string) *big.Rat {
+	r, ok := new(big.Rat).SetString(s)
+	Expect(ok).To(BeTrue())
+	return r
+}
+
+var _ = Describe("Currencies", func() {
+	It("should know the ISO 4217 codes and their minor units", func() {
+		Expect(currency.Valid("EUR")).To(BeTrue())
+		Expect(currency.Valid("eur")).To(BeFalse())
+		Expect(currency.Valid("XAU")).To(BeFalse())
+		Expect(currency.MinorUnits("USD")).To(Equal(2))
+		Expect(currency.MinorUnits("JPY")).To(Equal(0))
+		Expect(currency.MinorUnits("KWD")).To(Equal(3))
+	})
+
+	It("should round half away from zero to the minor unit", func() {
+		Expect(currency.Round(rat("2.005"), "USD")).To(Equal(rat("2.01")))
+		Expect(currency.Round(rat("-2.005"), "USD")).To(Equal(rat("-2.01")))
+		Expect(currency.Round(rat("2.0049"), "USD")).To(Equal(rat("2")))
+		Expect(currency.Round(rat("100/3"), "JPY")).To(Equal(rat("33")))
+		Expect(currency.Round(rat("1.0005"), "BHD")).To(Equal(rat("1.001")))
+	})
+})
+
+var _ = Describe("Table", func() {
+	var table *currency.Table
+
+	BeforeEach(func() {
+		var err error
+		table, err = currency.NewTable([]currency.Rate{
+			{From: "EUR", To: "USD", Rate: "1.10", EffectiveDate: "2026-01-01"},
+			{From: "EUR", To: "USD", Rate: "1.20", EffectiveDate: "2026-04-01"},
+			{From: "USD", To: "JPY", Rate: "150", EffectiveDate: "2026-01-01"},
+		})
+		Expect(err).To(BeNil())
+	})
+
+	It("should convert at the rate in effect on the date", func() {
+		Expect(table.Convert(rat("100"), "EUR", "USD", "2026-03-31")).To(Equal(rat("110")))
+		Expect(table.Convert(rat("100"), "EUR", "USD", "2026-04-01")).To(Equal(rat("120")))
+		_, err := table.Convert(rat("100"), "EUR", "USD", "2025-12-31")
+		Expect(err).To(MatchError(currency.ErrNoRate))
+	})
+
+	It("should convert the opposite way with the exact inverse", func() {
+		converted, err := table.Convert(rat("100"), "USD", "EUR", "2026-02-01")
+		Expect(err).To(BeNil())
+		Expect(converted).To(Equal(rat("1000/11")))
+		Expect(table.Convert(rat("3000"), "JPY", "USD", "2026-02-01")).To(Equal(rat("20")))
+	})
+
+	It("should only convert a currency into itself without rates", func() {
+		var empty *currency.Table
+		Expect(empty.Convert(rat("100"), "EUR", "EUR", "2026-02-01")).To(Equal(rat("100")))
+		_, err := empty.Convert(rat("100"), "EUR", "USD", "2026-02-01")
+		Expect(err).To(MatchError(currency.ErrNoRate))
+		_, err = table.Convert(rat("100"), "EUR", "GBP", "2026-02-01")
+		Expect(err).To(MatchError(currency.ErrNoRate))
+	})
+
+	It("should convert through a third currency both have rates with", func() {
+		Expect(table.Convert(rat("100"), "EUR", "JPY", "2026-02-01")).To(Equal(rat("16500")))
+		Expect(table.Convert(rat("16500"), "JPY", "EUR", "2026-02-01")).To(Equal(rat("100")))
+	})
+
+	DescribeTable("should reject invalid rates",
+		func(rate currency.Rate) {
+			_, err := currency.NewTable([]currency.Rate{{From: "EUR", To: "USD", Rate: "1.1", EffectiveDate: "2026-01-01"}, rate})
+			Expect(err).NotTo(BeNil())
+		},
+		Entry("unknown currency", currency.Rate{From: "EUR", To: "ABC", Rate: "1", EffectiveDate: "2026-01-01"}),
+		Entry("same currency", currency.Rate{From: "EUR", To: "EUR", Rate: "1", EffectiveDate: "2026-01-01"}),
+		Entry("invalid date", currency.Rate{From: "GBP", To: "USD", Rate: "1.3", EffectiveDate: "2026-13-01"}),
+		Entry("zero rate", currency.Rate{From: "GBP", To: "USD", Rate: "0", EffectiveDate: "2026-01-01"}),
+		Entry("fraction", currency.Rate{From: "GBP", To: "USD", Rate: "13/10", EffectiveDate: "2026-01-01"}),
+		Entry("duplicate date", currency.Rate{From: "EUR", To: "USD", Rate: "1.2", EffectiveDate: "2026-01-01"}),
+	)
+
+	It("should load a rates file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "rates.json")
+		Expect(os.WriteFile(path, []byte(`{"rates":[{"from":"GBP","to":"USD","rate":"1.25","effective_date":"2026-01-01"}]}`), 0o600)).To(Succeed())
+		loaded, err := currency.LoadRatesFile(path)
+		Expect(err).To(BeNil())
+		Expect(loaded.Convert(rat("4"), "GBP", "USD", "2026-01-01")).To(Equal(rat("5")))
+
+		Expect(os.WriteFile(path, []byte(`{"rates":[{"from":"GBP","to":"USD","rate":1.25}]}`), 0o600)).To(Succeed())
+		_, err = currency.LoadRatesFile(path)
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should read numeric rates exactly", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "rates.json")
+		// 0.1 has no exact float64, read through one it would not convert 10 into 1
+		Expect(os.WriteFile(path, []byte(`{"rates":[{"from":"JPY","to":"USD","rate":0.1,"effective_date":"2026-01-01"}]}`), 0o600)).To(Succeed())
+		loaded, err := currency.LoadRatesFile(path)
+		Expect(err).To(BeNil())
+		Expect(loaded.Convert(rat("10"), "JPY", "USD", "2026-01-01")).To(Equal(rat("1")))
+
+		for _, rate := range []string{`1e-1`, `-0.1`, `true`, `"abc"`} {
+			Expect(os.WriteFile(path, []byte(`{"rates":[{"from":"JPY","to":"USD","rate":`+rate+`,"effective_date":"2026-01-01"}]}`), 0o600)).To(Succeed())
+			_, err = currency.LoadRatesFile(path)
+			Expect(err).NotTo(BeNil(), rate)
+		}
+	})
+})
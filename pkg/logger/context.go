@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+type loggerKey struct{}
+
+type requestIDKey struct{}
+
+// Ctx returns the request scoped logger attached to ctx, or the global Log when
+// there is none.
+func Ctx(ctx context.Context) *zerolog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*zerolog.Logger); ok {
+		return l
+	}
+	return &Log
+}
+
+// WithLogger returns a copy of ctx carrying l.
+func WithLogger(ctx context.Context, l zerolog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, &l)
+}
+
+// WithStr returns a copy of ctx whose logger adds the field key to every line.
+func WithStr(ctx context.Context, key, value string) context.Context {
+	return WithLogger(ctx, Ctx(ctx).With().Str(key, value).Logger())
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or an empty string.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
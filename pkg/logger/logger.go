@@ -1,42 +1,143 @@
 package logger
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/rs/zerolog"
 )
 
-var Log zerolog.Logger
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
 
+	OutputStderr = "stderr"
+	OutputStdout = "stdout"
+)
+
+// The field names are process wide in zerolog, they are set once so that every
+// logger, including those built before Init, writes the same names.
 func init() {
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 	zerolog.TimestampFieldName = "ts"
 	zerolog.LevelFieldName = "lvl"
 	zerolog.MessageFieldName = "msg"
+}
+
+// Log is the process wide logger. Until Init is called it writes Info and above
+// to stderr in the console format, redacted with DefaultRedaction.
+var Log = zerolog.New(NewRedactingWriter(zerolog.ConsoleWriter{Out: os.Stderr, NoColor: true}, DefaultRedaction)).
+	Level(zerolog.InfoLevel).With().Timestamp().Logger()
+
+// Options configures the logger built by Init.
+type Options struct {
+	// Level is one of Debug, Info, Warn or Error.
+	Level string
+	// Format is FormatConsole or FormatJSON.
+	Format string
+	// Outputs are the sinks written to: OutputStderr, OutputStdout or a file path.
+	Outputs []string
+	// MaxSizeMB rotates a file sink once it grows past this size, 0 disables rotation.
+	MaxSizeMB int
+	// MaxBackups is the number of rotated files kept per file sink.
+	MaxBackups int
+	// Redaction is applied to every event before it reaches a sink. DefaultRedaction
+	// is used when it is nil.
+	Redaction *RedactionPolicy
+}
+
+// Init replaces Log with a logger writing to the configured sinks.
+//
+// It returns a closer for the file sinks, to be closed once nothing logs anymore.
+func Init(opts Options) (io.Closer, error) {
+	if err := SetLevel(opts.Level); err != nil {
+		return nil, err
+	}
+	redaction := DefaultRedaction
+	if opts.Redaction != nil {
+		redaction = *opts.Redaction
+	}
+	if err := redaction.Validate(); err != nil {
+		return nil, err
+	}
+	if len(opts.Outputs) == 0 {
+		opts.Outputs = []string{OutputStderr}
+	}
+
+	var writers []io.Writer
+	var files closers
+	for _, out := range opts.Outputs {
+		var w io.Writer
+		switch out {
+		case OutputStderr:
+			w = os.Stderr
+		case OutputStdout:
+			w = os.Stdout
+		default:
+			f, err := OpenRotatingFile(out, int64(opts.MaxSizeMB)<<20, opts.MaxBackups)
+			if err != nil {
+				files.Close()
+				return nil, err
+			}
+			files = append(files, f)
+			w = f
+		}
+		switch opts.Format {
+		case FormatJSON:
+		case FormatConsole, "":
+			w = zerolog.ConsoleWriter{Out: w, NoColor: true}
+		default:
+			files.Close()
+			return nil, fmt.Errorf("unknown log format %q", opts.Format)
+		}
+		writers = append(writers, w)
+	}
+
+	var w io.Writer = writers[0]
+	if len(writers) > 1 {
+		w = zerolog.MultiLevelWriter(writers...)
+	}
+	Log = zerolog.New(NewRedactingWriter(w, redaction)).With().Timestamp().Caller().Logger() // -> Adds caller details
+	return files, nil
+}
+
+var levels = map[string]zerolog.Level{
+	"debug": zerolog.DebugLevel,
+	"info":  zerolog.InfoLevel,
+	"warn":  zerolog.WarnLevel,
+	"error": zerolog.ErrorLevel,
+}
 
-	logLevel := GetLogLevel()
-	fmt.Println("Logging Level:", logLevel)
-	switch logLevel {
-	case "Info":
-		zerolog.SetGlobalLevel(zerolog.InfoLevel)
-		fmt.Println("Info Level:", logLevel)
-	case "Warn":
-		zerolog.SetGlobalLevel(zerolog.WarnLevel)
-		fmt.Println("Warn Level:", logLevel)
-	case "Error":
-		fmt.Println("Error Level:", logLevel)
-		zerolog.SetGlobalLevel(zerolog.ErrorLevel)
-	case "Debug":
-		zerolog.SetGlobalLevel(zerolog.DebugLevel)
-		fmt.Println("Debug Level:", logLevel)
-	default:
-		zerolog.SetGlobalLevel(zerolog.InfoLevel)
-		fmt.Println("default Level:", logLevel)
-	}
-	Log = zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr, NoColor: true}).With().Timestamp().Caller().Logger() // -> Adds caller details
+// SetLevel sets the global logging level to one of Debug, Info, Warn or Error. It
+// is safe to call while other goroutines log.
+func SetLevel(logLevel string) error {
+	level, ok := levels[strings.ToLower(logLevel)]
+	if !ok {
+		return fmt.Errorf("unknown log level %q", logLevel)
+	}
+	zerolog.SetGlobalLevel(level)
+	return nil
+}
+
+// GetLevel returns the global logging level as accepted by SetLevel.
+func GetLevel() string {
+	for name, level := range levels {
+		if level == zerolog.GlobalLevel() {
+			return strings.ToUpper(name[:1]) + name[1:]
+		}
+	}
+	return zerolog.GlobalLevel().String()
 }
 
-func GetLogLevel() string {
-	return os.Getenv("LOG_LEVEL")
+type closers []io.Closer
+
+func (c closers) Close() error {
+	var errs []error
+	for _, f := range c {
+		errs = append(errs, f.Close())
+	}
+	return errors.Join(errs...)
 }
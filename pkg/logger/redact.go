@@ -0,0 +1,218 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// Class is the sensitivity of a log field.
+type Class string
+
+const (
+	// ClassPublic fields are written as they are.
+	ClassPublic Class = "public"
+	// ClassPII fields identify a person, such as names and email addresses.
+	ClassPII Class = "pii"
+	// ClassCompensation fields hold salaries and other pay data.
+	ClassCompensation Class = "compensation"
+)
+
+// RedactAction is what happens to a classified field before it reaches a sink.
+type RedactAction string
+
+const (
+	RedactAllow RedactAction = "allow"
+	RedactMask  RedactAction = "mask"
+	RedactHash  RedactAction = "hash"
+	RedactDrop  RedactAction = "drop"
+)
+
+// maskedValue replaces the value of masked fields.
+const maskedValue = "[REDACTED]"
+
+// RedactionPolicy maps each sensitive class to the action applied to its fields.
+type RedactionPolicy struct {
+	PII          RedactAction
+	Compensation RedactAction
+}
+
+// DefaultRedaction hashes personal data, so lines about the same person can still
+// be correlated, and drops compensation entirely.
+var DefaultRedaction = RedactionPolicy{PII: RedactHash, Compensation: RedactDrop}
+
+// Validate checks the actions of p. Compensation may only be masked or dropped:
+// salaries have so few distinct values that their hashes are trivially reversed.
+func (p RedactionPolicy) Validate() error {
+	switch p.PII {
+	case RedactAllow, RedactMask, RedactHash, RedactDrop:
+	default:
+		return fmt.Errorf("unknown PII redaction %q", p.PII)
+	}
+	switch p.Compensation {
+	case RedactMask, RedactDrop:
+	default:
+		return fmt.Errorf("compensation redaction must be %s or %s, got %q", RedactMask, RedactDrop, p.Compensation)
+	}
+	return nil
+}
+
+func (p RedactionPolicy) action(c Class) RedactAction {
+	switch c {
+	case ClassPII:
+		return p.PII
+	case ClassCompensation:
+		return p.Compensation
+	}
+	return RedactAllow
+}
+
+var (
+	classMutex sync.RWMutex
+	// fieldClasses classifies log fields by their lowercase key.
+	fieldClasses = map[string]Class{
+		"name":     ClassPII,
+		"email":    ClassPII,
+		"phone":    ClassPII,
+		"address":  ClassPII,
+		"salary":   ClassCompensation,
+		"amount":   ClassCompensation,
+		"bonus":    ClassCompensation,
+		"currency": ClassPublic,
+	}
+)
+
+// ClassifyField sets the class of every log field named key, at any nesting depth.
+func ClassifyField(key string, c Class) {
+	classMutex.Lock()
+	defer classMutex.Unlock()
+	fieldClasses[strings.ToLower(key)] = c
+}
+
+// FieldClass returns the class of the log field key. Keys containing salary or
+// compensation are always compensation, whatever else they contain.
+func FieldClass(key string) Class {
+	k := strings.ToLower(key)
+	if strings.Contains(k, "salary") || strings.Contains(k, "compensation") {
+		return ClassCompensation
+	}
+	classMutex.RLock()
+	defer classMutex.RUnlock()
+	if c, ok := fieldClasses[k]; ok {
+		return c
+	}
+	return ClassPublic
+}
+
+// RedactingWriter applies a RedactionPolicy to every JSON log event before passing
+// it on. It sits between the zerolog logger and the formatting and sinks, so no
+// sink ever receives an unredacted event. Events that cannot be parsed are replaced
+// by an error event rather than written as they are.
+type RedactingWriter struct {
+	next   io.Writer
+	policy RedactionPolicy
+}
+
+// NewRedactingWriter creates a RedactingWriter writing the redacted events to next.
+//
+// It returns a pointer to the newly created RedactingWriter struct.
+func NewRedactingWriter(next io.Writer, policy RedactionPolicy) *RedactingWriter {
+	return &RedactingWriter{next: next, policy: policy}
+}
+
+func (r *RedactingWriter) Write(p []byte) (int, error) {
+	redacted, err := r.redact(bytes.TrimRight(p, "\n"))
+	if err != nil {
+		redacted = fmt.Appendf(nil, `{%q:"error",%q:"log event dropped by redaction: unparseable"}`,
+			zerolog.LevelFieldName, zerolog.MessageFieldName)
+	}
+	if _, err := r.next.Write(append(redacted, '\n')); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// redact rewrites a JSON object, keeping the order of its fields.
+func (r *RedactingWriter) redact(object []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(object))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("not a JSON object")
+	}
+
+	var out bytes.Buffer
+	out.WriteByte('{')
+	first := true
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		switch r.policy.action(FieldClass(key)) {
+		case RedactDrop:
+			continue
+		case RedactMask:
+			value = json.RawMessage(`"` + maskedValue + `"`)
+		case RedactHash:
+			sum := sha256.Sum256(value)
+			value = json.RawMessage(`"sha256:` + hex.EncodeToString(sum[:6]) + `"`)
+		default:
+			if value, err = r.redactValue(value); err != nil {
+				return nil, err
+			}
+		}
+
+		if !first {
+			out.WriteByte(',')
+		}
+		first = false
+		k, _ := json.Marshal(key)
+		out.Write(k)
+		out.WriteByte(':')
+		out.Write(value)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	out.WriteByte('}')
+	return out.Bytes(), nil
+}
+
+// redactValue redacts the objects nested in value.
+func (r *RedactingWriter) redactValue(value json.RawMessage) (json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(value)
+	if len(trimmed) == 0 {
+		return value, nil
+	}
+	switch trimmed[0] {
+	case '{':
+		return r.redact(trimmed)
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+		for i, item := range items {
+			redacted, err := r.redactValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = redacted
+		}
+		return json.Marshal(items)
+	}
+	return value, nil
+}
@@ -0,0 +1,86 @@
+package logger_test
+
+import (
+	"employee/pkg/logger"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RotatingFile", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "app.log")
+	})
+
+	It("should rotate once the maximum size is reached and keep the configured backups", func() {
+		f, err := logger.OpenRotatingFile(path, 10, 2)
+		Expect(err).To(BeNil())
+		for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+			_, err := f.Write([]byte(line))
+			Expect(err).To(BeNil())
+		}
+		Expect(f.Close()).To(Succeed())
+
+		Expect(os.ReadFile(path)).To(Equal([]byte("fourth\n")))
+		Expect(os.ReadFile(path + ".1")).To(Equal([]byte("third\n")))
+		Expect(os.ReadFile(path + ".2")).To(Equal([]byte("second\n")))
+		Expect(path + ".3").NotTo(BeAnExistingFile())
+	})
+
+	It("should keep appending to an existing file", func() {
+		Expect(os.WriteFile(path, []byte("old\n"), 0o600)).To(Succeed())
+		f, err := logger.OpenRotatingFile(path, 0, 0)
+		Expect(err).To(BeNil())
+		_, err = f.Write([]byte("new\n"))
+		Expect(err).To(BeNil())
+		Expect(f.Close()).To(Succeed())
+		Expect(os.ReadFile(path)).To(Equal([]byte("old\nnew\n")))
+	})
+})
+
+var _ = Describe("Init", func() {
+	previous := logger.Log
+	AfterEach(func() {
+		logger.Log = previous
+		Expect(logger.SetLevel("Info")).To(Succeed())
+	})
+
+	It("should write JSON to a file sink at the configured level", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "app.log")
+		closer, err := logger.Init(logger.Options{Level: "Warn", Format: logger.FormatJSON, Outputs: []string{path}})
+		Expect(err).To(BeNil())
+		logger.Log.Info().Msg("hidden")
+		logger.Log.Warn().Str("k", "v").Msg("shown")
+		Expect(closer.Close()).To(Succeed())
+
+		data, err := os.ReadFile(path)
+		Expect(err).To(BeNil())
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		Expect(lines).To(HaveLen(1))
+		var entry map[string]any
+		Expect(json.Unmarshal([]byte(lines[0]), &entry)).To(Succeed())
+		Expect(entry).To(HaveKeyWithValue("msg", "shown"))
+		Expect(entry).To(HaveKeyWithValue("lvl", "warn"))
+		Expect(entry).To(HaveKeyWithValue("k", "v"))
+	})
+
+	It("should reject unknown levels and formats", func() {
+		_, err := logger.Init(logger.Options{Level: "Verbose"})
+		Expect(err).NotTo(BeNil())
+		_, err = logger.Init(logger.Options{Level: "Info", Format: "xml"})
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should change the level at runtime", func() {
+		Expect(logger.SetLevel("debug")).To(Succeed())
+		Expect(logger.GetLevel()).To(Equal("Debug"))
+		Expect(logger.SetLevel("loud")).NotTo(Succeed())
+		Expect(logger.GetLevel()).To(Equal("Debug"))
+	})
+})
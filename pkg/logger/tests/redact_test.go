@@ -0,0 +1,76 @@
+package logger_test
+
+import (
+	"bytes"
+	"employee/pkg/logger"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Redaction", func() {
+	var buf *bytes.Buffer
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+	})
+
+	log := func(policy logger.RedactionPolicy) zerolog.Logger {
+		return zerolog.New(logger.NewRedactingWriter(buf, policy))
+	}
+
+	It("should drop compensation and hash personal data by default, keeping field order", func() {
+		l := log(logger.DefaultRedaction)
+		l.Info().Int("id", 7).Str("name", "Ada").Str("position", "Engineer").Float64("salary", 123456.78).Msg("created")
+
+		Expect(buf.String()).To(MatchRegexp(`^\{"lvl":"info","id":7,"name":"sha256:[0-9a-f]{12}","position":"Engineer","msg":"created"\}\n$`))
+	})
+
+	It("should mask when configured", func() {
+		l := log(logger.RedactionPolicy{PII: logger.RedactMask, Compensation: logger.RedactMask})
+		l.Info().Str("email", "ada@example.com").Float64("newSalary", 5).Msg("x")
+
+		Expect(buf.String()).To(Equal(`{"lvl":"info","email":"[REDACTED]","newSalary":"[REDACTED]","msg":"x"}` + "\n"))
+	})
+
+	It("should redact nested objects and arrays", func() {
+		l := log(logger.DefaultRedaction)
+		l.Info().Interface("employees", []map[string]any{{"id": 1, "salary": 10}, {"id": 2, "salary": 20}}).Msg("batch")
+
+		Expect(buf.String()).To(Equal(`{"lvl":"info","employees":[{"id":1},{"id":2}],"msg":"batch"}` + "\n"))
+	})
+
+	It("should hash the same value to the same digest", func() {
+		l := log(logger.DefaultRedaction)
+		l.Info().Str("name", "Ada").Msg("a")
+		l.Info().Str("name", "Ada").Msg("b")
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		Expect(lines[0][:50]).To(Equal(lines[1][:50]))
+	})
+
+	It("should honour fields classified at runtime", func() {
+		previous := logger.FieldClass("taxId")
+		DeferCleanup(logger.ClassifyField, "taxId", previous)
+		logger.ClassifyField("taxId", logger.ClassPII)
+		l := log(logger.RedactionPolicy{PII: logger.RedactDrop, Compensation: logger.RedactDrop})
+		l.Info().Str("taxid", "123").Msg("x")
+
+		Expect(buf.String()).To(Equal(`{"lvl":"info","msg":"x"}` + "\n"))
+	})
+
+	It("should replace events it cannot parse", func() {
+		w := logger.NewRedactingWriter(buf, logger.DefaultRedaction)
+		_, err := w.Write([]byte(`salary=100`))
+		Expect(err).To(BeNil())
+		Expect(buf.String()).NotTo(ContainSubstring("100"))
+		Expect(buf.String()).To(ContainSubstring(`"` + zerolog.MessageFieldName + `":"log event dropped`))
+	})
+
+	It("should refuse to hash or allow compensation", func() {
+		Expect(logger.RedactionPolicy{PII: logger.RedactHash, Compensation: logger.RedactHash}.Validate()).NotTo(Succeed())
+		Expect(logger.RedactionPolicy{PII: logger.RedactHash, Compensation: logger.RedactAllow}.Validate()).NotTo(Succeed())
+		Expect(logger.DefaultRedaction.Validate()).To(Succeed())
+	})
+})
@@ -0,0 +1,69 @@
+package metrics_test
+
+import (
+	"employee/pkg/metrics"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Registry", func() {
+	var reg *metrics.Registry
+
+	BeforeEach(func() {
+		reg = metrics.NewRegistry()
+	})
+
+	text := func() string {
+		var b strings.Builder
+		_, err := reg.WriteTo(&b)
+		Expect(err).To(BeNil())
+		return b.String()
+	}
+
+	It("should write counters with escaped labels in label order", func() {
+		c := reg.NewCounterVec("requests_total", "Requests.", "path")
+		c.WithLabelValues(`/b"\`).Add(2)
+		c.WithLabelValues("/a").Inc()
+
+		Expect(text()).To(Equal(`# HELP requests_total Requests.
+# TYPE requests_total counter
+requests_total{path="/a"} 1
+requests_total{path="/b\"\\"} 2
+`))
+	})
+
+	It("should write cumulative histogram buckets", func() {
+		h := reg.NewHistogramVec("latency_seconds", "Latency.", []float64{0.1, 1}, "method")
+		h.WithLabelValues("GET").Observe(0.05)
+		h.WithLabelValues("GET").Observe(0.5)
+		h.WithLabelValues("GET").Observe(3)
+
+		Expect(text()).To(Equal(`# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{method="GET",le="0.1"} 1
+latency_seconds_bucket{method="GET",le="1"} 2
+latency_seconds_bucket{method="GET",le="+Inf"} 3
+latency_seconds_sum{method="GET"} 3.55
+latency_seconds_count{method="GET"} 3
+`))
+	})
+
+	It("should read function metrics on every scrape", func() {
+		items := 1.0
+		reg.NewGaugeFunc("items", "Items.", nil, func() []metrics.Sample {
+			return []metrics.Sample{{Value: items}}
+		})
+		Expect(text()).To(ContainSubstring("\nitems 1\n"))
+		items = 4
+		Expect(text()).To(ContainSubstring("\nitems 4\n"))
+	})
+
+	It("should reject duplicate metric names and wrong label counts", func() {
+		c := reg.NewCounterVec("requests_total", "Requests.", "path")
+		Expect(func() { reg.NewCounterVec("requests_total", "Requests.") }).To(Panic())
+		Expect(func() { c.WithLabelValues("a", "b") }).To(Panic())
+		Expect(func() { c.WithLabelValues("a").Add(-1) }).To(Panic())
+	})
+})
@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"bufio"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter is a value that only goes up.
+type Counter struct {
+	bits atomic.Uint64
+}
+
+// Inc adds one to the counter.
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds v, which must not be negative, to the counter.
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		panic("metrics: counter cannot decrease")
+	}
+	for {
+		old := c.bits.Load()
+		if c.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+// Value returns the current count.
+func (c *Counter) Value() float64 {
+	return math.Float64frombits(c.bits.Load())
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	family
+	vec[Counter]
+}
+
+// NewCounterVec registers a counter with the given label names.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{
+		family: family{name: name, help: help, kind: "counter", labels: labels},
+		vec:    newVec(func() *Counter { return &Counter{} }),
+	}
+	r.register(name, c)
+	return c
+}
+
+// WithLabelValues returns the counter for the given label values.
+func (c *CounterVec) WithLabelValues(values ...string) *Counter {
+	c.checkValues(values)
+	return c.get(values)
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	c.header(w)
+	c.each(func(values []string, counter *Counter) {
+		c.series(w, "", values, "", counter.Value())
+	})
+}
+
+// Histogram counts observations in cumulative buckets.
+type Histogram struct {
+	mutex   sync.Mutex
+	bounds  []float64
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// Observe records v.
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.bounds, v)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if i < len(h.buckets) {
+		h.buckets[i]++
+	}
+	h.count++
+	h.sum += v
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	family
+	vec[Histogram]
+	bounds []float64
+}
+
+// NewHistogramVec registers a histogram with the given upper bounds and label names.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	bounds := append([]float64{}, buckets...)
+	sort.Float64s(bounds)
+	h := &HistogramVec{
+		family: family{name: name, help: help, kind: "histogram", labels: labels},
+		bounds: bounds,
+	}
+	h.vec = newVec(func() *Histogram {
+		return &Histogram{bounds: bounds, buckets: make([]uint64, len(bounds))}
+	})
+	r.register(name, h)
+	return h
+}
+
+// WithLabelValues returns the histogram for the given label values.
+func (h *HistogramVec) WithLabelValues(values ...string) *Histogram {
+	h.checkValues(values)
+	return h.get(values)
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	h.header(w)
+	h.each(func(values []string, hist *Histogram) {
+		hist.mutex.Lock()
+		buckets, count, sum := append([]uint64{}, hist.buckets...), hist.count, hist.sum
+		hist.mutex.Unlock()
+
+		var cumulative uint64
+		for i, bound := range h.bounds {
+			cumulative += buckets[i]
+			h.series(w, "_bucket", values, `le="`+formatFloat(bound)+`"`, float64(cumulative))
+		}
+		h.series(w, "_bucket", values, `le="+Inf"`, float64(count))
+		h.series(w, "_sum", values, "", sum)
+		h.series(w, "_count", values, "", float64(count))
+	})
+}
+
+// Sample is a value read by a function metric at scrape time.
+type Sample struct {
+	Value       float64
+	LabelValues []string
+}
+
+// funcMetric reads its samples from a function on every scrape.
+type funcMetric struct {
+	family
+	collect func() []Sample
+}
+
+// NewGaugeFunc registers a gauge whose samples are returned by collect on every scrape.
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, collect func() []Sample) {
+	r.register(name, &funcMetric{family: family{name: name, help: help, kind: "gauge", labels: labels}, collect: collect})
+}
+
+// NewCounterFunc is like NewGaugeFunc for values that only go up.
+func (r *Registry) NewCounterFunc(name, help string, labels []string, collect func() []Sample) {
+	r.register(name, &funcMetric{family: family{name: name, help: help, kind: "counter", labels: labels}, collect: collect})
+}
+
+func (f *funcMetric) write(w *bufio.Writer) {
+	f.header(w)
+	for _, s := range f.collect() {
+		f.checkValues(s.LabelValues)
+		f.series(w, "", s.LabelValues, "", s.Value)
+	}
+}
@@ -0,0 +1,48 @@
+package ratelimit_test
+
+import (
+	"employee/pkg/ratelimit"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Limiter", func() {
+	var (
+		l   *ratelimit.Limiter
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Unix(1700000000, 0)
+		l = ratelimit.NewLimiter(ratelimit.Limit{Rate: 1, Burst: 2})
+		l.SetClock(func() time.Time { return now })
+	})
+
+	It("should allow a burst and then reject", func() {
+		Expect(l.Allow("a").Allowed).To(BeTrue())
+		res := l.Allow("a")
+		Expect(res.Allowed).To(BeTrue())
+		Expect(res.Remaining).To(Equal(0))
+
+		res = l.Allow("a")
+		Expect(res.Allowed).To(BeFalse())
+		Expect(res.RetryAfter).To(Equal(time.Second))
+		Expect(res.Reset).To(Equal(2 * time.Second))
+	})
+
+	It("should refill tokens over time", func() {
+		l.Allow("a")
+		l.Allow("a")
+		now = now.Add(time.Second)
+		Expect(l.Allow("a").Allowed).To(BeTrue())
+	})
+
+	It("should keep separate buckets per key", func() {
+		l.Allow("a")
+		l.Allow("a")
+		Expect(l.Allow("a").Allowed).To(BeFalse())
+		Expect(l.Allow("b").Allowed).To(BeTrue())
+	})
+})
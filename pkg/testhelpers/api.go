@@ -0,0 +1,42 @@
+package testhelpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIClient sends requests to a router with bearer tokens signed by Secret.
+type APIClient struct {
+	Router  *gin.Engine
+	Secret  []byte
+	Subject string
+}
+
+// Token returns the Authorization header of Subject holding role, valid for one hour.
+func (c *APIClient) Token(role string) string {
+	return "Bearer " + SignHS256(c.Secret, "", map[string]any{
+		"sub": c.Subject, "roles": []string{role}, "exp": time.Now().Add(time.Hour).Unix(),
+	})
+}
+
+// Do serves a request made with the token of role and decodes the JSON response
+// into out, unless out is nil. It returns false when the status is not code or the
+// response does not decode.
+func (c *APIClient) Do(method, url, body, role string, code int, out any) bool {
+	req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+	if err != nil {
+		return false
+	}
+	req.Header.Set("Authorization", c.Token(role))
+	return TestHTTPResponse(c.Router, req, func(w *httptest.ResponseRecorder) bool {
+		if w.Code != code {
+			return false
+		}
+		return out == nil || json.Unmarshal(w.Body.Bytes(), out) == nil
+	})
+}
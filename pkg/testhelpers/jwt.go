@@ -0,0 +1,40 @@
+package testhelpers
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// SignHS256 builds a compact JWT signed with the given HMAC secret.
+func SignHS256(secret []byte, kid string, claims map[string]any) string {
+	input := signingInput("HS256", kid, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignRS256 builds a compact JWT signed with the given RSA private key.
+func SignRS256(key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	input := signingInput("RS256", kid, claims)
+	sum := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	if err != nil {
+		panic(err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func signingInput(alg, kid string, claims map[string]any) string {
+	hdr := map[string]string{"alg": alg, "typ": "JWT"}
+	if kid != "" {
+		hdr["kid"] = kid
+	}
+	h, _ := json.Marshal(hdr)
+	c, _ := json.Marshal(claims)
+	return base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+}
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// WriterExporter writes every span as one JSON line to an io.Writer.
+type WriterExporter struct {
+	mutex sync.Mutex
+	enc   *json.Encoder
+	close func() error
+}
+
+// NewWriterExporter creates an exporter writing to w, such as os.Stdout.
+//
+// It returns a pointer to the newly created WriterExporter struct.
+func NewWriterExporter(w io.Writer) *WriterExporter {
+	return &WriterExporter{enc: json.NewEncoder(w), close: func() error { return nil }}
+}
+
+// NewFileExporter creates an exporter appending to the file at path.
+func NewFileExporter(path string) (*WriterExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	e := NewWriterExporter(f)
+	e.close = f.Close
+	return e, nil
+}
+
+func (e *WriterExporter) Export(span SpanData) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.enc.Encode(span)
+}
+
+// Close closes the underlying file, if any.
+func (e *WriterExporter) Close() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.close()
+}
+
+// MemoryExporter keeps the exported spans for inspection in tests.
+type MemoryExporter struct {
+	mutex sync.Mutex
+	spans []SpanData
+}
+
+// NewMemoryExporter creates an empty MemoryExporter.
+func NewMemoryExporter() *MemoryExporter {
+	return &MemoryExporter{}
+}
+
+func (e *MemoryExporter) Export(span SpanData) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+// Spans returns the exported spans in the order they ended.
+func (e *MemoryExporter) Spans() []SpanData {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return append([]SpanData{}, e.spans...)
+}
+
+// Reset discards the exported spans.
+func (e *MemoryExporter) Reset() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.spans = nil
+}
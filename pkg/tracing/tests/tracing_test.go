@@ -0,0 +1,102 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"employee/pkg/tracing"
+	"encoding/json"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Traceparent", func() {
+	It("should round trip a valid header", func() {
+		const header = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+		sc, err := tracing.ParseTraceparent(header)
+		Expect(err).To(BeNil())
+		Expect(sc.TraceID.String()).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+		Expect(sc.SpanID.String()).To(Equal("00f067aa0ba902b7"))
+		Expect(sc.Sampled).To(BeTrue())
+		Expect(sc.Traceparent()).To(Equal(header))
+	})
+
+	DescribeTable("should reject malformed headers",
+		func(header string) {
+			_, err := tracing.ParseTraceparent(header)
+			Expect(err).NotTo(BeNil())
+		},
+		Entry("empty", ""),
+		Entry("zero trace ID", "00-00000000000000000000000000000000-00f067aa0ba902b7-01"),
+		Entry("zero span ID", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"),
+		Entry("uppercase", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"),
+		Entry("invalid version", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"),
+		Entry("extra fields in version 00", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-xx"),
+		Entry("short span ID", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa-01"),
+	)
+})
+
+var _ = Describe("Tracer", func() {
+	var (
+		exporter *tracing.MemoryExporter
+		tracer   *tracing.Tracer
+	)
+
+	BeforeEach(func() {
+		exporter = tracing.NewMemoryExporter()
+		tracer = tracing.NewTracer(exporter)
+	})
+
+	It("should link children to their parent", func() {
+		ctx, parent := tracer.Start(context.Background(), "parent")
+		_, child := tracer.Start(ctx, "child")
+		child.SetError(errors.New("boom"))
+		child.End()
+		parent.End()
+		parent.End()
+
+		spans := exporter.Spans()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name).To(Equal("child"))
+		Expect(spans[0].TraceID).To(Equal(spans[1].TraceID))
+		Expect(spans[0].ParentID).To(Equal(spans[1].SpanID))
+		Expect(spans[0].Error).To(Equal("boom"))
+		Expect(spans[1].ParentID).To(BeEmpty())
+	})
+
+	It("should continue a remote trace and honour its sampling decision", func() {
+		sc, err := tracing.ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		Expect(err).To(BeNil())
+		_, span := tracer.Start(tracing.ContextWithRemoteParent(context.Background(), sc), "server")
+		span.End()
+		Expect(exporter.Spans()[0].TraceID).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+		Expect(exporter.Spans()[0].ParentID).To(Equal("00f067aa0ba902b7"))
+
+		sc.Sampled = false
+		_, span = tracer.Start(tracing.ContextWithRemoteParent(context.Background(), sc), "server")
+		span.End()
+		Expect(exporter.Spans()).To(HaveLen(1))
+	})
+
+	It("should do nothing without a tracer", func() {
+		tracing.SetTracer(nil)
+		ctx, span := tracing.Start(context.Background(), "noop")
+		Expect(span).To(BeNil())
+		span.SetAttribute("key", "value")
+		span.End()
+		Expect(tracing.SpanFromContext(ctx)).To(BeNil())
+	})
+
+	It("should write spans as JSON lines", func() {
+		var buf bytes.Buffer
+		_, span := tracing.NewTracer(tracing.NewWriterExporter(&buf)).Start(context.Background(), "op")
+		span.SetAttribute("employee.id", 7)
+		span.End()
+
+		var data tracing.SpanData
+		Expect(json.Unmarshal(buf.Bytes(), &data)).To(Succeed())
+		Expect(data.Name).To(Equal("op"))
+		Expect(data.Attributes).To(Equal([]tracing.Attribute{{Key: "employee.id", Value: "7"}}))
+	})
+})
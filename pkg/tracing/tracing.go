@@ -0,0 +1,172 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Attribute is a key value pair describing a span.
+type Attribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// SpanData is the finished span handed to the exporter.
+type SpanData struct {
+	Name       string      `json:"name"`
+	TraceID    string      `json:"trace_id"`
+	SpanID     string      `json:"span_id"`
+	ParentID   string      `json:"parent_id,omitempty"`
+	Start      time.Time   `json:"start"`
+	End        time.Time   `json:"end"`
+	Attributes []Attribute `json:"attributes,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// Duration returns how long the span lasted.
+func (d SpanData) Duration() time.Duration {
+	return d.End.Sub(d.Start)
+}
+
+// Exporter receives every sampled span once it ends.
+type Exporter interface {
+	Export(span SpanData)
+}
+
+// Span is an operation in progress. A nil Span ignores every call, so callers
+// never have to check whether tracing is enabled.
+type Span struct {
+	tracer *Tracer
+	sc     SpanContext
+	mutex  sync.Mutex
+	data   SpanData
+	ended  bool
+}
+
+// SpanContext returns the identity of the span to propagate to callees.
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+// SetAttribute records a key value pair on the span.
+func (s *Span) SetAttribute(key string, value any) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.data.Attributes = append(s.data.Attributes, Attribute{Key: key, Value: fmt.Sprint(value)})
+}
+
+// SetError marks the span as failed with err. A nil err is ignored.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.data.Error = err.Error()
+}
+
+// End finishes the span and exports it when it is sampled. Later calls are ignored.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	if s.ended {
+		s.mutex.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = s.tracer.now()
+	data := s.data
+	s.mutex.Unlock()
+	if s.sc.Sampled {
+		s.tracer.exporter.Export(data)
+	}
+}
+
+// Tracer creates spans and hands them to its exporter.
+type Tracer struct {
+	exporter Exporter
+	now      func() time.Time
+}
+
+// NewTracer creates a Tracer exporting to e.
+//
+// It returns a pointer to the newly created Tracer struct.
+func NewTracer(e Exporter) *Tracer {
+	return &Tracer{exporter: e, now: time.Now}
+}
+
+// Start begins a span named name as a child of the span in ctx, or of the remote
+// parent set by ContextWithRemoteParent, or as the root of a new trace.
+//
+// It returns a context carrying the new span and the span itself.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	sc := SpanContext{SpanID: newSpanID(), Sampled: true}
+	var parentID SpanID
+	if parent := spanContextFrom(ctx); parent.IsValid() {
+		sc.TraceID, sc.Sampled, parentID = parent.TraceID, parent.Sampled, parent.SpanID
+	} else {
+		sc.TraceID = newTraceID()
+	}
+
+	span := &Span{tracer: t, sc: sc, data: SpanData{
+		Name:    name,
+		TraceID: sc.TraceID.String(),
+		SpanID:  sc.SpanID.String(),
+		Start:   t.now(),
+	}}
+	if parentID.IsValid() {
+		span.data.ParentID = parentID.String()
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+type spanKey struct{}
+
+type remoteKey struct{}
+
+// ContextWithRemoteParent makes sc, received from another process, the parent of
+// the next span started from the returned context.
+func ContextWithRemoteParent(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// SpanFromContext returns the current span of ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+func spanContextFrom(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.sc
+	}
+	sc, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return sc
+}
+
+var global atomic.Pointer[Tracer]
+
+// SetTracer makes t the tracer used by Start. A nil t disables tracing.
+func SetTracer(t *Tracer) {
+	global.Store(t)
+}
+
+// Start begins a span on the tracer set by SetTracer. Without one it returns ctx
+// unchanged and a nil span.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return global.Load().Start(ctx, name)
+}
@@ -1,13 +1,219 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"employee/logic/apikey"
+	"employee/logic/audit"
+	"employee/logic/authz"
+	"employee/logic/employee"
+	"employee/pkg/auth"
+	"employee/pkg/config"
+	"employee/pkg/currency"
+	"employee/pkg/logger"
+	"employee/pkg/metrics"
+	"employee/pkg/ratelimit"
+	"employee/pkg/tracing"
+	"employee/service/health"
 	"employee/service/router"
+	"employee/service/server"
+	"employee/service/storage"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
+	// Load the configuration
+	loaded, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n%s", os.Args[0], config.Usage())
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid configuration:", err)
+		os.Exit(2)
+	}
+	cfg := loaded.Config
+	if loaded.Print {
+		if err := cfg.Print(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	logs, err := logger.Init(logger.Options{
+		Level:      cfg.Logging.Level,
+		Format:     cfg.Logging.Format,
+		Outputs:    cfg.Logging.Outputs(),
+		MaxSizeMB:  cfg.Logging.MaxSizeMB,
+		MaxBackups: cfg.Logging.MaxBackups,
+		Redaction: &logger.RedactionPolicy{
+			PII:          logger.RedactAction(cfg.Logging.RedactPII),
+			Compensation: logger.RedactAction(cfg.Logging.RedactCompensation),
+		},
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to set up logging:", err)
+		os.Exit(2)
+	}
+
+	// Export spans when tracing is enabled
+	switch cfg.Tracing.Exporter {
+	case config.TracingStdout:
+		tracing.SetTracer(tracing.NewTracer(tracing.NewWriterExporter(os.Stdout)))
+	case config.TracingFile:
+		exporter, err := tracing.NewFileExporter(cfg.Tracing.File)
+		if err != nil {
+			logger.Log.Fatal().Err(err).Msg("Failed to open the trace file")
+		}
+		defer exporter.Close()
+		tracing.SetTracer(tracing.NewTracer(exporter))
+	}
+
+	// Create the storage, its journals are replayed once the server is listening
+	store := storage.New(cfg.Storage)
+	checker := health.NewChecker(cfg.Health.Timeout)
+	checker.AddLivenessCheck("storage", store.Alive)
+	checker.AddReadinessCheck("storage", store.Check)
+	for _, dep := range cfg.Health.DependencyURLs() {
+		checker.AddReadinessCheck(dep, health.HTTPCheck(&http.Client{}, dep))
+	}
+	trail := audit.NewTrailWithDB(store.Audit)
+	// A broken trail refuses new entries, so every mutation fails until it is repaired
+	checker.AddReadinessCheck("audit_trail", trail.Check)
+	emp := employee.NewEmployeeWithDB(store.Employees)
+	emp.SetPageSizes(cfg.Limits.DefaultPageSize, cfg.Limits.MaxPageSize)
+	emp.SetSalaryDB(store.Salaries)
+	emp.SetVersionDB(store.Versions)
+	emp.SetDepartmentDB(store.Departments)
+	emp.SetPositionDB(store.Positions)
+	var rates *currency.Table
+	if cfg.Currency.RatesFile != "" {
+		if rates, err = currency.LoadRatesFile(cfg.Currency.RatesFile); err != nil {
+			logger.Log.Fatal().Err(err).Msg("Failed to load exchange rates")
+		}
+	}
+	emp.SetCurrencies(cfg.Currency.Default, rates)
+	emp.SetAuditTrail(trail)
+
+	opts := []router.Option{router.WithEmployees(emp), router.WithAudit(trail), router.WithHealth(checker),
+		router.WithTrustedProxies(cfg.Server.TrustedProxyList())}
+	if cfg.Metrics.Enabled {
+		reg := metrics.NewRegistry()
+		store.RegisterMetrics(reg)
+		opts = append(opts, router.WithMetrics(reg))
+	}
+	if cfg.Limits.AddressRate > 0 {
+		opts = append(opts, router.WithAddressRateLimit(ratelimit.Limit{Rate: cfg.Limits.AddressRate, Burst: cfg.Limits.AddressBurst}))
+	}
+	if cfg.Limits.ReadRate > 0 || cfg.Limits.WriteRate > 0 {
+		opts = append(opts, router.WithRateLimits(
+			ratelimit.Limit{Rate: cfg.Limits.ReadRate, Burst: cfg.Limits.ReadBurst},
+			ratelimit.Limit{Rate: cfg.Limits.WriteRate, Burst: cfg.Limits.WriteBurst},
+		))
+	}
+
+	// Enable authentication when a token verification key, API keys or client certificates are configured
+	clientCerts := cfg.TLS.ClientAuth != config.ClientAuthNone
+	if cfg.Auth.Enabled() || cfg.Auth.APIKeysEnabled() || clientCerts {
+		if cfg.Auth.Enabled() {
+			verifier, err := auth.NewVerifier(auth.Config{
+				JWKSFile:         cfg.Auth.JWKSFile,
+				HMACSecret:       []byte(cfg.Auth.HMACSecret),
+				RSAPublicKeyFile: cfg.Auth.RSAPublicKeyFile,
+				Issuer:           cfg.Auth.Issuer,
+				Audience:         cfg.Auth.Audience,
+				Leeway:           cfg.Auth.Leeway,
+			})
+			if err != nil {
+				logger.Log.Fatal().Err(err).Msg("Failed to configure authentication")
+			}
+			opts = append(opts, router.WithAuthentication(verifier))
+		}
+		if cfg.Auth.APIKeysEnabled() {
+			opts = append(opts, router.WithAPIKeys(apikey.NewManagerWithDB(store.APIKeys)))
+		}
+		if clientCerts {
+			opts = append(opts, router.WithClientCertificates())
+		}
+
+		// Authenticated callers are always subject to the role based policy
+		policy := authz.DefaultPolicy()
+		if cfg.Auth.PolicyFile != "" {
+			if policy, err = authz.LoadPolicyFile(cfg.Auth.PolicyFile); err != nil {
+				logger.Log.Fatal().Err(err).Msg("Failed to load authorization policy")
+			}
+		}
+		authorizer := authz.NewAuthorizer(policy)
+		authorizer.SetHierarchy(emp.ManagerOf)
+		opts = append(opts, router.WithAuthorization(authorizer))
+	} else {
+		logger.Log.Warn().Msg("No JWT verification key, API keys or client CA configured, authentication is disabled")
+	}
+
 	// Create router
-	router := router.NewRouter()
+	router := router.NewRouter(opts...)
+
+	// Serve until SIGINT or SIGTERM, then drain in-flight requests
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var tlsConfig *tls.Config
+	if cfg.TLS.Enabled {
+		certs, err := server.NewCertReloader(cfg.TLS)
+		if err != nil {
+			logger.Log.Fatal().Err(err).Msg("Failed to load TLS certificates")
+		}
+		go certs.Watch(ctx, cfg.TLS.ReloadInterval)
+		tlsConfig = certs.TLSConfig()
+		checker.AddReadinessCheck("tls_certificate", health.CertificateCheck(certs.Leaf))
+	}
+	srv := server.New(cfg.Server, tlsConfig, router)
+	srv.OnShutdown(checker.SetShuttingDown)
+
+	// Readiness fails until the journals are replayed, a failed replay stops the server
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	loadErr := make(chan error, 1)
+	go func() {
+		err := store.Load()
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to load storage")
+			cancel()
+		} else {
+			logger.Log.Info().Msg("Storage loaded")
+			if err := trail.Verify(ctx); err != nil {
+				logger.Log.Error().Err(err).Msg("Audit trail is broken, mutations are refused")
+			} else {
+				logger.Log.Info().Msg("Audit trail verified")
+			}
+			if cfg.Retention.Period > 0 {
+				go emp.RunPurge(ctx, cfg.Retention.Period, cfg.Retention.PurgeInterval)
+			}
+		}
+		loadErr <- err
+	}()
+	runErr := srv.Run(ctx)
+	if runErr != nil {
+		logger.Log.Error().Err(runErr).Msg("Server stopped with error")
+	}
+	if err := <-loadErr; err != nil && runErr == nil {
+		runErr = err
+	}
 
-	// Start server
-	router.Run("localhost:8080")
+	// Flush and close the storage only once no request can write to it anymore
+	closeErr := store.Close()
+	if closeErr != nil {
+		logger.Log.Error().Err(closeErr).Msg("Failed to close storage")
+	} else {
+		logger.Log.Info().Msg("Storage closed")
+	}
+	logs.Close()
+	if runErr != nil || closeErr != nil {
+		os.Exit(1)
+	}
 }
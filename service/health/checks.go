@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPCheck probes a dependency by URL and expects a 2xx response.
+func HTTPCheck(client *http.Client, url string) Check {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		return nil
+	}
+}
+
+// CertificateCheck fails once the certificate returned by leaf has expired.
+func CertificateCheck(leaf func() *x509.Certificate) Check {
+	return func(context.Context) error {
+		cert := leaf()
+		if cert == nil {
+			return errors.New("no certificate loaded")
+		}
+		if time.Now().After(cert.NotAfter) {
+			return fmt.Errorf("certificate expired at %s", cert.NotAfter.Format(time.RFC3339))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,136 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+var errShuttingDown = errors.New("server is shutting down")
+
+// Check returns nil when the component it probes is healthy.
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// CheckResult is the outcome of a single check.
+type CheckResult struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// Report is the JSON body of the health endpoints.
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Checker runs the liveness and readiness checks of the service.
+type Checker struct {
+	mutex        sync.RWMutex
+	liveness     []namedCheck
+	readiness    []namedCheck
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+// NewChecker creates a Checker giving each check up to timeout to complete.
+//
+// It returns a pointer to the newly created Checker struct.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// AddLivenessCheck registers a check that fails only when the process must be restarted.
+func (h *Checker) AddLivenessCheck(name string, c Check) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.liveness = append(h.liveness, namedCheck{name: name, check: c})
+}
+
+// AddReadinessCheck registers a check that fails while the service cannot take traffic.
+func (h *Checker) AddReadinessCheck(name string, c Check) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.readiness = append(h.readiness, namedCheck{name: name, check: c})
+}
+
+// SetShuttingDown makes readiness fail from now on, so load balancers stop sending
+// traffic while in-flight requests drain.
+func (h *Checker) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Liveness runs the liveness checks.
+func (h *Checker) Liveness(ctx context.Context) Report {
+	h.mutex.RLock()
+	checks := append([]namedCheck{}, h.liveness...)
+	h.mutex.RUnlock()
+	return h.run(ctx, checks)
+}
+
+// Readiness runs the readiness checks, preceded by the shutdown check.
+func (h *Checker) Readiness(ctx context.Context) Report {
+	h.mutex.RLock()
+	checks := append([]namedCheck{{name: "shutdown", check: h.checkShutdown}}, h.readiness...)
+	h.mutex.RUnlock()
+	return h.run(ctx, checks)
+}
+
+// Health runs every check.
+func (h *Checker) Health(ctx context.Context) Report {
+	live, ready := h.Liveness(ctx), h.Readiness(ctx)
+	report := Report{Status: StatusOK, Checks: append(live.Checks, ready.Checks...)}
+	if live.Status != StatusOK || ready.Status != StatusOK {
+		report.Status = StatusFail
+	}
+	return report
+}
+
+func (h *Checker) checkShutdown(context.Context) error {
+	if h.shuttingDown.Load() {
+		return errShuttingDown
+	}
+	return nil
+}
+
+// run executes the checks concurrently, each bounded by the checker timeout.
+func (h *Checker) run(ctx context.Context, checks []namedCheck) Report {
+	report := Report{Status: StatusOK, Checks: make([]CheckResult, len(checks))}
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c namedCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, h.timeout)
+			defer cancel()
+			start := time.Now()
+			err := c.check(checkCtx)
+			res := CheckResult{Name: c.name, Status: StatusOK, DurationMs: float64(time.Since(start).Microseconds()) / 1000}
+			if err != nil {
+				res.Status = StatusFail
+				res.Error = err.Error()
+			}
+			report.Checks[i] = res
+		}(i, c)
+	}
+	wg.Wait()
+	for _, res := range report.Checks {
+		if res.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
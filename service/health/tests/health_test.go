@@ -0,0 +1,73 @@
+package health_test
+
+import (
+	"context"
+	"employee/service/health"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checker", func() {
+	var checker *health.Checker
+
+	BeforeEach(func() {
+		checker = health.NewChecker(50 * time.Millisecond)
+	})
+
+	It("should be ready when every check passes", func() {
+		checker.AddReadinessCheck("storage", func(context.Context) error { return nil })
+
+		report := checker.Readiness(context.Background())
+		Expect(report.Status).To(Equal(health.StatusOK))
+		Expect(report.Checks).To(HaveLen(2))
+		Expect(report.Checks[1].Name).To(Equal("storage"))
+	})
+
+	It("should report the failing check with its error", func() {
+		checker.AddReadinessCheck("storage", func(context.Context) error { return nil })
+		checker.AddReadinessCheck("ledger", func(context.Context) error { return errors.New("unreachable") })
+
+		report := checker.Readiness(context.Background())
+		Expect(report.Status).To(Equal(health.StatusFail))
+		Expect(report.Checks[1].Status).To(Equal(health.StatusOK))
+		Expect(report.Checks[2].Name).To(Equal("ledger"))
+		Expect(report.Checks[2].Status).To(Equal(health.StatusFail))
+		Expect(report.Checks[2].Error).To(Equal("unreachable"))
+	})
+
+	It("should fail a check that exceeds the timeout", func() {
+		checker.AddLivenessCheck("storage", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		report := checker.Liveness(context.Background())
+		Expect(report.Status).To(Equal(health.StatusFail))
+		Expect(report.Checks[0].Error).To(Equal(context.DeadlineExceeded.Error()))
+	})
+
+	It("should stop being ready once shutdown begins while staying alive", func() {
+		checker.SetShuttingDown()
+
+		Expect(checker.Readiness(context.Background()).Status).To(Equal(health.StatusFail))
+		Expect(checker.Liveness(context.Background()).Status).To(Equal(health.StatusOK))
+		Expect(checker.Health(context.Background()).Status).To(Equal(health.StatusFail))
+	})
+
+	It("should probe HTTP dependencies", func() {
+		up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer up.Close()
+		down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer down.Close()
+
+		Expect(health.HTTPCheck(up.Client(), up.URL)(context.Background())).To(Succeed())
+		Expect(health.HTTPCheck(down.Client(), down.URL)(context.Background())).NotTo(Succeed())
+	})
+})
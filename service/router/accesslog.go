@@ -0,0 +1,21 @@
+package router
+
+import (
+	"employee/pkg/logger"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLog logs every request once it is served through the request logger, so
+// that the access log is redacted like every other line. The errors attached to
+// the request are counted rather than written, they may quote the request body.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		logger.Ctx(c.Request.Context()).Info().Int("status", c.Writer.Status()).Dur("duration", time.Since(start)).
+			Str("client_ip", c.ClientIP()).Int("errors", len(c.Errors)).Msg("Request served")
+	}
+}
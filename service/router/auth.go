@@ -0,0 +1,76 @@
+package router
+
+import (
+	"employee/logic/apikey"
+	"employee/pkg/apierror"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader carries the API key of service-to-service callers.
+const APIKeyHeader = "X-API-Key"
+
+// Authenticators lists the accepted authentication methods. Nil fields disable a method.
+type Authenticators struct {
+	Verifier *auth.Verifier
+	APIKeys  *apikey.Manager
+	// ClientCerts accepts a verified TLS client certificate as the only credential.
+	ClientCerts bool
+}
+
+// Authenticate rejects requests without valid credentials and stores the
+// authenticated principal in the gin context for the handlers.
+//
+// Requests carrying an X-API-Key header are authenticated against the API keys,
+// requests carrying a bearer token against the verifier, and any other request by
+// its verified client certificate. The verified client certificate is attached to
+// every principal, whichever method authenticated it.
+func Authenticate(a Authenticators) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var principal *auth.Principal
+		var err error
+
+		client := auth.ClientIdentityFromTLS(c.Request.TLS)
+		token, hasToken := bearerToken(c.GetHeader("Authorization"))
+
+		switch raw := c.GetHeader(APIKeyHeader); {
+		case raw != "" && a.APIKeys != nil:
+			principal, err = a.APIKeys.Authenticate(c.Request.Context(), raw)
+		case hasToken && a.Verifier != nil:
+			principal, err = a.Verifier.Verify(token)
+		case client != nil && a.ClientCerts:
+			principal = &auth.Principal{Subject: auth.CertSubjectPrefix + client.CommonName, Method: auth.MethodClientCert}
+		default:
+			err = auth.GetAuthError(auth.MissingToken)
+		}
+
+		if err != nil {
+			logger.Ctx(c.Request.Context()).Error().Err(err).Msg("Authentication failed")
+			abortUnauthorized(c, err.(*apierror.APIError))
+			return
+		}
+
+		principal.Client = client
+		auth.SetPrincipal(c, principal)
+		ctx := auth.WithPrincipal(c.Request.Context(), principal)
+		c.Request = c.Request.WithContext(logger.WithStr(ctx, "subject", principal.Subject))
+		c.Next()
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func abortUnauthorized(c *gin.Context, apiError *apierror.APIError) {
+	c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+	apierror.Abort(c, apiError)
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"employee/pkg/apierror"
+	"net/http"
+)
+
+func GetRouterError(c RouterError) *apierror.APIError {
+	return RouterErrors[c]
+}
+
+type RouterError int
+
+const (
+	RateLimitExceeded RouterError = iota + 100400
+)
+
+var RouterErrors = map[RouterError]*apierror.APIError{
+	RateLimitExceeded: {HttpStatusCode: http.StatusTooManyRequests, ErrCode: int(RateLimitExceeded), ErrorMessage: "Rate limit exceeded, retry later"},
+}
@@ -0,0 +1,43 @@
+package router
+
+import (
+	"employee/pkg/apierror"
+	"employee/pkg/metrics"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that matched no route, so arbitrary paths
+// cannot create new series.
+const unmatchedRoute = "unmatched"
+
+// Metrics records the count and latency of every request by route, method and
+// status, and the APIError codes returned, on reg.
+func Metrics(reg *metrics.Registry) gin.HandlerFunc {
+	requests := reg.NewCounterVec("http_requests_total", "HTTP requests by route, method and status.", "route", "method", "status")
+	latency := reg.NewHistogramVec("http_request_duration_seconds", "HTTP request latency by route, method and status.", metrics.DefBuckets, "route", "method", "status")
+	apiErrors := reg.NewCounterVec("http_api_errors_total", "API errors returned by route and error code.", "route", "code")
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		requests.WithLabelValues(route, c.Request.Method, status).Inc()
+		latency.WithLabelValues(route, c.Request.Method, status).Observe(time.Since(start).Seconds())
+
+		for _, err := range c.Errors {
+			var apiError *apierror.APIError
+			if errors.As(err.Err, &apiError) {
+				apiErrors.WithLabelValues(route, strconv.Itoa(apiError.ErrCode)).Inc()
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package router
+
+import (
+	"employee/pkg/apierror"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"employee/pkg/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit applies separate token buckets to reads and writes. Clients are
+// identified by their API key or JWT subject, and by IP address when anonymous.
+// It must run after Authenticate. A nil limiter leaves its side unlimited.
+func RateLimit(reads, writes *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limiter := writes
+		if isRead(c.Request.Method) {
+			limiter = reads
+		}
+
+		if limiter == nil || allow(c, limiter, clientKey(c)) {
+			c.Next()
+		}
+	}
+}
+
+// AddressRateLimit applies a token bucket per IP address to every request. It runs
+// before Authenticate, so that callers guessing credentials are limited too.
+func AddressRateLimit(addresses *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if allow(c, addresses, "ip:"+c.ClientIP()) {
+			c.Next()
+		}
+	}
+}
+
+// allow counts the request against the bucket of key, sets the rate limit headers
+// and aborts the request when the bucket is empty.
+func allow(c *gin.Context, limiter *ratelimit.Limiter, key string) bool {
+	res := limiter.Allow(key)
+	c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+
+	if !res.Allowed {
+		logger.Ctx(c.Request.Context()).Error().Str("client", key).Msg("Rate limit exceeded")
+		c.Header("Retry-After", strconv.Itoa(max(1, ceilSeconds(res.RetryAfter))))
+		apiError := GetRouterError(RateLimitExceeded)
+		apierror.Abort(c, apiError)
+		return false
+	}
+	return true
+}
+
+func isRead(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func clientKey(c *gin.Context) string {
+	if subject := auth.GetSubject(c); subject != "" {
+		return "sub:" + subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
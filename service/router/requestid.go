@@ -0,0 +1,60 @@
+package router
+
+import (
+	"crypto/rand"
+	"employee/pkg/logger"
+	"employee/pkg/tracing"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID from the caller and back in every response.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the IDs accepted from callers so they cannot flood the logs.
+const maxRequestIDLength = 128
+
+// RequestID propagates the caller's X-Request-ID, or assigns a new one, echoes it in
+// the response and attaches a logger carrying the request ID, route and method to
+// the request context. Authenticate adds the caller identity to that logger.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		l := logger.Log.With().Str("request_id", id).Str("route", route).Str("http_method", c.Request.Method)
+		if span := tracing.SpanFromContext(c.Request.Context()); span != nil {
+			l = l.Str("trace_id", span.SpanContext().TraceID.String())
+		}
+		ctx := logger.WithRequestID(c.Request.Context(), id)
+		c.Request = c.Request.WithContext(logger.WithLogger(ctx, l.Logger()))
+		c.Next()
+	}
+}
+
+// validRequestID accepts non-empty IDs of printable ASCII without spaces.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
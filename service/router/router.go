@@ -1,22 +1,225 @@
 package router
 
 import (
+	"employee/handlers/apikey"
+	audithandler "employee/handlers/audit"
+	"employee/handlers/department"
 	"employee/handlers/employee"
+	healthhandler "employee/handlers/health"
+	"employee/handlers/logging"
+	"employee/handlers/position"
+	logicapikey "employee/logic/apikey"
+	"employee/logic/audit"
+	"employee/logic/authz"
+	logicemployee "employee/logic/employee"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"employee/pkg/metrics"
+	"employee/pkg/ratelimit"
+	"employee/service/health"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func NewRouter() *gin.Engine {
+type options struct {
+	employees  *logicemployee.Employee
+	authn      Authenticators
+	authorizer *authz.Authorizer
+	reads      *ratelimit.Limiter
+	writes     *ratelimit.Limiter
+	addresses  *ratelimit.Limiter
+	proxies    []string
+	health     *health.Checker
+	metrics    *metrics.Registry
+	audit      *audit.Trail
+}
+
+// Option configures the router built by NewRouter.
+type Option func(*options)
+
+// WithEmployees serves the given employee store instead of a new in-memory one.
+func WithEmployees(emp *logicemployee.Employee) Option {
+	return func(o *options) {
+		o.employees = emp
+	}
+}
+
+// WithAuthentication requires a valid bearer token verified by v on every employee route.
+func WithAuthentication(v *auth.Verifier) Option {
+	return func(o *options) {
+		o.authn.Verifier = v
+	}
+}
+
+// WithAPIKeys accepts API keys issued by m in the X-API-Key header and
+// registers the key admin endpoints.
+func WithAPIKeys(m *logicapikey.Manager) Option {
+	return func(o *options) {
+		o.authn.APIKeys = m
+	}
+}
+
+// WithClientCertificates authenticates requests without other credentials by
+// their verified TLS client certificate.
+func WithClientCertificates() Option {
+	return func(o *options) {
+		o.authn.ClientCerts = true
+	}
+}
+
+// WithAuthorization evaluates the role based policy of a before every employee operation.
+func WithAuthorization(a *authz.Authorizer) Option {
+	return func(o *options) {
+		o.authorizer = a
+	}
+}
+
+// WithRateLimits limits every client to the given read and write rates. A zero
+// rate leaves that side unlimited.
+func WithRateLimits(reads, writes ratelimit.Limit) Option {
+	return func(o *options) {
+		if reads.Rate > 0 {
+			o.reads = ratelimit.NewLimiter(reads)
+		}
+		if writes.Rate > 0 {
+			o.writes = ratelimit.NewLimiter(writes)
+		}
+	}
+}
+
+// WithAddressRateLimit limits every IP address to the given rate before
+// authentication. A zero rate leaves addresses unlimited.
+func WithAddressRateLimit(l ratelimit.Limit) Option {
+	return func(o *options) {
+		if l.Rate > 0 {
+			o.addresses = ratelimit.NewLimiter(l)
+		}
+	}
+}
+
+// WithTrustedProxies takes the client IP from the X-Forwarded-For header of requests
+// coming from proxies, IP addresses or CIDRs. Without it the header is ignored and
+// the client IP is the peer address.
+func WithTrustedProxies(proxies []string) Option {
+	return func(o *options) {
+		o.proxies = proxies
+	}
+}
+
+// WithHealth serves the checks of h on /healthz, /readyz and /livez.
+func WithHealth(h *health.Checker) Option {
+	return func(o *options) {
+		o.health = h
+	}
+}
+
+// WithAudit serves the audit trail on /employees/:id/audit. The employee store
+// must record to the same trail, see Employee.SetAuditTrail.
+func WithAudit(trail *audit.Trail) Option {
+	return func(o *options) {
+		o.audit = trail
+	}
+}
+
+// WithMetrics records request metrics on reg and serves it on /metrics.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(o *options) {
+		o.metrics = reg
+	}
+}
+
+func NewRouter(opts ...Option) *gin.Engine {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	// Create router
-	router := gin.Default()
+	// gin's own logger writes to stdout, past the redaction of the request logger
+	router := gin.New()
+	router.Use(gin.Recovery())
+	// The client IP keys the rate limits, a header set by anyone must not change it
+	if err := router.SetTrustedProxies(o.proxies); err != nil {
+		logger.Log.Error().Err(err).Msg("Invalid trusted proxies, trusting none")
+		router.SetTrustedProxies(nil)
+	}
 
 	// Create employee handler
-	eh := employee.NewEmployeeHandler()
+	if o.employees == nil {
+		o.employees = logicemployee.NewEmployee()
+	}
+	eh := employee.NewEmployeeHandler(o.employees, o.authorizer)
+
+	router.Use(Trace(), RequestID(), AccessLog())
+
+	// Metrics and probes are neither authenticated nor rate limited
+	if o.metrics != nil {
+		router.Use(Metrics(o.metrics))
+		router.GET("/metrics", gin.WrapH(o.metrics.Handler()))
+	}
+
+	if o.health == nil {
+		o.health = health.NewChecker(time.Second)
+	}
+	hh := healthhandler.NewHealthHandler(o.health)
+	router.GET("/healthz", hh.Healthz)
+	router.GET("/readyz", hh.Readyz)
+	router.GET("/livez", hh.Livez)
+
+	api := router.Group("/")
+	if o.addresses != nil {
+		api.Use(AddressRateLimit(o.addresses))
+	}
+	if o.authn.Verifier != nil || o.authn.APIKeys != nil || o.authn.ClientCerts {
+		api.Use(Authenticate(o.authn))
+	}
+	if o.reads != nil || o.writes != nil {
+		api.Use(RateLimit(o.reads, o.writes))
+	}
 
 	// Register handlers
-	router.POST("/employee", eh.CreateEmployee)
-	router.GET("/employee", eh.GetEmployee)
-	router.PUT("/employee", eh.UpdateEmployee)
-	router.DELETE("/employee", eh.DeleteEmployee)
+	api.POST("/employee", eh.CreateEmployee)
+	api.GET("/employee", eh.GetEmployee)
+	api.PUT("/employee", eh.UpdateEmployee)
+	api.DELETE("/employee", eh.DeleteEmployee)
+	api.GET("/employees/stats", eh.GetEmployeeStats)
+	api.POST("/employees/:id/restore", eh.RestoreEmployee)
+	api.GET("/employees/:id/salary-history", eh.GetSalaryHistory)
+	api.GET("/employees/:id/reports", eh.GetReports)
+	api.POST("/employees/:id/reports/move", eh.MoveReports)
+	api.GET("/employees/:id/chain", eh.GetChain)
+
+	dh := department.NewDepartmentHandler(o.employees, o.authorizer)
+	api.POST("/departments", dh.CreateDepartment)
+	api.GET("/departments", dh.GetDepartments)
+	api.GET("/departments/rollups", dh.GetRollups)
+	api.GET("/departments/:id", dh.GetDepartments)
+	api.PUT("/departments/:id", dh.UpdateDepartment)
+	api.DELETE("/departments/:id", dh.DeleteDepartment)
+	api.GET("/departments/:id/rollup", dh.GetRollups)
+
+	ph := position.NewPositionHandler(o.employees, o.authorizer)
+	api.POST("/positions", ph.CreatePosition)
+	api.GET("/positions", ph.GetPositions)
+	api.GET("/positions/:title", ph.GetPositions)
+	api.PUT("/positions/:title", ph.UpdatePosition)
+	api.DELETE("/positions/:title", ph.DeletePosition)
+
+	if o.audit != nil {
+		ah := audithandler.NewAuditHandler(o.audit, o.authorizer)
+		api.GET("/employees/:id/audit", ah.GetAudit)
+	}
+
+	lh := logging.NewLoggingHandler(o.authorizer)
+	api.GET("/admin/loglevel", lh.GetLevel)
+	api.PUT("/admin/loglevel", lh.SetLevel)
+
+	if o.authn.APIKeys != nil {
+		kh := apikey.NewAPIKeyHandler(o.authn.APIKeys, o.authorizer)
+		api.POST("/admin/apikeys", kh.CreateKey)
+		api.GET("/admin/apikeys", kh.ListKeys)
+		api.DELETE("/admin/apikeys/:id", kh.RevokeKey)
+	}
 	return router
 }
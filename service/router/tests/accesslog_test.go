@@ -0,0 +1,52 @@
+package router_test
+
+import (
+	"bytes"
+	"employee/pkg/logger"
+	"employee/pkg/testhelpers"
+	"employee/service/router"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Access log", func() {
+	It("logs requests through the redacting logger only", func() {
+		stdout := &bytes.Buffer{}
+		previousWriter, previousErrorWriter := gin.DefaultWriter, gin.DefaultErrorWriter
+		previousMode, previous, level := gin.Mode(), logger.Log, zerolog.GlobalLevel()
+		DeferCleanup(func() {
+			gin.DefaultWriter, gin.DefaultErrorWriter = previousWriter, previousErrorWriter
+			gin.SetMode(previousMode)
+			logger.Log = previous
+			zerolog.SetGlobalLevel(level)
+		})
+		// gin's own logger takes its writer when built, and debug mode prints the routes
+		gin.DefaultWriter, gin.DefaultErrorWriter = stdout, stdout
+		gin.SetMode(gin.ReleaseMode)
+		sink := filepath.Join(GinkgoT().TempDir(), "json.log")
+		closer, err := logger.Init(logger.Options{Level: "Info", Format: logger.FormatJSON, Outputs: []string{sink}})
+		Expect(err).To(BeNil())
+
+		r := router.NewRouter()
+		req, _ := http.NewRequest("POST", "/employee", bytes.NewBufferString(`{"id":1,"name":"Ada","position":"Engineer","salary":"98765.123456"}`))
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusBadRequest
+		})
+		Expect(res).To(Equal(true))
+		Expect(closer.Close()).To(Succeed())
+
+		Expect(stdout.String()).To(BeEmpty())
+		data, err := os.ReadFile(sink)
+		Expect(err).To(BeNil())
+		Expect(string(data)).To(ContainSubstring(`"msg":"Request served"`))
+		Expect(string(data)).To(ContainSubstring(`"status":400`))
+		Expect(string(data)).NotTo(ContainSubstring("98765"))
+	})
+})
@@ -0,0 +1,85 @@
+package router_test
+
+import (
+	"bytes"
+	"context"
+	"employee/logic/apikey"
+	"employee/logic/authz"
+	"employee/models"
+	"employee/pkg/auth"
+	"employee/pkg/testhelpers"
+	"employee/service/router"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("X-API-Key authentication", func() {
+	var (
+		keys *apikey.Manager
+		r    *gin.Engine
+	)
+
+	newKey := func(scopes ...string) string {
+		res, err := keys.CreateKey(context.Background(), models.CreateAPIKeyRequest{Name: "job", Scopes: scopes})
+		Expect(err).To(BeNil())
+		return res.Key
+	}
+
+	BeforeEach(func() {
+		keys = apikey.NewManager()
+		v, err := auth.NewVerifier(auth.Config{HMACSecret: []byte("secret")})
+		Expect(err).To(BeNil())
+		r = router.NewRouter(router.WithAuthentication(v), router.WithAPIKeys(keys),
+			router.WithAuthorization(authz.NewAuthorizer(authz.DefaultPolicy())))
+	})
+
+	It("returns 401 for an unknown key", func() {
+		req, _ := http.NewRequest("GET", "/employee", nil)
+		req.Header.Set("X-API-Key", "emk_0000_1111")
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusUnauthorized
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	It("allows reads with the employees:read scope", func() {
+		req, _ := http.NewRequest("GET", "/employee", nil)
+		req.Header.Set("X-API-Key", newKey(auth.ScopeEmployeesRead))
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusOK
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	It("returns 403 for writes with only the employees:read scope", func() {
+		req, _ := http.NewRequest("POST", "/employee", bytes.NewBufferString(`{"id":1,"name":"John Doe","position":"Developer","salary":50000}`))
+		req.Header.Set("X-API-Key", newKey(auth.ScopeEmployeesRead))
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusForbidden
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	It("lets an apikeys:admin key manage keys", func() {
+		req, _ := http.NewRequest("POST", "/admin/apikeys", bytes.NewBufferString(`{"name":"nightly","scopes":["employees:read"]}`))
+		req.Header.Set("X-API-Key", newKey(auth.ScopeAPIKeysAdmin))
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusCreated
+		})
+		Expect(res).To(Equal(true))
+		Expect(keys.ListKeys(context.Background())).To(HaveLen(2))
+	})
+
+	It("returns 403 on the admin endpoints without the apikeys:admin scope", func() {
+		req, _ := http.NewRequest("GET", "/admin/apikeys", nil)
+		req.Header.Set("X-API-Key", newKey(auth.ScopeEmployeesWrite))
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusForbidden
+		})
+		Expect(res).To(Equal(true))
+	})
+})
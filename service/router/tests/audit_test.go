@@ -0,0 +1,99 @@
+package router_test
+
+import (
+	"bytes"
+	"context"
+	"employee/logic/apikey"
+	"employee/logic/audit"
+	"employee/logic/authz"
+	"employee/logic/employee"
+	"employee/models"
+	"employee/pkg/auth"
+	"employee/pkg/testhelpers"
+	"employee/service/router"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Audit trail endpoint", func() {
+	var (
+		secret = []byte("0123456789abcdef0123456789abcdef")
+		keys   *apikey.Manager
+		r      *gin.Engine
+	)
+
+	token := func(role string) string {
+		return "Bearer " + testhelpers.SignHS256(secret, "", map[string]any{
+			"sub": "alice", "roles": []string{role}, "exp": time.Now().Add(time.Hour).Unix(),
+		})
+	}
+
+	BeforeEach(func() {
+		trail := audit.NewTrail()
+		emp := employee.NewEmployee()
+		emp.SetAuditTrail(trail)
+		keys = apikey.NewManager()
+		v, err := auth.NewVerifier(auth.Config{HMACSecret: secret})
+		Expect(err).To(BeNil())
+		r = router.NewRouter(router.WithEmployees(emp), router.WithAudit(trail), router.WithAuthentication(v),
+			router.WithAPIKeys(keys), router.WithAuthorization(authz.NewAuthorizer(authz.DefaultPolicy())))
+
+		for _, req := range []*http.Request{
+			httptest.NewRequest("POST", "/employee", bytes.NewBufferString(`{"id":1,"name":"Ada","position":"Engineer","salary":100}`)),
+			httptest.NewRequest("PUT", "/employee?id=1", bytes.NewBufferString(`{"position":"Lead","salary":150}`)),
+		} {
+			req.Header.Set("Authorization", token("hr-admin"))
+			req.Header.Set(router.RequestIDHeader, "req-"+req.Method)
+			Expect(testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+				return w.Code == http.StatusOK
+			})).To(Equal(true))
+		}
+	})
+
+	get := func(credential func(*http.Request)) (int, models.GetAuditResponse) {
+		req, _ := http.NewRequest("GET", "/employees/1/audit", nil)
+		credential(req)
+		var code int
+		var body models.GetAuditResponse
+		testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			code = w.Code
+			json.Unmarshal(w.Body.Bytes(), &body)
+			return true
+		})
+		return code, body
+	}
+
+	It("lists every mutation with actor, request ID and diff", func() {
+		code, body := get(func(req *http.Request) { req.Header.Set("Authorization", token("hr-admin")) })
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body.Entries).To(HaveLen(2))
+		Expect(body.Entries[0].Actor).To(Equal("alice"))
+		Expect(body.Entries[0].RequestID).To(Equal("req-POST"))
+		Expect(body.Entries[1].RequestID).To(Equal("req-PUT"))
+		Expect(body.Entries[1].Changes).To(Equal([]models.FieldChange{
+			{Field: "position", Old: []byte(`"Engineer"`), New: []byte(`"Lead"`)},
+			{Field: "salary", Old: []byte(`"100"`), New: []byte(`"150"`)},
+		}))
+	})
+
+	It("leaves salary changes out for callers who may not see salaries", func() {
+		key, err := keys.CreateKey(context.Background(), models.CreateAPIKeyRequest{Name: "auditor", Scopes: []string{auth.ScopeAuditRead}})
+		Expect(err).To(BeNil())
+		code, body := get(func(req *http.Request) { req.Header.Set("X-API-Key", key.Key) })
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body.Entries[1].Changes).To(Equal([]models.FieldChange{
+			{Field: "position", Old: []byte(`"Engineer"`), New: []byte(`"Lead"`)},
+		}))
+	})
+
+	It("forbids roles without audit access", func() {
+		code, _ := get(func(req *http.Request) { req.Header.Set("Authorization", token("viewer")) })
+		Expect(code).To(Equal(http.StatusForbidden))
+	})
+})
@@ -0,0 +1,59 @@
+package router_test
+
+import (
+	"employee/pkg/auth"
+	"employee/pkg/testhelpers"
+	"employee/service/router"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Authenticate middleware", func() {
+	var (
+		secret = []byte("0123456789abcdef0123456789abcdef")
+		r      *gin.Engine
+	)
+
+	BeforeEach(func() {
+		v, err := auth.NewVerifier(auth.Config{HMACSecret: secret, Audience: "employee-api"})
+		Expect(err).To(BeNil())
+		r = router.NewRouter(router.WithAuthentication(v))
+	})
+
+	It("returns 401 without a bearer token", func() {
+		req, _ := http.NewRequest("GET", "/employee", nil)
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") != ""
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	It("returns 401 for an expired token", func() {
+		token := testhelpers.SignHS256(secret, "", map[string]any{
+			"sub": "user-1", "aud": "employee-api", "exp": time.Now().Add(-time.Hour).Unix(),
+		})
+		req, _ := http.NewRequest("GET", "/employee", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusUnauthorized
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	It("returns 200 for a valid token", func() {
+		token := testhelpers.SignHS256(secret, "", map[string]any{
+			"sub": "user-1", "aud": "employee-api", "exp": time.Now().Add(time.Hour).Unix(),
+		})
+		req, _ := http.NewRequest("GET", "/employee", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusOK
+		})
+		Expect(res).To(Equal(true))
+	})
+})
@@ -0,0 +1,63 @@
+package router_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"employee/logic/authz"
+	"employee/pkg/testhelpers"
+	"employee/service/router"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client certificate authentication", func() {
+	var (
+		ca *testhelpers.TestCA
+		r  *gin.Engine
+	)
+
+	requestAs := func(cn string) *http.Request {
+		certPEM, _ := ca.Issue(cn, false)
+		block, _ := pem.Decode(certPEM)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).To(BeNil())
+
+		req, _ := http.NewRequest("GET", "/employee", nil)
+		req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert, ca.Cert}}}
+		return req
+	}
+
+	BeforeEach(func() {
+		ca = testhelpers.NewTestCA("test-ca")
+		policy := authz.DefaultPolicy()
+		policy.Bindings = map[string][]string{"cert:payroll-batch": {"viewer"}}
+		r = router.NewRouter(router.WithClientCertificates(), router.WithAuthorization(authz.NewAuthorizer(policy)))
+	})
+
+	It("returns 200 for a certificate bound to a role", func() {
+		res := testhelpers.TestHTTPResponse(r, requestAs("payroll-batch"), func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusOK
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	It("returns 403 for a certificate without a role", func() {
+		res := testhelpers.TestHTTPResponse(r, requestAs("unknown-service"), func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusForbidden
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	It("returns 401 without a certificate", func() {
+		req, _ := http.NewRequest("GET", "/employee", nil)
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusUnauthorized
+		})
+		Expect(res).To(Equal(true))
+	})
+})
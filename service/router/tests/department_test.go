@@ -0,0 +1,49 @@
+package router_test
+
+import (
+	"employee/models"
+	"employee/pkg/testhelpers"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Department endpoints", func() {
+	var api *testhelpers.APIClient
+
+	BeforeEach(func() {
+		api = newAPI()
+
+		Expect(api.Do("POST", "/departments", `{"id":"eng","name":"Engineering"}`, "hr-admin", http.StatusOK, nil)).To(BeTrue())
+		Expect(api.Do("POST", "/employee", `{"id":1,"name":"Ada","position":"Engineer","salary":100,"department":"eng"}`, "hr-admin", http.StatusOK, nil)).To(BeTrue())
+		Expect(api.Do("POST", "/employee", `{"id":2,"name":"Alan","position":"Engineer","salary":300,"department":"eng"}`, "hr-admin", http.StatusOK, nil)).To(BeTrue())
+	})
+
+	It("manages departments", func() {
+		var res models.GetDepartmentsResponse
+		Expect(api.Do("GET", "/departments", "", "viewer", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Departments).To(Equal([]models.Department{{ID: "eng", Name: "Engineering"}}))
+
+		Expect(api.Do("PUT", "/departments/eng", `{"name":"R&D"}`, "hr-admin", http.StatusOK, nil)).To(BeTrue())
+		Expect(api.Do("GET", "/departments/eng", "", "viewer", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Departments[0].Name).To(Equal("R&D"))
+
+		Expect(api.Do("POST", "/employee", `{"id":3,"name":"Ken","position":"Engineer","salary":100,"department":"ops"}`, "hr-admin", http.StatusBadRequest, nil)).
+			To(BeTrue())
+		Expect(api.Do("DELETE", "/departments/eng", "", "hr-admin", http.StatusBadRequest, nil)).To(BeTrue())
+		Expect(api.Do("DELETE", "/departments/eng", "", "viewer", http.StatusForbidden, nil)).To(BeTrue())
+		// GET /departments/rollups lists the rollups, it cannot name a department
+		Expect(api.Do("POST", "/departments", `{"id":"rollups","name":"Rollups"}`, "hr-admin", http.StatusBadRequest, nil)).To(BeTrue())
+	})
+
+	It("serves the rollups, with payroll only to callers who may see salaries", func() {
+		var res models.GetDepartmentRollupsResponse
+		Expect(api.Do("GET", "/departments/eng/rollup", "", "hr-admin", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Rollups).To(Equal([]models.DepartmentRollup{{Department: "eng", Headcount: 2, TotalSalary: models.MoneyOf(400), AverageSalary: models.MoneyOf(200), Currency: "USD"}}))
+
+		var hidden models.GetDepartmentRollupsResponse
+		Expect(api.Do("GET", "/departments/rollups", "", "viewer", http.StatusOK, &hidden)).To(BeTrue())
+		Expect(hidden.Rollups).To(Equal([]models.DepartmentRollup{{Department: "eng", Headcount: 2, Currency: "USD"}}))
+	})
+})
@@ -0,0 +1,21 @@
+package router_test
+
+import (
+	"employee/logic/authz"
+	"employee/pkg/auth"
+	"employee/pkg/testhelpers"
+	"employee/service/router"
+
+	. "github.com/onsi/gomega"
+)
+
+// newAPI returns a client of a new router that authenticates tokens signed by the
+// client and authorizes them with the default policy, unless opts say otherwise.
+func newAPI(opts ...router.Option) *testhelpers.APIClient {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	v, err := auth.NewVerifier(auth.Config{HMACSecret: secret})
+	Expect(err).To(BeNil())
+	opts = append([]router.Option{router.WithAuthentication(v), router.WithAuthorization(authz.NewAuthorizer(authz.DefaultPolicy()))}, opts...)
+	r := router.NewRouter(opts...)
+	return &testhelpers.APIClient{Router: r, Secret: secret, Subject: "alice"}
+}
@@ -0,0 +1,74 @@
+package router_test
+
+import (
+	"context"
+	"employee/pkg/auth"
+	"employee/pkg/testhelpers"
+	"employee/service/health"
+	"employee/service/router"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Health endpoints", func() {
+	var (
+		r       *gin.Engine
+		checker *health.Checker
+		ready   error
+	)
+
+	BeforeEach(func() {
+		ready = nil
+		checker = health.NewChecker(time.Second)
+		checker.AddReadinessCheck("storage", func(context.Context) error { return ready })
+		verifier, err := auth.NewVerifier(auth.Config{HMACSecret: []byte("0123456789abcdef0123456789abcdef")})
+		Expect(err).To(BeNil())
+		r = router.NewRouter(router.WithHealth(checker), router.WithAuthentication(verifier))
+	})
+
+	probe := func(path string, code int, status string) bool {
+		req, _ := http.NewRequest("GET", path, nil)
+		return testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			var report health.Report
+			return w.Code == code && json.Unmarshal(w.Body.Bytes(), &report) == nil && report.Status == status
+		})
+	}
+
+	It("answers without credentials", func() {
+		Expect(probe("/livez", http.StatusOK, health.StatusOK)).To(Equal(true))
+		Expect(probe("/readyz", http.StatusOK, health.StatusOK)).To(Equal(true))
+		Expect(probe("/healthz", http.StatusOK, health.StatusOK)).To(Equal(true))
+	})
+
+	It("reports 503 with the failing check while storage is not ready", func() {
+		ready = errors.New("storage has not finished loading")
+		req, _ := http.NewRequest("GET", "/readyz", nil)
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			var report health.Report
+			if w.Code != http.StatusServiceUnavailable || json.Unmarshal(w.Body.Bytes(), &report) != nil {
+				return false
+			}
+			for _, c := range report.Checks {
+				if c.Name == "storage" {
+					return c.Status == health.StatusFail && c.Error == ready.Error()
+				}
+			}
+			return false
+		})
+		Expect(res).To(Equal(true))
+		Expect(probe("/livez", http.StatusOK, health.StatusOK)).To(Equal(true))
+	})
+
+	It("stops being ready once shutdown begins", func() {
+		checker.SetShuttingDown()
+		Expect(probe("/readyz", http.StatusServiceUnavailable, health.StatusFail)).To(Equal(true))
+		Expect(probe("/livez", http.StatusOK, health.StatusOK)).To(Equal(true))
+	})
+})
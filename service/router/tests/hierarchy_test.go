@@ -0,0 +1,79 @@
+package router_test
+
+import (
+	"employee/logic/authz"
+	logicemployee "employee/logic/employee"
+	"employee/models"
+	"employee/pkg/testhelpers"
+	"employee/service/router"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hierarchy endpoints", func() {
+	var api *testhelpers.APIClient
+
+	BeforeEach(func() {
+		api = newAPI()
+
+		for _, body := range []string{
+			`{"id":1,"name":"Grace","position":"CEO","salary":300}`,
+			`{"id":2,"name":"Ada","position":"Director","salary":200,"manager_id":1}`,
+			`{"id":3,"name":"Alan","position":"Engineer","salary":100,"manager_id":2}`,
+		} {
+			Expect(api.Do("POST", "/employee", body, "hr-admin", http.StatusOK, nil)).To(BeTrue())
+		}
+	})
+
+	It("lists reports and the management chain", func() {
+		var res models.GetEmployeeResponse
+		Expect(api.Do("GET", "/employees/1/reports", "", "hr-admin", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Employees).To(HaveLen(1))
+		Expect(api.Do("GET", "/employees/1/reports?recursive=true", "", "hr-admin", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Employees).To(HaveLen(2))
+
+		var chain models.GetEmployeeResponse
+		Expect(api.Do("GET", "/employees/3/chain", "", "viewer", http.StatusOK, &chain)).To(BeTrue())
+		Expect(chain.Employees).To(HaveLen(2))
+		Expect(chain.Employees[0].ID).To(Equal(2))
+		Expect(chain.Employees[0].Salary).To(BeZero())
+	})
+
+	It("moves a team and rejects cycles", func() {
+		Expect(api.Do("POST", "/employees/2/reports/move", `{"manager_id":3}`, "hr-admin", http.StatusBadRequest, nil)).To(BeTrue())
+
+		var res models.MoveReportsResponse
+		Expect(api.Do("POST", "/employees/2/reports/move", `{"manager_id":1}`, "hr-admin", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Moved).To(Equal([]int{3}))
+		Expect(api.Do("DELETE", "/employee?id=2", "", "hr-admin", http.StatusOK, nil)).To(BeTrue())
+	})
+
+	It("lets managers move their reports only to managers in their scope", func() {
+		emp := logicemployee.NewEmployee()
+		policy := authz.DefaultPolicy()
+		policy.Employees = map[string]int{"alice": 1}
+		az := authz.NewAuthorizer(policy)
+		az.SetHierarchy(emp.ManagerOf)
+		api = newAPI(router.WithEmployees(emp), router.WithAuthorization(az))
+		for _, body := range []string{
+			`{"id":1,"name":"Grace","position":"CEO","salary":300}`,
+			`{"id":2,"name":"Ada","position":"Director","salary":200,"manager_id":1}`,
+			`{"id":3,"name":"Alan","position":"Engineer","salary":100,"manager_id":2}`,
+			`{"id":4,"name":"Edsger","position":"Director","salary":200,"manager_id":1}`,
+			`{"id":5,"name":"Barbara","position":"Director","salary":200}`,
+		} {
+			Expect(api.Do("POST", "/employee", body, "hr-admin", http.StatusOK, nil)).To(BeTrue())
+		}
+
+		Expect(api.Do("POST", "/employees/2/reports/move", `{"manager_id":5}`, "manager", http.StatusForbidden, nil)).To(BeTrue())
+		var res models.MoveReportsResponse
+		Expect(api.Do("POST", "/employees/2/reports/move", `{"manager_id":4}`, "manager", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Moved).To(Equal([]int{3}))
+	})
+
+	It("forbids viewers from moving teams", func() {
+		Expect(api.Do("POST", "/employees/2/reports/move", `{"manager_id":1}`, "viewer", http.StatusForbidden, nil)).To(BeTrue())
+	})
+})
@@ -0,0 +1,71 @@
+package router_test
+
+import (
+	"bytes"
+	"employee/handlers/logging"
+	"employee/logic/authz"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"employee/pkg/testhelpers"
+	"employee/service/router"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Log level endpoint", func() {
+	var (
+		secret = []byte("0123456789abcdef0123456789abcdef")
+		r      *gin.Engine
+	)
+
+	BeforeEach(func() {
+		v, err := auth.NewVerifier(auth.Config{HMACSecret: secret})
+		Expect(err).To(BeNil())
+		r = router.NewRouter(router.WithAuthentication(v), router.WithAuthorization(authz.NewAuthorizer(authz.DefaultPolicy())))
+		DeferCleanup(zerolog.SetGlobalLevel, zerolog.GlobalLevel())
+	})
+
+	request := func(method, role, body string) *http.Request {
+		req, _ := http.NewRequest(method, "/admin/loglevel", bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer "+testhelpers.SignHS256(secret, "", map[string]any{
+			"sub": "ops", "roles": []string{role}, "exp": time.Now().Add(time.Hour).Unix(),
+		}))
+		return req
+	}
+
+	It("lets hr-admin change the level", func() {
+		res := testhelpers.TestHTTPResponse(r, request("PUT", "hr-admin", `{"level":"debug"}`), func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusOK && w.Body.String() == `{"level":"Debug"}`
+		})
+		Expect(res).To(Equal(true))
+		Expect(logger.GetLevel()).To(Equal("Debug"))
+
+		res = testhelpers.TestHTTPResponse(r, request("GET", "hr-admin", ""), func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusOK && w.Body.String() == `{"level":"Debug"}`
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	It("rejects unknown levels", func() {
+		res := testhelpers.TestHTTPResponse(r, request("PUT", "hr-admin", `{"level":"loud"}`), func(w *httptest.ResponseRecorder) bool {
+			var body map[string]any
+			return w.Code == http.StatusBadRequest && json.Unmarshal(w.Body.Bytes(), &body) == nil &&
+				body["code"] == float64(logging.InvalidLogLevel)
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	It("forbids other roles", func() {
+		res := testhelpers.TestHTTPResponse(r, request("PUT", "viewer", `{"level":"Debug"}`), func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusForbidden
+		})
+		Expect(res).To(Equal(true))
+	})
+})
@@ -0,0 +1,71 @@
+package router_test
+
+import (
+	"bytes"
+	"employee/logic/employee"
+	"employee/pkg/config"
+	"employee/pkg/metrics"
+	"employee/pkg/testhelpers"
+	"employee/service/router"
+	"employee/service/storage"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Metrics", func() {
+	var r *gin.Engine
+
+	BeforeEach(func() {
+		reg := metrics.NewRegistry()
+		store, err := storage.Open(config.Default().Storage)
+		Expect(err).To(BeNil())
+		store.RegisterMetrics(reg)
+		r = router.NewRouter(router.WithMetrics(reg), router.WithEmployees(employee.NewEmployeeWithDB(store.Employees)))
+	})
+
+	scrape := func(expected ...string) bool {
+		req, _ := http.NewRequest("GET", "/metrics", nil)
+		return testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			if w.Code != http.StatusOK || w.Header().Get("Content-Type") != metrics.ContentType {
+				return false
+			}
+			for _, e := range expected {
+				if !strings.Contains(w.Body.String(), e) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	It("counts requests and API errors by route", func() {
+		req, _ := http.NewRequest("GET", "/employee?id=abc", nil)
+		testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool { return true })
+		req, _ = http.NewRequest("GET", "/nowhere/42", nil)
+		testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool { return true })
+
+		Expect(scrape(
+			`http_requests_total{route="/employee",method="GET",status="400"} 1`,
+			`http_request_duration_seconds_count{route="/employee",method="GET",status="400"} 1`,
+			`http_api_errors_total{route="/employee",code="100010"} 1`,
+			`http_requests_total{route="unmatched",method="GET",status="404"} 1`,
+		)).To(Equal(true))
+	})
+
+	It("exports the storage counters", func() {
+		body := bytes.NewBufferString(`{"id":1,"name":"Ada","position":"Engineer","salary":100}`)
+		req, _ := http.NewRequest("POST", "/employee", body)
+		testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool { return true })
+
+		Expect(scrape(
+			`simpledb_items{db="employees"} 1`,
+			`simpledb_operations_total{db="employees",method="SetItem"} 1`,
+			`# TYPE simpledb_lock_wait_seconds_total counter`,
+		)).To(Equal(true))
+	})
+})
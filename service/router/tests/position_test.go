@@ -0,0 +1,51 @@
+package router_test
+
+import (
+	"employee/models"
+	"employee/pkg/testhelpers"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Position endpoints", func() {
+	var api *testhelpers.APIClient
+
+	BeforeEach(func() {
+		api = newAPI()
+
+		Expect(api.Do("POST", "/positions", `{"title":"Engineer","min_salary":100,"mid_salary":150,"max_salary":200}`, "hr-admin", http.StatusOK, nil)).
+			To(BeTrue())
+	})
+
+	It("manages positions", func() {
+		Expect(api.Do("POST", "/positions", `{"title":"Manager","min_salary":1,"mid_salary":2,"max_salary":3}`, "viewer", http.StatusForbidden, nil)).
+			To(BeTrue())
+		Expect(api.Do("PUT", "/positions/Engineer", `{"min_salary":110,"mid_salary":160,"max_salary":210}`, "hr-admin", http.StatusOK, nil)).
+			To(BeTrue())
+
+		var res models.GetPositionsResponse
+		Expect(api.Do("GET", "/positions/Engineer", "", "hr-admin", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Positions).To(Equal([]models.Position{{Title: "Engineer", MinSalary: models.MoneyOf(110), MidSalary: models.MoneyOf(160), MaxSalary: models.MoneyOf(210), Currency: "USD"}}))
+
+		var hidden models.GetPositionsResponse
+		Expect(api.Do("GET", "/positions", "", "viewer", http.StatusOK, &hidden)).To(BeTrue())
+		Expect(hidden.Positions).To(Equal([]models.Position{{Title: "Engineer", Currency: "USD"}}))
+
+		Expect(api.Do("DELETE", "/positions/Engineer", "", "hr-admin", http.StatusOK, nil)).To(BeTrue())
+		Expect(api.Do("GET", "/positions/Engineer", "", "hr-admin", http.StatusBadRequest, nil)).To(BeTrue())
+	})
+
+	It("creates employees outside the band only with a justification", func() {
+		Expect(api.Do("POST", "/employee", `{"id":1,"name":"Ada","position":"Engineer","salary":250}`, "hr-admin", http.StatusBadRequest, nil)).
+			To(BeTrue())
+		Expect(api.Do("POST", "/employee", `{"id":1,"name":"Ada","position":"Engineer","salary":250,"salary_justification":"market rate"}`, "hr-admin", http.StatusOK, nil)).
+			To(BeTrue())
+
+		var res models.GetSalaryHistoryResponse
+		Expect(api.Do("GET", "/employees/1/salary-history", "", "hr-admin", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Changes).To(HaveLen(1))
+		Expect(res.Changes[0].OverrideJustification).To(Equal("market rate"))
+	})
+})
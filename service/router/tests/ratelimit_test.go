@@ -0,0 +1,136 @@
+package router_test
+
+import (
+	"bytes"
+	"employee/logic/employee"
+	"employee/pkg/auth"
+	"employee/pkg/ratelimit"
+	"employee/pkg/testhelpers"
+	"employee/service/router"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rate limiting", func() {
+	var r *gin.Engine
+
+	BeforeEach(func() {
+		r = router.NewRouter(
+			router.WithRateLimits(ratelimit.Limit{Rate: 0.001, Burst: 2}, ratelimit.Limit{Rate: 0.001, Burst: 1}),
+		)
+	})
+
+	It("returns 429 with Retry-After once the read budget is spent", func() {
+		for i := 0; i < 2; i++ {
+			req, _ := http.NewRequest("GET", "/employee", nil)
+			res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+				return w.Code == http.StatusOK && w.Header().Get("RateLimit-Limit") == "2"
+			})
+			Expect(res).To(Equal(true))
+		}
+
+		req, _ := http.NewRequest("GET", "/employee", nil)
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusTooManyRequests &&
+				w.Header().Get("Retry-After") != "" &&
+				w.Header().Get("RateLimit-Remaining") == "0"
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	It("keeps separate budgets for reads and writes", func() {
+		req, _ := http.NewRequest("DELETE", "/employee?id=1", nil)
+		testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool { return true })
+
+		req, _ = http.NewRequest("DELETE", "/employee?id=1", nil)
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusTooManyRequests
+		})
+		Expect(res).To(Equal(true))
+
+		req, _ = http.NewRequest("GET", "/employee", nil)
+		res = testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusOK
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	It("leaves a side with a zero rate unlimited", func() {
+		r = router.NewRouter(router.WithRateLimits(ratelimit.Limit{Rate: 0.001, Burst: 1}, ratelimit.Limit{Rate: 0, Burst: 1}))
+		for i := 0; i < 3; i++ {
+			req, _ := http.NewRequest("DELETE", "/employee?id=1", nil)
+			res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+				return w.Code != http.StatusTooManyRequests && w.Header().Get("RateLimit-Limit") == ""
+			})
+			Expect(res).To(Equal(true))
+		}
+	})
+
+	It("limits callers failing authentication by IP address", func() {
+		v, err := auth.NewVerifier(auth.Config{HMACSecret: []byte("0123456789abcdef0123456789abcdef")})
+		Expect(err).To(BeNil())
+		r = router.NewRouter(router.WithAuthentication(v), router.WithAddressRateLimit(ratelimit.Limit{Rate: 0.001, Burst: 2}))
+
+		codes := []int{}
+		for i := 0; i < 3; i++ {
+			req, _ := http.NewRequest("GET", "/employee", nil)
+			req.Header.Set("Authorization", "Bearer guessed")
+			testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+				codes = append(codes, w.Code)
+				return true
+			})
+		}
+		Expect(codes).To(Equal([]int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}))
+	})
+
+	It("keys addresses on the peer unless it is a trusted proxy", func() {
+		codes := func(r *gin.Engine) []int {
+			codes := []int{}
+			for i := 0; i < 3; i++ {
+				req, _ := http.NewRequest("GET", "/employee", nil)
+				req.RemoteAddr = "192.0.2.1:4321"
+				req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+				testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+					codes = append(codes, w.Code)
+					return true
+				})
+			}
+			return codes
+		}
+		limit := ratelimit.Limit{Rate: 0.001, Burst: 2}
+
+		// A spoofed X-Forwarded-For does not reset the bucket
+		r = router.NewRouter(router.WithAddressRateLimit(limit))
+		Expect(codes(r)).To(Equal([]int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}))
+
+		r = router.NewRouter(router.WithAddressRateLimit(limit), router.WithTrustedProxies([]string{"192.0.2.0/24"}))
+		Expect(codes(r)).To(Equal([]int{http.StatusOK, http.StatusOK, http.StatusOK}))
+	})
+
+	It("caps num_records at the configured maximum", func() {
+		emp := employee.NewEmployee()
+		emp.SetPageSizes(5, 5)
+		r = router.NewRouter(router.WithEmployees(emp))
+		for i := 1; i <= 8; i++ {
+			body := fmt.Sprintf(`{"id":%d,"name":"Employee","position":"Developer","salary":1000}`, i)
+			req, _ := http.NewRequest("POST", "/employee", bytes.NewBufferString(body))
+			testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool { return true })
+		}
+
+		req, _ := http.NewRequest("GET", "/employee?num_records=100000", nil)
+		var body struct {
+			Employees []map[string]any `json:"employees"`
+		}
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Code == http.StatusOK && json.Unmarshal(w.Body.Bytes(), &body) == nil
+		})
+		Expect(res).To(Equal(true))
+		Expect(body.Employees).To(HaveLen(5))
+	})
+})
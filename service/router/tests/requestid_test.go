@@ -0,0 +1,91 @@
+package router_test
+
+import (
+	"bytes"
+	"employee/pkg/auth"
+	"employee/pkg/logger"
+	"employee/pkg/testhelpers"
+	"employee/service/router"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+)
+
+var _ = Describe("Request IDs", func() {
+	var (
+		r    *gin.Engine
+		logs *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		logs = &bytes.Buffer{}
+		previous := logger.Log
+		logger.Log = zerolog.New(logs)
+		DeferCleanup(func() { logger.Log = previous })
+		r = router.NewRouter()
+	})
+
+	It("assigns an ID and echoes it", func() {
+		req, _ := http.NewRequest("GET", "/employee", nil)
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return len(w.Header().Get(router.RequestIDHeader)) == 32
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	It("propagates the caller's ID and replaces invalid ones", func() {
+		req, _ := http.NewRequest("GET", "/livez", nil)
+		req.Header.Set(router.RequestIDHeader, "batch-42")
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return w.Header().Get(router.RequestIDHeader) == "batch-42"
+		})
+		Expect(res).To(Equal(true))
+
+		req, _ = http.NewRequest("GET", "/livez", nil)
+		req.Header.Set(router.RequestIDHeader, strings.Repeat("x", 200))
+		res = testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			return len(w.Header().Get(router.RequestIDHeader)) == 32
+		})
+		Expect(res).To(Equal(true))
+	})
+
+	It("puts the ID in API error bodies and in the logs of every layer", func() {
+		secret := []byte("0123456789abcdef0123456789abcdef")
+		v, err := auth.NewVerifier(auth.Config{HMACSecret: secret})
+		Expect(err).To(BeNil())
+		r = router.NewRouter(router.WithAuthentication(v))
+		token := testhelpers.SignHS256(secret, "", map[string]any{
+			"sub": "alice", "exp": time.Now().Add(time.Hour).Unix(),
+		})
+
+		req, _ := http.NewRequest("GET", "/employee?id=abc", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set(router.RequestIDHeader, "req-1")
+		res := testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool {
+			var body map[string]any
+			return w.Code == http.StatusBadRequest &&
+				json.Unmarshal(w.Body.Bytes(), &body) == nil &&
+				body["request_id"] == "req-1"
+		})
+		Expect(res).To(Equal(true))
+
+		var logicLine map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+			var entry map[string]any
+			Expect(json.Unmarshal([]byte(line), &entry)).To(Succeed())
+			if entry[zerolog.MessageFieldName] == "Invalid employee ID" {
+				logicLine = entry
+			}
+		}
+		Expect(logicLine).To(HaveKeyWithValue("request_id", "req-1"))
+		Expect(logicLine).To(HaveKeyWithValue("subject", "alice"))
+		Expect(logicLine).To(HaveKeyWithValue("route", "/employee"))
+	})
+})
@@ -0,0 +1,30 @@
+package router_test
+
+import (
+	"employee/pkg/testhelpers"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Restore endpoint", func() {
+	var api *testhelpers.APIClient
+
+	BeforeEach(func() {
+		api = newAPI()
+		Expect(api.Do("POST", "/employee", `{"id":1,"name":"Ada","position":"Engineer","salary":100}`, "hr-admin", http.StatusOK, nil)).To(BeTrue())
+		Expect(api.Do("DELETE", "/employee?id=1", "", "hr-admin", http.StatusOK, nil)).To(BeTrue())
+	})
+
+	It("brings back a deleted employee", func() {
+		Expect(api.Do("GET", "/employee?id=1", "", "hr-admin", http.StatusBadRequest, nil)).To(BeTrue())
+		Expect(api.Do("POST", "/employees/1/restore", "", "hr-admin", http.StatusOK, nil)).To(BeTrue())
+		Expect(api.Do("GET", "/employee?id=1", "", "hr-admin", http.StatusOK, nil)).To(BeTrue())
+		Expect(api.Do("POST", "/employees/1/restore", "", "hr-admin", http.StatusBadRequest, nil)).To(BeTrue())
+	})
+
+	It("requires the permission to delete", func() {
+		Expect(api.Do("POST", "/employees/1/restore", "", "viewer", http.StatusForbidden, nil)).To(BeTrue())
+	})
+})
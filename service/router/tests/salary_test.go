@@ -0,0 +1,45 @@
+package router_test
+
+import (
+	"employee/models"
+	"employee/pkg/testhelpers"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Salary history endpoint", func() {
+	var api *testhelpers.APIClient
+
+	BeforeEach(func() {
+		api = newAPI()
+
+		Expect(api.Do("POST", "/employee", `{"id":1,"name":"Ada","position":"Engineer","salary":100}`, "hr-admin", http.StatusOK, nil)).To(BeTrue())
+		Expect(api.Do("PUT", "/employee?id=1", `{"salary":150,"effective_date":"2999-01-01","reason":"promotion"}`, "hr-admin", http.StatusOK, nil)).To(BeTrue())
+	})
+
+	It("lists past and scheduled changes", func() {
+		var res models.GetSalaryHistoryResponse
+		Expect(api.Do("GET", "/employees/1/salary-history", "", "hr-admin", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Changes).To(HaveLen(2))
+		Expect(res.Changes[1].Salary).To(Equal(models.MoneyOf(150)))
+		Expect(res.Changes[1].EffectiveDate).To(Equal("2999-01-01"))
+		Expect(res.Changes[1].Reason).To(Equal("promotion"))
+		Expect(res.Changes[1].RecordedBy).To(Equal("alice"))
+	})
+
+	It("returns the salary in effect on as_of", func() {
+		var res models.GetEmployeeResponse
+		Expect(api.Do("GET", "/employee?id=1", "", "hr-admin", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Employees[0].Salary).To(Equal(models.MoneyOf(100)))
+		Expect(api.Do("GET", "/employee?id=1&as_of=2999-06-30", "", "hr-admin", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Employees[0].Salary).To(Equal(models.MoneyOf(150)))
+		Expect(api.Do("GET", "/employee?as_of=tomorrow", "", "hr-admin", http.StatusBadRequest, nil)).To(BeTrue())
+		Expect(api.Do("GET", "/employee?known_at=yesterday", "", "hr-admin", http.StatusBadRequest, nil)).To(BeTrue())
+	})
+
+	It("forbids callers who may not see salaries", func() {
+		Expect(api.Do("GET", "/employees/1/salary-history", "", "viewer", http.StatusForbidden, nil)).To(BeTrue())
+	})
+})
@@ -0,0 +1,45 @@
+package router_test
+
+import (
+	"employee/models"
+	"employee/pkg/testhelpers"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Employee stats endpoint", func() {
+	var api *testhelpers.APIClient
+
+	BeforeEach(func() {
+		api = newAPI()
+
+		for _, body := range []string{
+			`{"id":1,"name":"Ada","position":"Engineer","salary":100}`,
+			`{"id":2,"name":"Alan","position":"Engineer","salary":"200.5"}`,
+			`{"id":3,"name":"Grace","position":"Lead","salary":300}`,
+		} {
+			Expect(api.Do("POST", "/employee", body, "hr-admin", http.StatusOK, nil)).To(BeTrue())
+		}
+	})
+
+	It("returns the salary statistics of each group", func() {
+		var res models.GetEmployeeStatsResponse
+		Expect(api.Do("GET", "/employees/stats?group_by=position&percentiles=50,90", "", "manager", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Stats).To(Equal([]models.SalaryStats{
+			{Group: "Engineer", Count: 2, Sum: models.MustParseMoney("300.5"), Mean: models.MustParseMoney("150.25"),
+				Median: models.MustParseMoney("150.25"), Currency: "USD",
+				Percentiles: map[string]models.Money{"p50": models.MustParseMoney("150.25"), "p90": models.MustParseMoney("190.45")}},
+			{Group: "Lead", Count: 1, Sum: models.MoneyOf(300), Mean: models.MoneyOf(300), Median: models.MoneyOf(300), Currency: "USD",
+				Percentiles: map[string]models.Money{"p50": models.MoneyOf(300), "p90": models.MoneyOf(300)}},
+		}))
+		Expect(api.Do("GET", "/employees/stats?group_by=salary", "", "manager", http.StatusBadRequest, nil)).To(BeTrue())
+	})
+
+	It("hides the salary figures from callers who cannot see salaries", func() {
+		var res models.GetEmployeeStatsResponse
+		Expect(api.Do("GET", "/employees/stats?position=Engineer", "", "viewer", http.StatusOK, &res)).To(BeTrue())
+		Expect(res.Stats).To(Equal([]models.SalaryStats{{Count: 2, Currency: "USD"}}))
+	})
+})
@@ -0,0 +1,63 @@
+package router_test
+
+import (
+	"bytes"
+	"employee/pkg/testhelpers"
+	"employee/pkg/tracing"
+	"employee/service/router"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tracing", func() {
+	var (
+		r        *gin.Engine
+		exporter *tracing.MemoryExporter
+	)
+
+	BeforeEach(func() {
+		exporter = tracing.NewMemoryExporter()
+		tracing.SetTracer(tracing.NewTracer(exporter))
+		DeferCleanup(tracing.SetTracer, (*tracing.Tracer)(nil))
+		r = router.NewRouter()
+	})
+
+	It("links the handler, logic and storage spans under the caller's trace", func() {
+		body := bytes.NewBufferString(`{"id":1,"name":"Ada","position":"Engineer","salary":100}`)
+		req, _ := http.NewRequest("POST", "/employee", body)
+		req.Header.Set(tracing.TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool { return true })
+
+		spans := exporter.Spans()
+		// The employee, its starting salary and its first version are stored separately
+		Expect(len(spans)).To(BeNumerically(">", 3))
+		logic, server := spans[len(spans)-2], spans[len(spans)-1]
+		Expect(logic.Name).To(Equal("employee.CreateEmployee"))
+		Expect(server.Name).To(Equal("POST /employee"))
+		for _, storage := range spans[:len(spans)-2] {
+			Expect(storage.Name).To(HavePrefix("simpledb."))
+			Expect(storage.ParentID).To(Equal(logic.SpanID))
+		}
+		for _, s := range spans {
+			Expect(s.TraceID).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+		}
+		Expect(server.ParentID).To(Equal("00f067aa0ba902b7"))
+		Expect(logic.ParentID).To(Equal(server.SpanID))
+		Expect(server.Attributes).To(ContainElement(tracing.Attribute{Key: "http.status_code", Value: "200"}))
+	})
+
+	It("records the API error on the failed spans", func() {
+		req, _ := http.NewRequest("GET", "/employee?id=42", nil)
+		testhelpers.TestHTTPResponse(r, req, func(w *httptest.ResponseRecorder) bool { return true })
+
+		spans := exporter.Spans()
+		Expect(spans).To(HaveLen(3))
+		Expect(spans[1].Error).To(Equal("Provide a valid ID"))
+		Expect(spans[2].Error).To(Equal("Provide a valid ID"))
+		Expect(spans[2].ParentID).To(BeEmpty())
+	})
+})
@@ -0,0 +1,40 @@
+package router
+
+import (
+	"employee/pkg/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Trace starts a server span for every request, continuing the trace of the
+// caller's traceparent header when it is valid. The span is put in the request
+// context so the logic and storage spans become its children.
+func Trace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if sc, err := tracing.ParseTraceparent(c.GetHeader(tracing.TraceparentHeader)); err == nil {
+			ctx = tracing.ContextWithRemoteParent(ctx, sc)
+		}
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		ctx, span := tracing.Start(ctx, c.Request.Method+" "+route)
+		if span == nil {
+			c.Request = c.Request.WithContext(ctx)
+			c.Next()
+			return
+		}
+		defer span.End()
+		span.SetAttribute("http.method", c.Request.Method)
+		span.SetAttribute("http.route", route)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		span.SetAttribute("http.status_code", c.Writer.Status())
+		if err := c.Errors.Last(); err != nil {
+			span.SetError(err.Err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"employee/pkg/config"
+	"employee/pkg/logger"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Server serves HTTP until its context is cancelled and then drains in-flight requests.
+type Server struct {
+	http            *http.Server
+	shutdownTimeout time.Duration
+	shutdownDelay   time.Duration
+	onShutdown      []func()
+}
+
+// New creates a Server for handler with the timeouts of cfg. It serves HTTPS when
+// tlsConfig is not nil, see CertReloader.
+//
+// It returns a pointer to the newly created Server struct.
+func New(cfg config.ServerConfig, tlsConfig *tls.Config, handler http.Handler) *Server {
+	return &Server{
+		http: &http.Server{
+			Addr:              cfg.Address,
+			Handler:           handler,
+			TLSConfig:         tlsConfig,
+			ReadTimeout:       cfg.ReadTimeout,
+			ReadHeaderTimeout: cfg.ReadTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+		},
+		shutdownTimeout: cfg.ShutdownTimeout,
+		shutdownDelay:   cfg.ShutdownDelay,
+	}
+}
+
+// OnShutdown registers f to be called as soon as a shutdown begins, before the
+// shutdown delay and before in-flight requests are drained.
+func (s *Server) OnShutdown(f func()) {
+	s.onShutdown = append(s.onShutdown, f)
+}
+
+// Run listens on the configured address and serves until ctx is done. It then stops
+// accepting connections and waits up to the shutdown timeout for in-flight requests.
+//
+// It returns nil after a clean shutdown, or the error that stopped the server.
+func (s *Server) Run(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve is like Run but accepts connections on ln.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Log.Info().Str("address", ln.Addr().String()).Bool("tls", s.http.TLSConfig != nil).Msg("Server listening")
+		if s.http.TLSConfig != nil {
+			// The certificates are provided by the TLS configuration
+			errCh <- s.http.ServeTLS(ln, "", "")
+		} else {
+			errCh <- s.http.Serve(ln)
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	for _, f := range s.onShutdown {
+		f()
+	}
+	if s.shutdownDelay > 0 {
+		// Keep serving while load balancers notice that readiness fails
+		logger.Log.Info().Dur("delay", s.shutdownDelay).Msg("Shutting down, waiting before closing the listener")
+		time.Sleep(s.shutdownDelay)
+	}
+
+	logger.Log.Info().Dur("timeout", s.shutdownTimeout).Msg("Shutting down, draining in-flight requests")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	if err := s.http.Shutdown(shutdownCtx); err != nil {
+		// The deadline passed with requests still running, cut them off
+		logger.Log.Error().Err(err).Msg("Failed to drain in-flight requests")
+		s.http.Close()
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	logger.Log.Info().Msg("Server stopped")
+	return nil
+}
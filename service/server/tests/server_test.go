@@ -0,0 +1,103 @@
+package server_test
+
+import (
+	"context"
+	"employee/pkg/config"
+	"employee/service/server"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		cfg      config.ServerConfig
+		ln       net.Listener
+		started  chan struct{}
+		release  chan struct{}
+		handler  http.Handler
+		shutdown chan struct{}
+	)
+
+	BeforeEach(func() {
+		cfg = config.Default().Server
+		cfg.ShutdownDelay = 0
+		var err error
+		ln, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+		started = make(chan struct{})
+		release = make(chan struct{})
+		shutdown = make(chan struct{})
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			io.WriteString(w, "done")
+		})
+	})
+
+	serve := func(ctx context.Context) chan error {
+		srv := server.New(cfg, nil, handler)
+		srv.OnShutdown(func() { close(shutdown) })
+		errCh := make(chan error, 1)
+		go func() { errCh <- srv.Serve(ctx, ln) }()
+		return errCh
+	}
+
+	It("should drain in-flight requests before returning", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := serve(ctx)
+
+		respCh := make(chan string, 1)
+		go func() {
+			defer GinkgoRecover()
+			resp, err := http.Get("http://" + ln.Addr().String())
+			Expect(err).To(BeNil())
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			respCh <- string(body)
+		}()
+
+		<-started
+		cancel()
+		Eventually(shutdown).Should(BeClosed())
+		Consistently(errCh, 100*time.Millisecond).ShouldNot(Receive())
+
+		close(release)
+		Eventually(respCh).Should(Receive(Equal("done")))
+		Eventually(errCh).Should(Receive(BeNil()))
+	})
+
+	It("should give up once the shutdown timeout passes", func() {
+		cfg.ShutdownTimeout = 50 * time.Millisecond
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := serve(ctx)
+
+		go http.Get("http://" + ln.Addr().String())
+		<-started
+		cancel()
+
+		Eventually(errCh).Should(Receive(MatchError(context.DeadlineExceeded)))
+		close(release)
+	})
+
+	It("should keep serving during the shutdown delay", func() {
+		cfg.ShutdownDelay = 300 * time.Millisecond
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "ok")
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := serve(ctx)
+
+		cancel()
+		Eventually(shutdown).Should(BeClosed())
+		resp, err := http.Get("http://" + ln.Addr().String())
+		Expect(err).To(BeNil())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Eventually(errCh).Should(Receive(BeNil()))
+	})
+})
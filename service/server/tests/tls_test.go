@@ -0,0 +1,146 @@
+package server_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"employee/pkg/auth"
+	"employee/pkg/config"
+	"employee/pkg/testhelpers"
+	"employee/service/server"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TLS", func() {
+	var (
+		dir    string
+		ca     *testhelpers.TestCA
+		tlsCfg config.TLSConfig
+		ln     net.Listener
+		cancel context.CancelFunc
+	)
+
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, data, 0o600)).To(Succeed())
+		return path
+	}
+
+	issueServerCert := func(cn string) {
+		certPEM, keyPEM := ca.Issue(cn, true)
+		write("server.crt", certPEM)
+		write("server.key", keyPEM)
+	}
+
+	clientFor := func(certs ...tls.Certificate) *http.Client {
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.Cert)
+		return &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{RootCAs: roots, Certificates: certs},
+			DisableKeepAlives: true,
+		}}
+	}
+
+	start := func() *server.CertReloader {
+		reloader, err := server.NewCertReloader(tlsCfg)
+		Expect(err).To(BeNil())
+		ln, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(BeNil())
+
+		// Echo the verified client identity the authorization layer would see
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if id := auth.ClientIdentityFromTLS(r.TLS); id != nil {
+				io.WriteString(w, id.CommonName)
+			}
+		})
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		go reloader.Watch(ctx, 10*time.Millisecond)
+		go server.New(config.Default().Server, reloader.TLSConfig(), handler).Serve(ctx, ln)
+		return reloader
+	}
+
+	get := func(client *http.Client) (string, error) {
+		resp, err := client.Get("https://" + ln.Addr().String())
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		ca = testhelpers.NewTestCA("test-ca")
+		issueServerCert("server-1")
+		tlsCfg = config.TLSConfig{
+			Enabled:      true,
+			CertFile:     filepath.Join(dir, "server.crt"),
+			KeyFile:      filepath.Join(dir, "server.key"),
+			ClientCAFile: write("ca.crt", ca.PEM),
+			ClientAuth:   config.ClientAuthRequire,
+		}
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("should expose the verified client certificate identity", func() {
+		start()
+		certPEM, keyPEM := ca.Issue("payroll-batch", false)
+		clientCert, err := tls.X509KeyPair(certPEM, keyPEM)
+		Expect(err).To(BeNil())
+
+		body, err := get(clientFor(clientCert))
+		Expect(err).To(BeNil())
+		Expect(body).To(Equal("payroll-batch"))
+	})
+
+	It("should reject clients without a certificate when client auth is required", func() {
+		start()
+		_, err := get(clientFor())
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should reject client certificates from another CA", func() {
+		start()
+		certPEM, keyPEM := testhelpers.NewTestCA("other-ca").Issue("intruder", false)
+		clientCert, err := tls.X509KeyPair(certPEM, keyPEM)
+		Expect(err).To(BeNil())
+
+		_, err = get(clientFor(clientCert))
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should serve a replaced certificate without a restart", func() {
+		tlsCfg.ClientAuth = config.ClientAuthNone
+		start()
+
+		peerCN := func() string {
+			conn, err := tls.Dial("tcp", ln.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+			Expect(err).To(BeNil())
+			defer conn.Close()
+			return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+		}
+		Expect(peerCN()).To(Equal("server-1"))
+
+		// Make sure the modification time differs from the first write
+		time.Sleep(20 * time.Millisecond)
+		issueServerCert("server-2")
+		future := time.Now().Add(time.Second)
+		Expect(os.Chtimes(tlsCfg.CertFile, future, future)).To(Succeed())
+		Expect(os.Chtimes(tlsCfg.KeyFile, future, future)).To(Succeed())
+
+		Eventually(peerCN).Should(Equal("server-2"))
+	})
+})
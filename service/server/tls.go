@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"employee/pkg/config"
+	"employee/pkg/logger"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// CertReloader serves the certificate and client CA bundle of a TLS configuration
+// and reloads them when their files change, without restarting the listener.
+type CertReloader struct {
+	cfg     config.TLSConfig
+	mutex   sync.RWMutex
+	cert    *tls.Certificate
+	roots   *x509.CertPool
+	modTime map[string]time.Time
+}
+
+// NewCertReloader loads the certificate, key and optional client CA bundle of cfg.
+//
+// It returns an error if any of the files cannot be loaded.
+func NewCertReloader(cfg config.TLSConfig) (*CertReloader, error) {
+	r := &CertReloader{cfg: cfg, modTime: map[string]time.Time{}}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads the files again. On error the previously loaded material stays in use.
+func (r *CertReloader) Reload() error {
+	modTime := map[string]time.Time{}
+	for _, path := range r.files() {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		modTime[path] = fi.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate: %w", err)
+	}
+	var roots *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("read client CA bundle: %w", err)
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return errors.New("client CA bundle contains no certificate")
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.cert = &cert
+	r.roots = roots
+	r.modTime = modTime
+	return nil
+}
+
+// Leaf returns the certificate currently served.
+func (r *CertReloader) Leaf() *x509.Certificate {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if r.cert == nil {
+		return nil
+	}
+	return r.cert.Leaf
+}
+
+// Watch polls the files every interval and reloads them when one changes, until ctx is done.
+func (r *CertReloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !r.changed() {
+			continue
+		}
+		if err := r.Reload(); err != nil {
+			logger.Log.Error().Err(err).Msg("Failed to reload TLS certificates, keeping the previous ones")
+			continue
+		}
+		logger.Log.Info().Str("cert", r.cfg.CertFile).Msg("Reloaded TLS certificates")
+	}
+}
+
+func (r *CertReloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	return files
+}
+
+func (r *CertReloader) changed() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for _, path := range r.files() {
+		fi, err := os.Stat(path)
+		if err != nil {
+			// The file is being replaced, try again on the next tick
+			return false
+		}
+		if !fi.ModTime().Equal(r.modTime[path]) {
+			return true
+		}
+	}
+	return false
+}
+
+// TLSConfig returns a configuration that always uses the most recently loaded material.
+func (r *CertReloader) TLSConfig() *tls.Config {
+	base := &tls.Config{MinVersion: tls.VersionTLS12}
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		r.mutex.RLock()
+		defer r.mutex.RUnlock()
+		cfg := &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{*r.cert},
+			ClientAuth:   clientAuthType(r.cfg.ClientAuth),
+			ClientCAs:    r.roots,
+		}
+		return cfg, nil
+	}
+	return base
+}
+
+func clientAuthType(mode string) tls.ClientAuthType {
+	switch mode {
+	case config.ClientAuthOptional:
+		return tls.VerifyClientCertIfGiven
+	case config.ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert
+	default:
+		return tls.NoClientCert
+	}
+}
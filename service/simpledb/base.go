@@ -2,11 +2,16 @@ package simpledb
 
 import (
 	"sync"
+	"sync/atomic"
 
 	orderedmap "github.com/wk8/go-ordered-map"
 )
 
 type Database[I comparable, T any] struct {
-	data  *orderedmap.OrderedMap
-	mutex sync.RWMutex
+	data    *orderedmap.OrderedMap
+	mutex   sync.RWMutex
+	journal *journal
+	closed  bool
+	loading atomic.Bool
+	stats   stats
 }
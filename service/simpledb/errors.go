@@ -6,4 +6,6 @@ var (
 	KeyAlreadyPresent    = errors.New("Key already present")
 	KeyAbsent            = errors.New("Key absent")
 	InvalidLastEvalKeyID = errors.New("Invalid last ID")
+	DatabaseClosed       = errors.New("Database closed")
+	DatabaseLoading      = errors.New("Database is loading")
 )
@@ -1,15 +1,21 @@
 package simpledb
 
-import orderedmap "github.com/wk8/go-ordered-map"
+import (
+	"context"
+	"employee/pkg/logger"
+
+	orderedmap "github.com/wk8/go-ordered-map"
+)
 
 func (db *Database[I, T]) Init() *Database[I, T] {
 	db.data = orderedmap.New()
 	return db
 }
 
-func (db *Database[I, T]) GetItem(key I) (T, bool) {
-	db.mutex.RLock()
+func (db *Database[I, T]) GetItem(ctx context.Context, key I) (T, bool) {
+	span := db.rlock(ctx, OpGetItem)
 	defer db.mutex.RUnlock()
+	defer span.End()
 	i, p := db.data.Get(key)
 	var zeroVal T
 	if i == nil {
@@ -18,39 +24,69 @@ func (db *Database[I, T]) GetItem(key I) (T, bool) {
 	return i.(T), p
 }
 
-func (db *Database[I, T]) SetItem(key I, value T) error {
-	db.mutex.Lock()
+func (db *Database[I, T]) SetItem(ctx context.Context, key I, value T) error {
+	span := db.lock(ctx, OpSetItem)
 	defer db.mutex.Unlock()
+	defer span.End()
+	if db.closed {
+		return DatabaseClosed
+	}
 	if _, present := db.data.Get(key); present {
 		return KeyAlreadyPresent
 	}
+	if err := db.append(opSet, key, &value); err != nil {
+		span.SetError(err)
+		logger.Ctx(ctx).Error().Err(err).Str("op", OpSetItem).Msg("Failed to append to the journal")
+		return err
+	}
 	db.data.Set(key, value)
+	db.updateItems()
 	return nil
 }
 
-func (db *Database[I, T]) UpdateItem(key I, value T) error {
-	db.mutex.Lock()
+func (db *Database[I, T]) UpdateItem(ctx context.Context, key I, value T) error {
+	span := db.lock(ctx, OpUpdateItem)
 	defer db.mutex.Unlock()
+	defer span.End()
+	if db.closed {
+		return DatabaseClosed
+	}
 	if _, present := db.data.Get(key); !present {
 		return KeyAbsent
 	}
+	if err := db.append(opUpdate, key, &value); err != nil {
+		span.SetError(err)
+		logger.Ctx(ctx).Error().Err(err).Str("op", OpUpdateItem).Msg("Failed to append to the journal")
+		return err
+	}
 	db.data.Set(key, value)
 	return nil
 }
 
-func (db *Database[I, T]) DeleteItem(key I) error {
-	db.mutex.Lock()
+func (db *Database[I, T]) DeleteItem(ctx context.Context, key I) error {
+	span := db.lock(ctx, OpDeleteItem)
 	defer db.mutex.Unlock()
+	defer span.End()
+	if db.closed {
+		return DatabaseClosed
+	}
 	if _, present := db.data.Get(key); !present {
 		return KeyAbsent
 	}
+	if err := db.append(opDelete, key, nil); err != nil {
+		span.SetError(err)
+		logger.Ctx(ctx).Error().Err(err).Str("op", OpDeleteItem).Msg("Failed to append to the journal")
+		return err
+	}
 	db.data.Delete(key)
+	db.updateItems()
 	return nil
 }
 
-func (db *Database[I, T]) GetItems(LastEvalKeyID I, numItems int) ([]T, I, error) {
-	db.mutex.RLock()
+func (db *Database[I, T]) GetItems(ctx context.Context, LastEvalKeyID I, numItems int) ([]T, I, error) {
+	span := db.rlock(ctx, OpGetItems)
 	defer db.mutex.RUnlock()
+	defer span.End()
 
 	var lastItem *orderedmap.Pair
 	var items []T
@@ -89,3 +125,36 @@ func (db *Database[I, T]) GetItems(LastEvalKeyID I, numItems int) ([]T, I, error
 
 	return items, lastID, nil
 }
+
+// Check reports whether the database can serve requests. It fails when the database
+// is closed, when its journal can no longer be written, or when the lock cannot be
+// acquired before ctx is done.
+func (db *Database[I, T]) Check(ctx context.Context) error {
+	if db.loading.Load() {
+		return DatabaseLoading
+	}
+	acquired := make(chan struct{})
+	go func() {
+		db.mutex.RLock()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+	case <-ctx.Done():
+		// The read lock is released by the goroutine once it is finally acquired
+		go func() {
+			<-acquired
+			db.mutex.RUnlock()
+		}()
+		return ctx.Err()
+	}
+	defer db.mutex.RUnlock()
+
+	if db.closed {
+		return DatabaseClosed
+	}
+	if db.journal != nil && db.journal.err != nil {
+		return db.journal.err
+	}
+	return nil
+}
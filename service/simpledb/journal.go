@@ -0,0 +1,170 @@
+package simpledb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	opSet    = "set"
+	opUpdate = "update"
+	opDelete = "delete"
+)
+
+// record is a single line of the journal.
+type record[I comparable, T any] struct {
+	Op    string `json:"op"`
+	Key   I      `json:"key"`
+	Value *T     `json:"value,omitempty"`
+}
+
+// journal appends every mutation to a JSON lines file so the database can be
+// rebuilt by replaying it on start.
+type journal struct {
+	file *os.File
+	w    *bufio.Writer
+	// err is the first write error. Once set, the journal may end in a torn record
+	// and every further write is refused.
+	err error
+}
+
+// Open creates a database persisted to the journal at path. An existing journal
+// is replayed first; a partially written trailing record, left behind by a crash,
+// is discarded.
+//
+// It returns a pointer to the database, or an error if the journal cannot be read.
+func Open[I comparable, T any](path string) (*Database[I, T], error) {
+	var db Database[I, T]
+	if err := db.Init().StartLoad()(path); err != nil {
+		return nil, err
+	}
+	return &db, nil
+}
+
+// StartLoad locks the empty database and returns a function that replays the journal
+// at path, keeps appending to it and unlocks the database. Operations issued in
+// between block until loading completes, so the database can be handed out before
+// its journal has been read. If loading fails the database is closed.
+func (db *Database[I, T]) StartLoad() func(path string) error {
+	db.mutex.Lock()
+	db.loading.Store(true)
+	return func(path string) error {
+		defer db.mutex.Unlock()
+		defer db.loading.Store(false)
+		if err := db.load(path); err != nil {
+			db.closed = true
+			return err
+		}
+		db.updateItems()
+		return nil
+	}
+}
+
+func (db *Database[I, T]) load(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("open journal: %w", err)
+	}
+	valid, err := db.replay(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	// Drop a torn trailing record and continue appending after the last valid one
+	if err := f.Truncate(valid); err != nil {
+		f.Close()
+		return fmt.Errorf("truncate journal: %w", err)
+	}
+	if _, err := f.Seek(valid, io.SeekStart); err != nil {
+		f.Close()
+		return fmt.Errorf("seek journal: %w", err)
+	}
+
+	db.journal = &journal{file: f, w: bufio.NewWriter(f)}
+	return nil
+}
+
+// replay applies every complete record of r and returns the offset after the last one.
+func (db *Database[I, T]) replay(r io.Reader) (int64, error) {
+	reader := bufio.NewReader(r)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// Anything after the last newline is an incomplete record
+			return offset, nil
+		}
+		if err != nil {
+			return offset, fmt.Errorf("read journal: %w", err)
+		}
+
+		var rec record[I, T]
+		if err := json.Unmarshal(bytes.TrimSpace(line), &rec); err != nil {
+			return offset, fmt.Errorf("corrupt journal record at offset %d: %w", offset, err)
+		}
+		switch rec.Op {
+		case opSet, opUpdate:
+			if rec.Value == nil {
+				return offset, fmt.Errorf("corrupt journal record at offset %d: missing value", offset)
+			}
+			db.data.Set(rec.Key, *rec.Value)
+		case opDelete:
+			db.data.Delete(rec.Key)
+		default:
+			return offset, fmt.Errorf("corrupt journal record at offset %d: unknown op %q", offset, rec.Op)
+		}
+		offset += int64(len(line))
+	}
+}
+
+// append writes a record to the journal. It must be called with the write lock held,
+// before the mutation is applied in memory.
+func (db *Database[I, T]) append(op string, key I, value *T) error {
+	if db.journal == nil {
+		return nil
+	}
+	if db.journal.err != nil {
+		return db.journal.err
+	}
+	line, err := json.Marshal(record[I, T]{Op: op, Key: key, Value: value})
+	if err != nil {
+		return err
+	}
+	if _, err := db.journal.w.Write(append(line, '\n')); err != nil {
+		db.journal.err = fmt.Errorf("journal write failed: %w", err)
+		return db.journal.err
+	}
+	// Hand the record to the OS so it survives a crash of the process
+	if err := db.journal.w.Flush(); err != nil {
+		db.journal.err = fmt.Errorf("journal write failed: %w", err)
+		return db.journal.err
+	}
+	return nil
+}
+
+// Close flushes the journal to stable storage and closes it. Writes made after
+// Close fail with DatabaseClosed, so nothing is acknowledged that was not persisted.
+func (db *Database[I, T]) Close() error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.closed = true
+	if db.journal == nil {
+		return nil
+	}
+	j := db.journal
+	db.journal = nil
+	if err := j.w.Flush(); err != nil {
+		j.file.Close()
+		return err
+	}
+	if err := j.file.Sync(); err != nil {
+		j.file.Close()
+		return err
+	}
+	return j.file.Close()
+}
@@ -0,0 +1,96 @@
+package simpledb
+
+import (
+	"context"
+	"employee/pkg/tracing"
+	"sync/atomic"
+	"time"
+)
+
+// Operation names reported by Stats.
+const (
+	OpGetItem    = "GetItem"
+	OpGetItems   = "GetItems"
+	OpSetItem    = "SetItem"
+	OpUpdateItem = "UpdateItem"
+	OpDeleteItem = "DeleteItem"
+)
+
+// Operations lists every operation reported by Stats.
+var Operations = []string{OpGetItem, OpGetItems, OpSetItem, OpUpdateItem, OpDeleteItem}
+
+type opStats struct {
+	count    atomic.Uint64
+	lockWait atomic.Int64
+}
+
+type stats struct {
+	items atomic.Int64
+	ops   [5]opStats
+}
+
+// OperationStats are the totals of one operation since the database was created.
+type OperationStats struct {
+	Count    uint64
+	LockWait time.Duration
+}
+
+// Stats is a snapshot of the database counters.
+type Stats struct {
+	Items      int
+	Operations map[string]OperationStats
+}
+
+// Stats returns the item count and per operation totals without taking the lock,
+// so it can be scraped while the database is busy.
+func (db *Database[I, T]) Stats() Stats {
+	s := Stats{Items: int(db.stats.items.Load()), Operations: map[string]OperationStats{}}
+	for i, op := range Operations {
+		s.Operations[op] = OperationStats{
+			Count:    db.stats.ops[i].count.Load(),
+			LockWait: time.Duration(db.stats.ops[i].lockWait.Load()),
+		}
+	}
+	return s
+}
+
+func opIndex(op string) int {
+	for i, o := range Operations {
+		if o == op {
+			return i
+		}
+	}
+	panic("simpledb: unknown operation " + op)
+}
+
+// lock starts the span of op and takes the write lock, recording how long it waited.
+//
+// It returns the span, which the caller ends.
+func (db *Database[I, T]) lock(ctx context.Context, op string) *tracing.Span {
+	_, span := tracing.Start(ctx, "simpledb."+op)
+	start := time.Now()
+	db.mutex.Lock()
+	db.record(span, op, time.Since(start))
+	return span
+}
+
+// rlock is like lock for the read lock.
+func (db *Database[I, T]) rlock(ctx context.Context, op string) *tracing.Span {
+	_, span := tracing.Start(ctx, "simpledb."+op)
+	start := time.Now()
+	db.mutex.RLock()
+	db.record(span, op, time.Since(start))
+	return span
+}
+
+func (db *Database[I, T]) record(span *tracing.Span, op string, wait time.Duration) {
+	s := &db.stats.ops[opIndex(op)]
+	s.count.Add(1)
+	s.lockWait.Add(int64(wait))
+	span.SetAttribute("db.lock_wait_us", wait.Microseconds())
+}
+
+// updateItems refreshes the item count, the write lock must be held.
+func (db *Database[I, T]) updateItems() {
+	db.stats.items.Store(int64(db.data.Len()))
+}
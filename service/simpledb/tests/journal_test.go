@@ -0,0 +1,124 @@
+package simpledb_test
+
+import (
+	"context"
+	"employee/service/simpledb"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type item struct {
+	Name string `json:"name"`
+}
+
+var _ = Describe("Journal", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "items.journal")
+	})
+
+	It("should restore every mutation in order after reopening", func() {
+		db, err := simpledb.Open[int, item](path)
+		Expect(err).To(BeNil())
+		Expect(db.SetItem(context.Background(), 1, item{Name: "a"})).To(Succeed())
+		Expect(db.SetItem(context.Background(), 2, item{Name: "b"})).To(Succeed())
+		Expect(db.SetItem(context.Background(), 3, item{Name: "c"})).To(Succeed())
+		Expect(db.UpdateItem(context.Background(), 2, item{Name: "b2"})).To(Succeed())
+		Expect(db.DeleteItem(context.Background(), 1)).To(Succeed())
+		Expect(db.Close()).To(Succeed())
+
+		db, err = simpledb.Open[int, item](path)
+		Expect(err).To(BeNil())
+		items, _, err := db.GetItems(context.Background(), 0, 10)
+		Expect(err).To(BeNil())
+		Expect(items).To(Equal([]item{{Name: "b2"}, {Name: "c"}}))
+		Expect(db.Close()).To(Succeed())
+	})
+
+	It("should discard a torn trailing record", func() {
+		db, err := simpledb.Open[int, item](path)
+		Expect(err).To(BeNil())
+		Expect(db.SetItem(context.Background(), 1, item{Name: "a"})).To(Succeed())
+		Expect(db.Close()).To(Succeed())
+
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+		Expect(err).To(BeNil())
+		_, err = f.WriteString(`{"op":"set","key":2,"val`)
+		Expect(err).To(BeNil())
+		Expect(f.Close()).To(Succeed())
+
+		db, err = simpledb.Open[int, item](path)
+		Expect(err).To(BeNil())
+		Expect(db.SetItem(context.Background(), 2, item{Name: "b"})).To(Succeed())
+		Expect(db.Close()).To(Succeed())
+
+		db, err = simpledb.Open[int, item](path)
+		Expect(err).To(BeNil())
+		items, _, err := db.GetItems(context.Background(), 0, 10)
+		Expect(err).To(BeNil())
+		Expect(items).To(Equal([]item{{Name: "a"}, {Name: "b"}}))
+	})
+
+	It("should fail on a corrupt record", func() {
+		Expect(os.WriteFile(path, []byte("garbage\n"), 0o600)).To(Succeed())
+		_, err := simpledb.Open[int, item](path)
+		Expect(err).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("Close", func() {
+	It("should reject writes after the database is closed", func() {
+		db, err := simpledb.Open[int, item](filepath.Join(GinkgoT().TempDir(), "items.journal"))
+		Expect(err).To(BeNil())
+		Expect(db.Close()).To(Succeed())
+		Expect(db.SetItem(context.Background(), 1, item{Name: "a"})).To(MatchError(simpledb.DatabaseClosed))
+	})
+})
+
+var _ = Describe("Check", func() {
+	It("should report loading until the journal is replayed", func() {
+		var db simpledb.Database[int, item]
+		load := db.Init().StartLoad()
+		Expect(db.Check(context.Background())).To(MatchError(simpledb.DatabaseLoading))
+
+		Expect(load(filepath.Join(GinkgoT().TempDir(), "items.journal"))).To(Succeed())
+		Expect(db.Check(context.Background())).To(Succeed())
+
+		Expect(db.Close()).To(Succeed())
+		Expect(db.Check(context.Background())).To(MatchError(simpledb.DatabaseClosed))
+	})
+})
+
+var _ = Describe("Stats", func() {
+	It("should count items and operations", func() {
+		var db simpledb.Database[int, item]
+		db.Init()
+		Expect(db.SetItem(context.Background(), 1, item{Name: "a"})).To(Succeed())
+		Expect(db.SetItem(context.Background(), 2, item{Name: "b"})).To(Succeed())
+		Expect(db.DeleteItem(context.Background(), 1)).To(Succeed())
+		db.GetItem(context.Background(), 2)
+
+		stats := db.Stats()
+		Expect(stats.Items).To(Equal(1))
+		Expect(stats.Operations[simpledb.OpSetItem].Count).To(Equal(uint64(2)))
+		Expect(stats.Operations[simpledb.OpDeleteItem].Count).To(Equal(uint64(1)))
+		Expect(stats.Operations[simpledb.OpGetItem].Count).To(Equal(uint64(1)))
+		Expect(stats.Operations[simpledb.OpGetItems].Count).To(BeZero())
+	})
+
+	It("should count the items replayed from the journal", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "items.journal")
+		db, err := simpledb.Open[int, item](path)
+		Expect(err).To(BeNil())
+		Expect(db.SetItem(context.Background(), 1, item{Name: "a"})).To(Succeed())
+		Expect(db.Close()).To(Succeed())
+
+		db, err = simpledb.Open[int, item](path)
+		Expect(err).To(BeNil())
+		Expect(db.Stats().Items).To(Equal(1))
+	})
+})
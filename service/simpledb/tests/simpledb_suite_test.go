@@ -0,0 +1,13 @@
+package simpledb_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSimpleDB(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SimpleDB Suite")
+}
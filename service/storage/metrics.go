@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"employee/pkg/metrics"
+	"employee/service/simpledb"
+)
+
+// RegisterMetrics exports the item count, operation count and lock wait time of
+// every database on reg. The values are read on each scrape.
+func (s *Storage) RegisterMetrics(reg *metrics.Registry) {
+	snapshot := func() map[string]simpledb.Stats {
+		return map[string]simpledb.Stats{
+			"employees":   s.Employees.Stats(),
+			"apikeys":     s.APIKeys.Stats(),
+			"audit":       s.Audit.Stats(),
+			"salaries":    s.Salaries.Stats(),
+			"versions":    s.Versions.Stats(),
+			"departments": s.Departments.Stats(),
+			"positions":   s.Positions.Stats(),
+		}
+	}
+	databases := []string{"apikeys", "audit", "departments", "employees", "positions", "salaries", "versions"}
+
+	reg.NewGaugeFunc("simpledb_items", "Number of items stored in the database.", []string{"db"}, func() []metrics.Sample {
+		stats := snapshot()
+		var samples []metrics.Sample
+		for _, db := range databases {
+			samples = append(samples, metrics.Sample{Value: float64(stats[db].Items), LabelValues: []string{db}})
+		}
+		return samples
+	})
+	perOperation := func(value func(simpledb.OperationStats) float64) func() []metrics.Sample {
+		return func() []metrics.Sample {
+			stats := snapshot()
+			var samples []metrics.Sample
+			for _, db := range databases {
+				for _, op := range simpledb.Operations {
+					samples = append(samples, metrics.Sample{Value: value(stats[db].Operations[op]), LabelValues: []string{db, op}})
+				}
+			}
+			return samples
+		}
+	}
+	reg.NewCounterFunc("simpledb_operations_total", "Database operations by method.", []string{"db", "method"},
+		perOperation(func(o simpledb.OperationStats) float64 { return float64(o.Count) }))
+	reg.NewCounterFunc("simpledb_lock_wait_seconds_total", "Time spent waiting for the database lock by method.", []string{"db", "method"},
+		perOperation(func(o simpledb.OperationStats) float64 { return o.LockWait.Seconds() }))
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"employee/models"
+	"employee/pkg/config"
+	"employee/service/simpledb"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var errNotLoaded = errors.New("storage has not finished loading")
+
+// Storage holds the databases of the service.
+type Storage struct {
+	Employees *simpledb.Database[int, models.Employee]
+	APIKeys   *simpledb.Database[string, models.StoredAPIKey]
+	Audit     *simpledb.Database[uint64, models.AuditEntry]
+	Salaries  *simpledb.Database[int, models.SalaryHistory]
+	Versions  *simpledb.Database[int, models.EmployeeVersions]
+	// Departments is keyed by department ID.
+	Departments *simpledb.Database[string, models.Department]
+	// Positions is keyed by position title.
+	Positions *simpledb.Database[string, models.Position]
+
+	cfg     config.StorageConfig
+	loaders []func() error
+	mutex   sync.Mutex
+	loaded  bool
+	loadErr error
+}
+
+// New creates the databases of the configured backend without reading any data.
+// Until Load returns, every operation on a file backed database blocks.
+func New(cfg config.StorageConfig) *Storage {
+	var emps simpledb.Database[int, models.Employee]
+	var keys simpledb.Database[string, models.StoredAPIKey]
+	var trail simpledb.Database[uint64, models.AuditEntry]
+	var salaries simpledb.Database[int, models.SalaryHistory]
+	var versions simpledb.Database[int, models.EmployeeVersions]
+	var departments simpledb.Database[string, models.Department]
+	var positions simpledb.Database[string, models.Position]
+	s := &Storage{Employees: emps.Init(), APIKeys: keys.Init(), Audit: trail.Init(), Salaries: salaries.Init(), Versions: versions.Init(),
+		Departments: departments.Init(), Positions: positions.Init(), cfg: cfg}
+	if cfg.Backend == config.StorageFile {
+		loadEmps, loadKeys, loadAudit := s.Employees.StartLoad(), s.APIKeys.StartLoad(), s.Audit.StartLoad()
+		loadSalaries, loadVersions := s.Salaries.StartLoad(), s.Versions.StartLoad()
+		loadDepartments, loadPositions := s.Departments.StartLoad(), s.Positions.StartLoad()
+		s.loaders = []func() error{
+			func() error { return wrap("employees", loadEmps(filepath.Join(cfg.Path, "employees.journal"))) },
+			func() error { return wrap("api keys", loadKeys(filepath.Join(cfg.Path, "apikeys.journal"))) },
+			func() error { return wrap("audit", loadAudit(filepath.Join(cfg.Path, "audit.journal"))) },
+			func() error { return wrap("salaries", loadSalaries(filepath.Join(cfg.Path, "salaries.journal"))) },
+			func() error { return wrap("versions", loadVersions(filepath.Join(cfg.Path, "versions.journal"))) },
+			func() error {
+				return wrap("departments", loadDepartments(filepath.Join(cfg.Path, "departments.journal")))
+			},
+			func() error { return wrap("positions", loadPositions(filepath.Join(cfg.Path, "positions.journal"))) },
+		}
+	}
+	return s
+}
+
+// Open creates the databases of the configured backend and loads them. The file
+// backend keeps one journal per database in cfg.Path.
+func Open(cfg config.StorageConfig) (*Storage, error) {
+	s := New(cfg)
+	return s, s.Load()
+}
+
+// Load replays the journals of the file backend. It is a no-op for the memory backend.
+func (s *Storage) Load() error {
+	var errs []error
+	if len(s.loaders) > 0 {
+		if err := os.MkdirAll(s.cfg.Path, 0o700); err != nil {
+			errs = append(errs, fmt.Errorf("create storage directory: %w", err))
+		}
+	}
+	for _, load := range s.loaders {
+		if len(errs) > 0 {
+			// Release the blocked databases, they are closed by the failed load
+			load()
+			continue
+		}
+		if err := load(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.loaded = true
+	s.loadErr = errors.Join(errs...)
+	return s.loadErr
+}
+
+// Check reports whether the storage has finished loading and every database is usable.
+func (s *Storage) Check(ctx context.Context) error {
+	s.mutex.Lock()
+	loaded, loadErr := s.loaded, s.loadErr
+	s.mutex.Unlock()
+	if !loaded {
+		return errNotLoaded
+	}
+	if loadErr != nil {
+		return loadErr
+	}
+	return errors.Join(wrap("employees", s.Employees.Check(ctx)), wrap("api keys", s.APIKeys.Check(ctx)), wrap("audit", s.Audit.Check(ctx)),
+		wrap("salaries", s.Salaries.Check(ctx)), wrap("versions", s.Versions.Check(ctx)),
+		wrap("departments", s.Departments.Check(ctx)), wrap("positions", s.Positions.Check(ctx)))
+}
+
+// Alive reports whether every database lock can be acquired before ctx is done.
+// Unlike Check it ignores loading and journal failures, which a restart does not fix.
+func (s *Storage) Alive(ctx context.Context) error {
+	for _, err := range []error{s.Employees.Check(ctx), s.APIKeys.Check(ctx), s.Audit.Check(ctx), s.Salaries.Check(ctx), s.Versions.Check(ctx), s.Departments.Check(ctx), s.Positions.Check(ctx)} {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close flushes and closes every database.
+func (s *Storage) Close() error {
+	return errors.Join(s.Employees.Close(), s.APIKeys.Close(), s.Audit.Close(), s.Salaries.Close(), s.Versions.Close(), s.Departments.Close(), s.Positions.Close())
+}
+
+func wrap(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", name, err)
+}